@@ -0,0 +1,64 @@
+/**
+ * @fileoverview Separate admin listener for operational endpoints.
+ * When an admin address is configured, health, readiness, metrics, debug, fleet, and
+ * /admin/* endpoints are served only there, so they can be kept off the public port,
+ * along with the pprof profiling endpoints under /debug/pprof/.
+ * Admin requests bypass the application's concurrency limit and deadline budget so
+ * probes keep answering while the server is saturated.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/routes"
+)
+
+// AdminReadHeaderTimeout bounds how long the admin listener waits for request headers
+const AdminReadHeaderTimeout = 10 * time.Second
+
+/**
+ * @description Binds address with server's bind host and address family, retrying while it
+ * is in use, and serves handler on it in the background. Returns an error when the address cannot be bound.
+ */
+func startAdminServer(server config.ServerConfig, address string, handler http.Handler) (*http.Server, error) {
+	listener, err := bindSecondary(context.Background(), server, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind admin address %s: %w", address, err)
+	}
+
+	adminServer := &http.Server{
+		Addr:              listener.Addr().String(),
+		Handler:           handler,
+		ReadHeaderTimeout: AdminReadHeaderTimeout,
+		ErrorLog:          log.New(os.Stderr, "ADMIN: ", log.LstdFlags),
+	}
+	go func() {
+		if err := adminServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("⚠️ Admin listener on %s stopped: %v", adminServer.Addr, err)
+		}
+	}()
+
+	fmt.Printf("✅ Admin endpoints served on %s\n", adminServer.Addr)
+	return adminServer, nil
+}
+
+/**
+ * @description Registers the runtime profiling endpoints under /debug/pprof/.
+ */
+func registerProfiling(scope *routes.Scope) {
+	scope.HandleFunc("/debug/pprof/", pprof.Index)
+	scope.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	scope.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	scope.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	scope.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
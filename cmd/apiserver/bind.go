@@ -0,0 +1,213 @@
+/**
+ * @fileoverview Bind address selection for the API server.
+ * Candidates come from server.bind_addresses (or server.port) and are tried strictly in order; the first
+ * address that binds is used, and the listener is kept open so it cannot be lost between
+ * the check and the bind. When none bind, the error lists every address tried and why.
+ * Candidates without a host use server.bind_host, and every bind, including the admin,
+ * gRPC, and HTTP/3 listeners, uses the network for server.ip_family, so availability is
+ * always checked in the family actually served.
+ * Binding is retried only while every candidate is in use; serving is never retried.
+ * The bound listener applies the configured connection limits and TCP options.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/connlimit"
+)
+
+// BindAttempt records a failed bind
+type BindAttempt struct {
+	Address string
+	Err     error
+}
+
+// BindError reports that no candidate address could be bound
+type BindError struct {
+	Attempts []BindAttempt
+}
+
+func (e *BindError) Error() string {
+	return "no bind address available, tried: " + e.tried()
+}
+
+// tried lists each attempted address with its failure
+func (e *BindError) tried() string {
+	tried := make([]string, len(e.Attempts))
+	for i, attempt := range e.Attempts {
+		tried[i] = fmt.Sprintf("%s (%v)", attempt.Address, attempt.Err)
+	}
+	return strings.Join(tried, ", ")
+}
+
+/**
+ * @description Returns the ordered bind candidates from the configured bind addresses, or the
+ * single port address. Entries may be host:port, :port, or a bare port number; those
+ * without a host are bound on the configured bind host.
+ */
+func getBindCandidates(server config.ServerConfig) ([]string, error) {
+	entries := server.BindAddresses
+	if len(entries) == 0 {
+		entries = []string{server.Port}
+	}
+
+	var candidates []string
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, ":") {
+			entry = ":" + entry
+		}
+		host, port, err := net.SplitHostPort(entry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bind address %q: %w", entry, err)
+		}
+		if portNum, err := strconv.Atoi(port); err != nil || portNum < 0 || portNum > 65535 {
+			return nil, fmt.Errorf("invalid port in bind address %q", entry)
+		}
+		if host == "" && server.BindHost != "" {
+			entry = net.JoinHostPort(server.BindHost, port)
+		}
+		candidates = append(candidates, entry)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no bind addresses configured")
+	}
+	return candidates, nil
+}
+
+/**
+ * @description Binds a secondary listener, such as admin or gRPC, on address through the same
+ * path as the main listener: on server's bind host when address names none, in server's
+ * address family, and retrying while the address is in use.
+ */
+func bindSecondary(ctx context.Context, server config.ServerConfig, address string) (net.Listener, error) {
+	candidates, err := getBindCandidates(config.ServerConfig{BindAddresses: []string{address}, BindHost: server.BindHost})
+	if err != nil {
+		return nil, err
+	}
+	return bindWithRetries(ctx, bindNetwork("tcp", server.IPFamily), candidates)
+}
+
+/**
+ * @description Returns base, "tcp" or "udp", restricted to family. Wildcard addresses on
+ * the IPv6-only network do not accept IPv4 connections, while dual binds both.
+ */
+func bindNetwork(base, family string) string {
+	switch family {
+	case config.IPFamilyIPv4:
+		return base + "4"
+	case config.IPFamilyIPv6:
+		return base + "6"
+	default:
+		return base
+	}
+}
+
+/**
+ * @description Binds the first available candidate on network, returning a *BindError listing every failure otherwise.
+ */
+func listenFirstAvailable(network string, candidates []string) (net.Listener, error) {
+	bindErr := &BindError{}
+	for _, address := range candidates {
+		listener, err := net.Listen(network, address)
+		if err == nil {
+			if len(bindErr.Attempts) > 0 {
+				fmt.Printf("⚠️ Falling back to %s, unavailable: %s\n", listener.Addr(), bindErr.tried())
+			}
+			return listener, nil
+		}
+		bindErr.Attempts = append(bindErr.Attempts, BindAttempt{Address: address, Err: err})
+	}
+	return nil, bindErr
+}
+
+/**
+ * @description Binds the first available candidate, retrying with exponential backoff and jitter
+ * while every candidate is in use, e.g. during a rolling restart. Other bind errors fail
+ * immediately; cancelling ctx stops the retries.
+ */
+func bindWithRetries(ctx context.Context, network string, candidates []string) (net.Listener, error) {
+	delay := BindRetryBaseDelay
+	for attempt := 1; ; attempt++ {
+		listener, err := listenFirstAvailable(network, candidates)
+		if err == nil {
+			return listener, nil
+		}
+		if attempt >= MaxBindAttempts || !addressInUse(err) {
+			return nil, err
+		}
+
+		// Wait between half and all of the current delay so restarted replicas do not retry in lockstep
+		wait := delay/2 + rand.N(delay/2+1)
+		fmt.Printf("⚠️ Bind attempt %d/%d failed: %v. Retrying in %v...\n", attempt, MaxBindAttempts, err, wait.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		delay = min(delay*2, BindRetryMaxDelay)
+	}
+}
+
+// addressInUse reports whether a bind failed only because candidates were already in use
+func addressInUse(err error) bool {
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		return false
+	}
+	for _, attempt := range bindErr.Attempts {
+		if !errors.Is(attempt.Err, syscall.EADDRINUSE) {
+			return false
+		}
+	}
+	return len(bindErr.Attempts) > 0
+}
+
+/**
+ * @description Wraps listener with the configured connection limits and TCP options, or
+ * returns it unchanged when every setting is at the system default.
+ */
+func limitListener(listener net.Listener, server config.ServerConfig) net.Listener {
+	if server.MaxConnections == 0 && server.MaxConnectionsPerHost == 0 && server.TCPNoDelay && server.TCPKeepAlive == 0 {
+		return listener
+	}
+
+	limitConfig := connlimit.Config{
+		MaxConnections: server.MaxConnections,
+		MaxPerHost:     server.MaxConnectionsPerHost,
+		NoDelay:        &server.TCPNoDelay,
+		KeepAlive:      time.Duration(server.TCPKeepAlive),
+	}
+	if server.MaxConnections > 0 {
+		fmt.Printf("✅ Connection limit set to %d\n", server.MaxConnections)
+	}
+	if server.MaxConnectionsPerHost > 0 {
+		fmt.Printf("✅ Per-client connection limit set to %d\n", server.MaxConnectionsPerHost)
+	}
+	return connlimit.NewListener(listener, limitConfig)
+}
+
+/**
+ * @description Serves on listener, using HTTPS when server.TLSConfig is set.
+ */
+func serveListener(server *http.Server, listener net.Listener) error {
+	if server.TLSConfig != nil {
+		return server.ServeTLS(listener, "", "")
+	}
+	return server.Serve(listener)
+}
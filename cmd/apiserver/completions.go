@@ -0,0 +1,186 @@
+/**
+ * @fileoverview Completion endpoints backed by the configured LLM provider.
+ * POST /v1/completions answers with the full completion and POST /v1/completions/stream
+ * sends it incrementally as Server-Sent Events. Provider failures map to 429, 502, or 504
+ * so clients can tell retryable errors from invalid requests. Completions are recorded per
+ * caller with their estimated cost, optionally reported in the X-LLM-Cost-USD header.
+ * With a circuit breaker configured, answers from fallbacks while the provider is down are
+ * flagged as degraded in the response envelope.
+ */
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/dedupe"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/id"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/llm"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/routes"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/streams"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/validate"
+)
+
+// CostHeader reports a completion's estimated cost in USD when cost headers are enabled
+const CostHeader = "X-LLM-Cost-USD"
+
+// completionMessage is one conversation turn in a completion request
+type completionMessage struct {
+	Role    string `json:"role" validate:"required,enum=system|user|assistant"`
+	Content string `json:"content" validate:"required"`
+}
+
+// completionRequest is the body of both completion endpoints
+type completionRequest struct {
+	Model       string              `json:"model" validate:"max=128"`
+	Messages    []completionMessage `json:"messages" validate:"required,min=1"`
+	MaxTokens   int                 `json:"max_tokens" validate:"min=0"`
+	Temperature float64             `json:"temperature" validate:"min=0,max=2"`
+}
+
+// completionResponse is the response envelope of POST /v1/completions
+type completionResponse struct {
+	ID           string    `json:"id"`
+	Provider     string    `json:"provider"`
+	Model        string    `json:"model"`
+	Content      string    `json:"content"`
+	FinishReason string    `json:"finish_reason"`
+	Usage        llm.Usage `json:"usage"`
+	// Degraded is set when the provider's breaker is open and Fallback produced the answer
+	Degraded bool   `json:"degraded"`
+	Fallback string `json:"fallback,omitempty"`
+}
+
+/**
+ * @description Creates the provider named by the llm settings, behind a circuit breaker with
+ * fallbacks when one is configured, or returns nil when no provider is configured.
+ */
+func newLLMProvider(settings config.LLMConfig) (llm.Provider, error) {
+	if settings.Provider == "" {
+		return nil, nil
+	}
+	options, err := settings.ProviderOptions()
+	if err != nil {
+		return nil, err
+	}
+	provider, err := llm.New(settings.Provider, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create LLM provider: %w", err)
+	}
+	fmt.Printf("✅ LLM provider %s selected\n", provider.Name())
+	if settings.BreakerFailures > 0 {
+		breaker := llm.NewBreaker(provider.Name(), settings.BreakerFailures, time.Duration(settings.BreakerCooldown))
+		provider = llm.NewFallbackProvider(provider, llm.FallbackConfig{
+			Breaker: breaker,
+			Cache:   settings.FallbackCache,
+			Model:   settings.FallbackModel,
+			Message: settings.FallbackMessage,
+		})
+		fmt.Printf("✅ LLM circuit breaker opens after %d consecutive failures\n", settings.BreakerFailures)
+	}
+	return provider, nil
+}
+
+/**
+ * @description Registers POST /v1/completions and POST /v1/completions/stream on the scope.
+ * Requests without a model use the configured model; streams are tracked and exempt from
+ * timeouts. Complete responses are recorded in usage, since streams report no token usage.
+ */
+func registerCompletions(scope *routes.Scope, provider llm.Provider, settings config.LLMConfig, tracker *streams.Tracker, usage *llm.UsageTracker) {
+	decode := func(w http.ResponseWriter, r *http.Request) (llm.Request, bool) {
+		var body completionRequest
+		if err := validate.Decode(r, &body); err != nil {
+			validate.WriteError(w, r, err)
+			return llm.Request{}, false
+		}
+		request := llm.Request{Model: body.Model, MaxTokens: body.MaxTokens, Temperature: body.Temperature}
+		if request.Model == "" {
+			request.Model = settings.Model
+		}
+		for _, message := range body.Messages {
+			request.Messages = append(request.Messages, llm.Message{Role: message.Role, Content: message.Content})
+		}
+		return request, true
+	}
+
+	scope.HandleFunc("POST /v1/completions", withErrorHandling(func(w http.ResponseWriter, r *http.Request) {
+		request, ok := decode(w, r)
+		if !ok {
+			return
+		}
+		response, err := provider.Complete(r.Context(), request)
+		if err != nil {
+			writeProviderError(w, r, err)
+			return
+		}
+		record := usage.Record(dedupe.ScopeByCredentials(r), provider.Name(), response.Model, response.Usage)
+		if settings.CostHeaders && record.Priced {
+			w.Header().Set(CostHeader, strconv.FormatFloat(record.CostUSD, 'f', -1, 64))
+		}
+		jsoncase.Write(w, http.StatusOK, completionResponse{
+			ID:           id.New(),
+			Provider:     provider.Name(),
+			Model:        response.Model,
+			Content:      response.Content,
+			FinishReason: response.FinishReason,
+			Usage:        response.Usage,
+			Degraded:     response.Fallback != "",
+			Fallback:     response.Fallback,
+		})
+	}))
+
+	exemptStream("POST /v1/completions/stream")
+	scope.Handle("POST /v1/completions/stream", tracker.Middleware("/v1/completions/stream", http.HandlerFunc(withErrorHandling(func(w http.ResponseWriter, r *http.Request) {
+		request, ok := decode(w, r)
+		if !ok {
+			return
+		}
+		var sse *httputil.SSEWriter
+		err := provider.Stream(r.Context(), request, func(chunk llm.Chunk) error {
+			// Open the stream on the first chunk so failures before any output get a plain error response
+			if sse == nil {
+				var err error
+				if sse, err = httputil.NewSSEWriter(w, r); err != nil {
+					return err
+				}
+			}
+			return sse.SendJSON("", "chunk", chunk)
+		})
+		switch {
+		case err == nil:
+		case sse == nil:
+			writeProviderError(w, r, err)
+		default:
+			sse.SendJSON("", "error", map[string]string{"error": err.Error()})
+		}
+	}))))
+}
+
+// writeProviderError answers a failed completion: 429 when rate limited, 504 on provider
+// timeouts, 400 for requests the provider rejected, and 502 otherwise
+func writeProviderError(w http.ResponseWriter, r *http.Request, err error) {
+	var providerErr *llm.ProviderError
+	if !errors.As(err, &providerErr) {
+		apierror.Write(w, r, http.StatusBadGateway, "completion failed")
+		return
+	}
+	status := http.StatusBadGateway
+	switch providerErr.Kind {
+	case llm.ErrorRateLimited:
+		status = http.StatusTooManyRequests
+		w.Header().Set("Retry-After", "1")
+	case llm.ErrorTimeout:
+		status = http.StatusGatewayTimeout
+	case llm.ErrorInvalid:
+		status = http.StatusBadRequest
+	}
+	apierror.Write(w, r, status, providerErr.Error())
+}
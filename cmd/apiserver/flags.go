@@ -0,0 +1,198 @@
+/**
+ * @fileoverview Command-line flags for the API server.
+ * Flags take precedence over environment variables, which take precedence over the
+ * configuration file; settings without a flag come from the file and environment.
+ * Also implements the "apiserver state" command that exports and imports state bundles
+ * through a running server's admin endpoint.
+ */
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/buildinfo"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/state"
+)
+
+// StateTransferTimeout bounds exporting or importing a state bundle through a running server
+const StateTransferTimeout = time.Minute
+
+// errVersionRequested is returned by loadConfig after --version printed the version
+var errVersionRequested = errors.New("version requested")
+
+/**
+ * @description Parses the server's command-line flags and loads the configuration they select,
+ * applying only the flags that were given. Returns flag.ErrHelp after printing usage for -h/--help,
+ * and errVersionRequested after printing the version for --version.
+ */
+func loadConfig(args []string) (config.Config, error) {
+	flags := flag.NewFlagSet("apiserver", flag.ContinueOnError)
+	configFile := flags.String("config", os.Getenv(ConfigFileEnv), "YAML, JSON, or TOML configuration `file` (env "+ConfigFileEnv+")")
+	port := flags.String("port", "", "HTTP server `port` (env PORT, default 8080)")
+	logLevel := flags.String("log-level", "", "log `level`: debug, info, warn, or error (env LOG_LEVEL)")
+	shutdownTimeout := flags.Duration("shutdown-timeout", 0, "graceful shutdown `timeout` such as 30s (env SHUTDOWN_TIMEOUT)")
+	version := flags.Bool("version", false, "print the version and build metadata, then exit")
+	flags.Usage = func() {
+		out := flags.Output()
+		fmt.Fprintln(out, "Usage:")
+		fmt.Fprintln(out, "  apiserver [flags]")
+		fmt.Fprintln(out, "  apiserver support-bundle [-url URL] [-o file] [-token token]")
+		fmt.Fprintln(out, "  apiserver state export [-url URL] [-o file] [-sections a,b] [-token token]")
+		fmt.Fprintln(out, "  apiserver state import [-url URL] [-dry-run] [-token token] file")
+		fmt.Fprintln(out, "\nFlags:")
+		flags.PrintDefaults()
+		fmt.Fprintln(out, "\nFlags override environment variables, which override the configuration file.")
+	}
+	if err := flags.Parse(args); err != nil {
+		return config.Config{}, err
+	}
+	if *version {
+		fmt.Println("apiserver " + buildinfo.Get().String())
+		return config.Config{}, errVersionRequested
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		return config.Config{}, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	var overrides []func(*config.Config)
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			overrides = append(overrides, func(c *config.Config) { c.Server.Port = *port })
+		case "log-level":
+			overrides = append(overrides, func(c *config.Config) { c.Logging.Level = *logLevel })
+		case "shutdown-timeout":
+			overrides = append(overrides, func(c *config.Config) { c.Server.ShutdownTimeout = config.Duration(*shutdownTimeout) })
+		}
+	})
+	return config.Load(*configFile, overrides...)
+}
+
+/**
+ * @description Runs the state command: "export" downloads a state bundle from a running
+ * server and "import" uploads one to it, authenticating with -token or the configured admin token.
+ */
+func runState(args []string) error {
+	if len(args) == 0 || (args[0] != "export" && args[0] != "import") {
+		return errors.New("usage: apiserver state export|import [flags]")
+	}
+	command := args[0]
+	cfg, err := config.Load(os.Getenv(ConfigFileEnv))
+	if err != nil {
+		return err
+	}
+	// Bundles and results are encoded in the server's casing
+	style, err := jsoncase.ParseStyle(cfg.API.JSONFieldCase)
+	if err != nil {
+		return err
+	}
+	jsoncase.SetDefault(style)
+
+	flags := flag.NewFlagSet("state "+command, flag.ContinueOnError)
+	serverURL := flags.String("url", "http://localhost:"+cfg.Server.Port, "base URL of the running server")
+	token := flags.String("token", cfg.Admin.Token, "admin bearer token")
+	output := flags.String("o", "state-bundle-"+time.Now().UTC().Format("20060102T150405Z")+".json", "output file (export)")
+	sections := flags.String("sections", "", "comma-separated sections to export (default: all)")
+	dryRun := flags.Bool("dry-run", false, "validate the bundle without applying it (import)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *token == "" {
+		return fmt.Errorf("an admin token is required (-token or ADMIN_TOKEN)")
+	}
+
+	query := url.Values{}
+	var body io.Reader
+	method := http.MethodGet
+	if command == "export" {
+		for _, section := range strings.Split(*sections, ",") {
+			if section = strings.TrimSpace(section); section != "" {
+				query.Add("section", section)
+			}
+		}
+	} else {
+		if flags.NArg() != 1 {
+			return errors.New("usage: apiserver state import [flags] file")
+		}
+		file, err := os.Open(flags.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		body, method = file, http.MethodPost
+		if *dryRun {
+			query.Set("dry_run", "true")
+		}
+	}
+
+	req, err := http.NewRequest(method, *serverURL+"/admin/state?"+query.Encode(), body)
+	if err != nil {
+		return fmt.Errorf("invalid server URL: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+*token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Timeout: StateTransferTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to %s state: %w", command, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var problem struct {
+			Detail string `json:"detail"`
+		}
+		if jsoncase.Decode(resp.Body, &problem) != nil || problem.Detail == "" {
+			problem.Detail = "server returned " + resp.Status
+		}
+		return fmt.Errorf("failed to %s state: %s", command, problem.Detail)
+	}
+
+	if command == "export" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *output, err)
+		}
+		if _, err := io.Copy(file, resp.Body); err != nil {
+			file.Close()
+			return fmt.Errorf("failed to write %s: %w", *output, err)
+		}
+		if err := file.Close(); err != nil {
+			return fmt.Errorf("failed to write %s: %w", *output, err)
+		}
+		fmt.Printf("✅ State bundle written to %s\n", *output)
+		return nil
+	}
+
+	var result state.ImportResult
+	if err := jsoncase.Decode(resp.Body, &result); err != nil {
+		return fmt.Errorf("failed to read the import result: %w", err)
+	}
+	verb := "Imported"
+	if result.DryRun {
+		verb = "Validated (dry run)"
+	}
+	for _, section := range slices.Sorted(maps.Keys(result.Sections)) {
+		fmt.Printf("✅ %s %s: %d items\n", verb, section, result.Sections[section])
+	}
+	if len(result.Skipped) > 0 {
+		fmt.Printf("⚠️ Skipped sections this server does not have: %s\n", strings.Join(result.Skipped, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/**
+ * @fileoverview Embedded gRPC server sharing the application's lifecycle.
+ * gRPC is served on its own address or multiplexed onto the HTTP listener. Its health
+ * service reports NOT_SERVING once shutdown starts, and in-flight RPCs are drained
+ * within the shutdown timeout.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/grpcserver"
+)
+
+/**
+ * @description Creates the gRPC server when enabled and, given its own address, binds it and
+ * serves in the background. Returns nil when gRPC is disabled.
+ */
+func startGRPCServer(cfg config.GRPCConfig, server config.ServerConfig) (*grpcserver.Server, error) {
+	if cfg.Address == "" && !cfg.Multiplex {
+		return nil, nil
+	}
+
+	rpc := grpcserver.New()
+	if cfg.Multiplex {
+		fmt.Println("✅ gRPC multiplexed onto the HTTP listener")
+		return rpc, nil
+	}
+
+	listener, err := bindSecondary(context.Background(), server, cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind gRPC address %s: %w", cfg.Address, err)
+	}
+	fmt.Printf("✅ gRPC server listening on %s\n", listener.Addr())
+	lifecycle.Go("grpc-server", func(context.Context) error {
+		return rpc.Serve(listener)
+	})
+	return rpc, nil
+}
+
+/**
+ * @description Serves HTTP on listener, sharing it with gRPC when rpc is set.
+ */
+func serveHTTPAndGRPC(server *http.Server, rpc *grpcserver.Server, listener net.Listener) error {
+	if rpc == nil {
+		return serveListener(server, listener)
+	}
+	return rpc.ServeMultiplexed(listener, func(httpListener net.Listener) error {
+		return serveListener(server, httpListener)
+	})
+}
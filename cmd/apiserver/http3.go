@@ -0,0 +1,85 @@
+/**
+ * @fileoverview Experimental HTTP/3 listener sharing the HTTP server's handlers.
+ * When enabled, requests are also served over QUIC on a UDP address, by default the
+ * one the TCP listener bound. Responses over TCP carry an Alt-Svc header so clients
+ * can upgrade, and the QUIC listener drains alongside the HTTP server on shutdown.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+)
+
+// http3Server serves the application over QUIC; nil unless HTTP/3 is enabled
+var http3Server *http3.Server
+
+/**
+ * @description Creates the HTTP/3 server from server's handler and TLS configuration and
+ * wraps server's handler so TCP responses advertise it.
+ */
+func enableHTTP3(server *http.Server) *http3.Server {
+	h3 := &http3.Server{
+		Handler:        server.Handler,
+		TLSConfig:      server.TLSConfig,
+		MaxHeaderBytes: server.MaxHeaderBytes,
+		IdleTimeout:    server.IdleTimeout,
+		// A non-nil config leaves 0-RTT off, since early data can be replayed
+		QUICConfig: &quic.Config{},
+	}
+	server.Handler = advertiseHTTP3(h3, server.Handler)
+	return h3
+}
+
+// advertiseHTTP3 adds the Alt-Svc header to responses not already served over HTTP/3
+func advertiseHTTP3(h3 *http3.Server, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor < 3 {
+			// Fails only before the QUIC listener is up, when there is nothing to advertise
+			h3.SetQUICHeaders(w.Header())
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+/**
+ * @description Binds the HTTP/3 UDP address, or tcpAddress when none is configured, in the
+ * server's address family and serves in the background until shutdown.
+ */
+func startHTTP3(h3 *http3.Server, server config.ServerConfig, tcpAddress string) error {
+	address := server.HTTP3Address
+	if address == "" {
+		address = tcpAddress
+	}
+	conn, err := net.ListenPacket(bindNetwork("udp", server.IPFamily), address)
+	if err != nil {
+		return fmt.Errorf("failed to bind HTTP/3 address %s: %w", address, err)
+	}
+	fmt.Printf("✅ HTTP/3 (experimental) listening on udp %s\n", conn.LocalAddr())
+
+	lifecycle.Go("http3-server", func(context.Context) error {
+		defer conn.Close()
+		if err := h3.Serve(conn); !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("HTTP/3 server stopped unexpectedly: %w", err)
+		}
+		return nil
+	})
+	return nil
+}
+
+// shutdownHTTP3 drains HTTP/3 connections until ctx ends, then closes them
+func shutdownHTTP3(ctx context.Context) error {
+	if http3Server == nil {
+		return nil
+	}
+	return http3Server.Shutdown(ctx)
+}
@@ -0,0 +1,98 @@
+/**
+ * @fileoverview Inbound webhook and job submission endpoints.
+ * Webhook deliveries and job submissions are accepted with an ID and published on the
+ * event bus for /admin/events subscribers. Both are deduplicated by delivery ID header or
+ * body hash, so provider retries and client resubmissions are answered with the original
+ * acceptance instead of being published twice.
+ */
+
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/dedupe"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/events"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/id"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/routes"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/store"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/validate"
+)
+
+// jobRequest is the body of a job submission
+type jobRequest struct {
+	Type    string          `json:"type" validate:"required,max=64"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// intakeEvent is published on the bus for each accepted delivery or submission
+type intakeEvent struct {
+	ID     string `json:"id"`
+	Source string `json:"source,omitempty"`
+	Type   string `json:"type,omitempty"`
+	// Payload is the request body when it is JSON
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// intakeResponse acknowledges an accepted delivery or submission
+type intakeResponse struct {
+	ID         string `json:"id"`
+	Status     string `json:"status"`
+	AcceptedAt string `json:"accepted_at"`
+}
+
+/**
+ * @description Registers POST /webhooks/{source} and POST /jobs on the scope, each behind
+ * its own deduper sharing the configured idempotency store.
+ */
+func registerIntake(scope *routes.Scope, bus *events.Bus, idempotency store.IdempotencyStore) {
+	dedupeConfig := dedupe.MiddlewareConfig{HashBody: true}
+	webhooks := dedupe.New(dedupe.Config{Store: idempotency, Namespace: "webhooks"})
+	jobs := dedupe.New(dedupe.Config{Store: idempotency, Namespace: "jobs"})
+	scope.Handle("POST /webhooks/{source}", webhooks.Middleware(dedupeConfig, http.HandlerFunc(withErrorHandling(receiveWebhook(bus)))))
+	scope.Handle("POST /jobs", jobs.Middleware(dedupeConfig, http.HandlerFunc(withErrorHandling(submitJob(bus)))))
+}
+
+// receiveWebhook publishes each delivery as a webhook.received event
+func receiveWebhook(bus *events.Bus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			validate.WriteError(w, r, err)
+			return
+		}
+		event := intakeEvent{ID: id.New(), Source: r.PathValue("source")}
+		if json.Valid(body) {
+			event.Payload = body
+		}
+		bus.Publish(events.TypeWebhookReceived, event)
+		accept(w, event.ID)
+	}
+}
+
+// submitJob validates a submission and publishes it as a job.submitted event
+func submitJob(bus *events.Bus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var request jobRequest
+		if err := validate.Decode(r, &request); err != nil {
+			validate.WriteError(w, r, err)
+			return
+		}
+		event := intakeEvent{ID: id.New(), Type: request.Type, Payload: request.Payload}
+		bus.Publish(events.TypeJobSubmitted, event)
+		accept(w, event.ID)
+	}
+}
+
+// accept answers 202 with the ID assigned to the delivery or submission
+func accept(w http.ResponseWriter, eventID string) {
+	jsoncase.Write(w, http.StatusAccepted, intakeResponse{
+		ID:         eventID,
+		Status:     "accepted",
+		AcceptedAt: time.Now().UTC().Format(time.RFC3339),
+	})
+}
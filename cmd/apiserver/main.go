@@ -8,31 +8,55 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/x509"
+	"database/sql"
+	"encoding/hex"
 	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
-	"net"
+	"log/slog"
 	"net/http"
 	"os"
-	"os/signal"
-	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/app"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/buildinfo"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/events"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/grpcserver"
 	"github.com/ashleywang1/new-ai-project-tutorial/pkg/health"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/id"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/llm"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/middleware"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/store"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/tlsutil"
 )
 
 const (
-	// DefaultPort is the default HTTP server port
-	DefaultPort = "8080"
-	// ShutdownTimeout defines how long to wait for graceful shutdown
-	ShutdownTimeout = 30 * time.Second
-	// StartupTimeout defines how long to wait for server to start
-	StartupTimeout = 10 * time.Second
-	// MaxRetries defines maximum startup retry attempts
-	MaxRetries = 3
-	// RetryDelay defines delay between startup retries
-	RetryDelay = 2 * time.Second
+	// ConfigFileEnv names the optional YAML, JSON, or TOML configuration file
+	ConfigFileEnv = "CONFIG_FILE"
+	// ProdProfile disables development-only features such as debug endpoints
+	ProdProfile = "prod"
+	// MaxBindAttempts bounds how many times binding is tried while the address is in use
+	MaxBindAttempts = 5
+	// BindRetryBaseDelay is the delay before the second bind attempt, doubled after each retry
+	BindRetryBaseDelay = 500 * time.Millisecond
+	// BindRetryMaxDelay caps the delay between bind attempts
+	BindRetryMaxDelay = 5 * time.Second
+	// TrustAnchorExpiryWarning is how early the CA bundle check reports degraded before expiry
+	TrustAnchorExpiryWarning = 30 * 24 * time.Hour
+	// PriorityQueueFactor sizes the priority wait queue as a multiple of the concurrency limit
+	PriorityQueueFactor = 2
+	// PriorityQueueTimeout bounds how long a request waits for a concurrency slot
+	PriorityQueueTimeout = 5 * time.Second
+	// VectorStoreTimeout bounds each request to the vector store made by health checks
+	VectorStoreTimeout = 5 * time.Second
 )
 
 // ServerError represents application-specific errors
@@ -49,265 +73,380 @@ func (e *ServerError) Error() string {
 	return e.Message
 }
 
+// lifecycle starts the server's components in order and coordinates shutdown
+var lifecycle = app.New()
+
+// outboundCalls records failed outbound HTTP calls so /admin/health/diagnose can correlate them
+var outboundCalls = httputil.NewOutboundRecorder(httputil.DefaultOutboundErrorHistory)
+
 /**
- * @description Main function that initializes and starts the HTTP server.
- * Sets up health endpoints and handles graceful shutdown on termination signals.
- * Includes comprehensive error handling and startup retry logic.
+ * @description Main function that declares the server's components and runs them.
+ * Components start in order (config, logging, stores, retention, LLM provider, health, gRPC server, HTTP server) and stop in
+ * reverse on a termination signal or when serving fails.
  */
 func main() {
-	fmt.Println("AI Project Tutorial API Server - Phase 0")
+	if len(os.Args) > 1 && os.Args[1] == "support-bundle" {
+		if err := runSupportBundle(os.Args[2:]); err != nil {
+			log.Fatalf("Support bundle failed: %v", err)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "state" {
+		if err := runState(os.Args[2:]); err != nil {
+			log.Fatalf("State command failed: %v", err)
+		}
+		return
+	}
+
+	var (
+		cfg           config.Config
+		stores        *store.Stores
+		storeDB       *sql.DB
+		provider      llm.Provider
+		healthChecker *health.HealthChecker
+		server        *http.Server
+		rpc           *grpcserver.Server
+	)
+
+	// Publish lifecycle events for the admin event stream
+	bus := events.NewBus(events.DefaultReplaySize)
+	// Record applied configuration reloads for /admin/config/history
+	configHistory := config.NewHistory(config.DefaultHistorySize)
+
+	// Layer defaults, the optional config file, environment, and command-line flags
+	lifecycle.Add(app.Component{Name: "config", Start: func(context.Context) error {
+		var err error
+		cfg, err = loadConfig(os.Args[1:])
+		if err == nil {
+			fmt.Println("AI Project Tutorial API Server - Phase 0")
+		}
+		return err
+	}})
+
+	// Apply logging settings, keeping recent output for support bundles, then validate
+	lifecycle.Add(app.Component{Name: "logging", Start: func(context.Context) error {
+		if err := setupLogging(cfg.Logging, io.MultiWriter(os.Stderr, recentLogs)); err != nil {
+			return err
+		}
+		return validateConfiguration(cfg)
+	}})
+
+	// Open the shared stores, closed once the HTTP server has drained
+	lifecycle.Add(app.Component{
+		Name: "stores",
+		Start: func(ctx context.Context) error {
+			var err error
+			storeConfig := store.Config{
+				Backend:       cfg.Store.Backend,
+				KeyPrefix:     cfg.Store.KeyPrefix,
+				RedisAddr:     cfg.Store.RedisAddr,
+				RedisPassword: cfg.Store.RedisPassword,
+				RedisDB:       cfg.Store.RedisDB,
+				SQLDialect:    cfg.Store.SQLDialect,
+			}
+			if cfg.Store.Backend == store.BackendSQL {
+				if storeDB, err = openStoreDB(ctx, cfg.Store); err != nil {
+					return err
+				}
+				storeConfig.SQLDB = storeDB
+			}
+			if stores, err = store.New(ctx, storeConfig); err != nil && storeDB != nil {
+				storeDB.Close()
+			}
+			return err
+		},
+		Stop: func(context.Context) error {
+			err := stores.Close()
+			if storeDB != nil {
+				err = errors.Join(err, storeDB.Close())
+			}
+			return err
+		},
+	})
+
+	// Purge expired records on the configured schedule until shutdown
+	lifecycle.Add(app.Component{Name: "retention", Start: func(context.Context) error {
+		return startRetention(cfg.Retention, stores)
+	}})
+
+	// Select the completion provider, when one is configured
+	lifecycle.Add(app.Component{Name: "llm", Start: func(context.Context) error {
+		var err error
+		provider, err = newLLMProvider(cfg.LLM)
+		return err
+	}})
+
+	lifecycle.Add(app.Component{Name: "health", Start: func(context.Context) error {
+		var err error
+		healthChecker, err = newHealthChecker(cfg, bus, configHistory)
+		return err
+	}})
+
+	// Serve gRPC when enabled; it stops after the HTTP server, draining in-flight RPCs
+	lifecycle.Add(app.Component{
+		Name: "grpc-server",
+		Start: func(context.Context) error {
+			var err error
+			rpc, err = startGRPCServer(cfg.GRPC, cfg.Server)
+			return err
+		},
+		Stop: func(context.Context) error {
+			if rpc == nil {
+				return nil
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout))
+			defer cancel()
+			return rpc.Shutdown(ctx)
+		},
+	})
+
+	// Serve in the background; a serving failure shuts the application down
+	lifecycle.Add(app.Component{
+		Name: "http-server",
+		Start: func(context.Context) error {
+			var err error
+			server, err = createHTTPServerWithHealthChecker(cfg, healthChecker, bus, configHistory, stores, provider)
+			if err != nil {
+				return err
+			}
+			lifecycle.Go("http-server", func(ctx context.Context) error {
+				return startServerWithRetries(ctx, server, rpc, cfg.Server)
+			})
+			return nil
+		},
+		Stop: func(context.Context) error {
+			return performGracefulShutdown(server, time.Duration(cfg.Server.ShutdownTimeout))
+		},
+	})
+
+	// Announce shutdown on the event stream and to load balancers before draining. On SIGTERM,
+	// keep serving while readiness fails so load balancers stop routing here first.
+	lifecycle.OnStopping(func(reason string) {
+		bus.Publish(events.TypeShutdownStarted, map[string]string{"signal": reason})
+		if loadHints != nil {
+			loadHints.SetDraining(true)
+		}
+		if rpc != nil {
+			rpc.SetServing(false)
+		}
+		if healthChecker == nil {
+			return
+		}
+		healthChecker.SetShuttingDown(true)
+		if delay := time.Duration(cfg.Server.DrainDelay); delay > 0 && reason == syscall.SIGTERM.String() {
+			fmt.Printf("Readiness now failing; waiting %v for load balancers before draining...\n", delay)
+			time.Sleep(delay)
+		}
+	})
 
-	// Validate configuration
-	if err := validateConfiguration(); err != nil {
-		log.Fatalf("Configuration validation failed: %v", err)
+	err := lifecycle.Run(os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
+	if errors.Is(err, flag.ErrHelp) || errors.Is(err, errVersionRequested) {
+		return
+	}
+	if err != nil {
+		log.Printf("Server stopped with errors: %v", err)
+		os.Exit(1)
 	}
 
-	// Create health checker instance
+	fmt.Println("Server shutdown complete")
+}
+
+/**
+ * @description Creates the health checker with its readiness checks, the upstream trust
+ * store watch, and transition webhooks.
+ */
+func newHealthChecker(cfg config.Config, bus *events.Bus, configHistory *config.History) (*health.HealthChecker, error) {
+	build := buildinfo.Get()
 	healthChecker := health.NewHealthChecker(health.HealthCheckerConfig{
 		ServiceName:    "AI Project Tutorial API Server",
-		ServiceVersion: "0.1.0",
+		ServiceVersion: build.Version,
+		GitCommit:      build.GitCommit,
+		BuildDate:      build.BuildDate,
+		GoVersion:      build.GoVersion,
+		Logger:         slog.Default(),
+		// Zero values fall back to the health package defaults
+		SlowCheckThreshold: time.Duration(cfg.Health.SlowCheckThreshold),
+		HistorySize:        cfg.Health.HistorySize,
+		Outbound:           outboundCalls,
+	})
+
+	// Publish check transitions for the admin event stream
+	healthChecker.AddTransitionListener(func(event health.TransitionEvent) {
+		bus.Publish(events.TypeCheckTransitioned, event)
 	})
 
 	// Add basic readiness checks
 	healthChecker.AddReadinessCheck("handlers", health.AlwaysHealthyCheck())
 	healthChecker.AddReadinessCheck("server", health.AlwaysHealthyCheck())
 
-	// Create HTTP server with configured routes
-	server, err := createHTTPServerWithHealthChecker(healthChecker)
-	if err != nil {
-		log.Fatalf("Failed to create HTTP server: %v", err)
+	// Load the upstream trust store and warn before its anchors expire
+	if caBundle := cfg.TLS.UpstreamCABundle; caBundle != "" {
+		trustStore, err := tlsutil.NewTrustStore(caBundle, cfg.TLS.UpstreamClientCert, cfg.TLS.UpstreamClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load upstream trust store: %w", err)
+		}
+		anchors := certificateSnapshot(trustStore.Certificates())
+		trustStore.OnReload(func(err error) {
+			if err != nil {
+				log.Printf("Upstream trust store reload failed, keeping previous bundle: %v", err)
+				reload := configHistory.Record(caBundle, nil, nil, err)
+				bus.Publish(events.TypeConfigReloaded, reload)
+				return
+			}
+			current := certificateSnapshot(trustStore.Certificates())
+			reload := configHistory.Record(caBundle, []string{"upstream-trust-store"}, config.Diff(anchors, current), nil)
+			anchors = current
+			log.Printf("Upstream trust store reloaded from %s with %d changes", caBundle, len(reload.Changes))
+			for _, change := range reload.Changes {
+				log.Printf("  %s %s: %q -> %q", change.Kind, change.Key, change.Old, change.New)
+			}
+			bus.Publish(events.TypeConfigReloaded, reload)
+		})
+		lifecycle.Go("upstream-trust-store", func(ctx context.Context) error {
+			trustStore.Watch(ctx, tlsutil.DefaultWatchInterval)
+			return nil
+		})
+		healthChecker.AddReadinessCheck("upstream-ca-bundle",
+			health.CertificateExpiryCheck("upstream CA bundle", trustStore.Certificates, TrustAnchorExpiryWarning))
 	}
 
-	// Start server with retry logic in a goroutine
-	serverErrChan := make(chan error, 1)
-	go func() {
-		serverErrChan <- startServerWithRetries(server)
-	}()
-
-	// Setup graceful shutdown handling
-	shutdown := setupShutdownSignals()
-
-	// Wait for either server error or shutdown signal
-	select {
-	case err := <-serverErrChan:
+	// Refuse to start when any embedding model and its vector collection disagree on dimensions;
+	// an unreachable store only fails readiness, so the server can start before it
+	if embeddings := cfg.Embeddings; embeddings.QdrantURL != "" {
+		models, err := config.ParseEmbeddingModels(embeddings.Models)
 		if err != nil {
-			log.Fatalf("Server failed to start: %v", err)
+			return nil, err
 		}
-		// Server stopped gracefully
-	case sig := <-shutdown:
-		fmt.Printf("\nReceived signal: %v. Initiating graceful shutdown...\n", sig)
-		if err := performGracefulShutdown(server); err != nil {
-			log.Printf("Error during graceful shutdown: %v", err)
-			os.Exit(1)
+		var mismatches []error
+		for _, model := range models {
+			check := health.EmbeddingDimensionCheck(model.Model, model.Collection,
+				health.StaticDimensions(model.Dimensions),
+				health.QdrantCollectionDimensions(embeddings.QdrantURL, model.Collection, embeddings.QdrantAPIKey, VectorStoreTimeout))
+			switch err := check(); {
+			case errors.Is(err, health.ErrDimensionMismatch):
+				mismatches = append(mismatches, err)
+			case err != nil:
+				fmt.Printf("⚠️ Could not validate embedding dimensions of %s yet: %v\n", model.Model, err)
+			default:
+				fmt.Printf("✅ Embedding dimensions of %s (%d) match collection %s\n", model.Model, model.Dimensions, model.Collection)
+			}
+			healthChecker.AddReadinessCheck("embedding-dimensions:"+model.Model, check)
+		}
+		if len(mismatches) > 0 {
+			return nil, errors.Join(mismatches...)
 		}
 	}
 
-	fmt.Println("Server shutdown complete")
+	// Deliver check transitions to configured webhooks until shutdown completes
+	for _, webhookURL := range cfg.Health.WebhookURLs {
+		healthChecker.AddWebhook(health.WebhookConfig{
+			URL:       webhookURL,
+			Secret:    cfg.Health.WebhookSecret,
+			Transport: outboundCalls.Transport(nil),
+		})
+	}
+	if len(cfg.Health.WebhookURLs) > 0 {
+		lifecycle.Go("health-webhooks", func(ctx context.Context) error {
+			healthChecker.RunWebhooks(ctx)
+			return nil
+		})
+	}
+	return healthChecker, nil
 }
 
 /**
  * @description Validates application configuration before startup.
- * Checks port availability, environment variables, and system requirements.
+ * Applies process-wide settings and checks that bind addresses are well formed.
  */
-func validateConfiguration() error {
-	port := getPort()
-
-	// Validate port number
-	if portNum, err := strconv.Atoi(port); err != nil || portNum < 1 || portNum > 65535 {
+func validateConfiguration(cfg config.Config) error {
+	// Validate JSON field casing and apply it to all responses
+	style, err := jsoncase.ParseStyle(cfg.API.JSONFieldCase)
+	if err != nil {
 		return &ServerError{
-			Message: "Invalid port number",
+			Message: "Invalid JSON field casing",
 			Cause:   err,
 			Code:    400,
 		}
 	}
+	jsoncase.SetDefault(style)
 
-	// Check if port is available
-	if !isPortAvailable(port) {
+	// Validate the ID format used for request and resource identifiers
+	idFormat, err := id.ParseFormat(cfg.API.IDFormat)
+	if err != nil {
 		return &ServerError{
-			Message: fmt.Sprintf("Port %s is already in use", port),
-			Code:    409,
+			Message: "Invalid ID format",
+			Cause:   err,
+			Code:    400,
 		}
 	}
+	id.SetDefault(idFormat)
 
-	fmt.Printf("✅ Configuration validated - Port %s is available\n", port)
-	return nil
-}
-
-/**
- * @description Creates and configures the HTTP server with health checker.
- * Returns a configured http.Server with proper timeouts and error handling.
- */
-func createHTTPServerWithHealthChecker(healthChecker *health.HealthChecker) (*http.Server, error) {
-	mux := http.NewServeMux()
-
-	// Register health endpoints using the health checker
-	mux.HandleFunc("/health", withErrorHandling(healthChecker.HealthHandler))
-	mux.HandleFunc("/ready", withErrorHandling(healthChecker.ReadinessHandler))
-	mux.HandleFunc("/", withErrorHandling(handleRoot))
-
-	server := &http.Server{
-		Addr:         ":" + getPort(),
-		Handler:      mux,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
-		ErrorLog:     log.New(os.Stderr, "HTTP: ", log.LstdFlags),
+	// Believe forwarding headers only from trusted proxies when identifying clients
+	proxies, err := httputil.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		return &ServerError{
+			Message: "Invalid trusted proxies",
+			Cause:   err,
+			Code:    400,
+		}
 	}
+	httputil.SetTrustedProxies(proxies)
 
-	fmt.Println("✅ HTTP server configured successfully")
-	return server, nil
-}
-
-/**
- * @description Starts the server with retry logic for improved reliability.
- * Attempts to start the server multiple times with exponential backoff.
- */
-func startServerWithRetries(server *http.Server) error {
-	var lastErr error
-
-	for attempt := 1; attempt <= MaxRetries; attempt++ {
-		fmt.Printf("Starting server (attempt %d/%d) on %s...\n", attempt, MaxRetries, server.Addr)
-
-		// Start server - this will block until server stops or fails
-		fmt.Printf("✅ Server started successfully on %s\n", server.Addr)
-		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			lastErr = &ServerError{
-				Message: fmt.Sprintf("Server startup failed on attempt %d", attempt),
-				Cause:   err,
-				Code:    500,
-			}
-
-			if attempt < MaxRetries {
-				fmt.Printf("❌ Startup failed: %v. Retrying in %v...\n", err, RetryDelay)
-				time.Sleep(RetryDelay)
-				continue
-			}
-		} else {
-			// Server shutdown gracefully (ErrServerClosed)
-			fmt.Println("✅ Server shutdown gracefully")
-			return nil
+	// Validate bind candidates; availability is checked when the server binds, with retries
+	if _, err := getBindCandidates(cfg.Server); err != nil {
+		return &ServerError{
+			Message: "Invalid bind address",
+			Cause:   err,
+			Code:    400,
 		}
 	}
 
-	return lastErr
-}
-
-/**
- * @description Sets up signal handling for graceful shutdown.
- * Returns a channel that receives shutdown signals.
- */
-func setupShutdownSignals() <-chan os.Signal {
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM, syscall.SIGQUIT)
-	return signalChan
+	fmt.Println("✅ Configuration validated")
+	return nil
 }
 
 /**
- * @description Performs graceful shutdown of the HTTP server.
- * Handles connection draining and resource cleanup with timeout.
+ * @description Routes the standard logger, slog, and the access log through output at the
+ * configured level, encoding records as JSON when the json format is selected.
  */
-func performGracefulShutdown(server *http.Server) error {
-	fmt.Println("Initiating graceful shutdown...")
-
-	// Create context with timeout for shutdown
-	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
-	defer cancel()
-
-	// Channel to track shutdown completion
-	shutdownComplete := make(chan error, 1)
-
-	go func() {
-		shutdownComplete <- server.Shutdown(ctx)
-	}()
-
-	// Wait for shutdown completion or timeout
-	select {
-	case err := <-shutdownComplete:
-		if err != nil {
-			return &ServerError{
-				Message: "Error during server shutdown",
-				Cause:   err,
-				Code:    500,
-			}
-		}
-		fmt.Println("✅ Server shutdown completed successfully")
-		return nil
+func setupLogging(logging config.LoggingConfig, output io.Writer) error {
+	level, err := config.ParseLogLevel(logging.Level)
+	if err != nil {
+		return err
+	}
 
-	case <-ctx.Done():
-		// Force close if graceful shutdown times out
-		fmt.Println("⚠️ Graceful shutdown timed out, forcing server close...")
-		if err := server.Close(); err != nil {
-			return &ServerError{
-				Message: "Error during forced server close",
-				Cause:   err,
-				Code:    500,
-			}
-		}
-		return &ServerError{
-			Message: "Server shutdown timed out and was forced to close",
-			Code:    408,
+	accessFormat := logging.AccessLogFormat
+	if accessFormat == "" {
+		accessFormat = middleware.AccessLogCommon
+		if logging.Format == config.LogFormatJSON {
+			accessFormat = middleware.AccessLogJSON
 		}
 	}
-}
-
-/**
- * @description Middleware wrapper that adds error handling to HTTP handlers.
- * Provides consistent error logging and response formatting.
- */
-func withErrorHandling(handler func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		defer func() {
-			if err := recover(); err != nil {
-				log.Printf("Panic in handler %s: %v", r.URL.Path, err)
-				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
-			}
-		}()
-
-		// Log request
-		log.Printf("Request: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr)
-
-		// Call the actual handler
-		handler(w, r)
+	if err := accessLog.SetFormat(accessFormat); err != nil {
+		return err
 	}
-}
-
-/**
- * @description Root endpoint handler providing basic service information.
- * Returns service name and available endpoints with error handling.
- */
-func handleRoot(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	response := fmt.Sprintf(`{
-		"service": "AI Project Tutorial API Server",
-		"phase": "0",
-		"endpoints": ["/health", "/ready"],
-		"timestamp": "%s"
-	}`, time.Now().UTC().Format(time.RFC3339))
-	w.Write([]byte(response))
-}
+	accessLog.SetOutput(output)
+	accessLog.Exclude(logging.AccessLogExclude...)
 
-/**
- * @description Gets the server port from environment or returns default.
- * Checks PORT environment variable, defaults to 8080.
- */
-func getPort() string {
-	if port := os.Getenv("PORT"); port != "" {
-		return port
+	if logging.Format == config.LogFormatJSON {
+		slog.SetDefault(slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{Level: level})))
+		return nil
 	}
-	return DefaultPort
+	log.SetOutput(output)
+	slog.SetLogLoggerLevel(level)
+	return nil
 }
 
 /**
- * @description Checks if a port is available for binding.
- * Returns true if the port is available, false otherwise.
+ * @description Describes certificates as flattened configuration keyed by SHA-256 fingerprint,
+ * so trust store reloads can be diffed like any other configuration.
  */
-func isPortAvailable(port string) bool {
-	address := net.JoinHostPort("", port)
-	listener, err := net.Listen("tcp", address)
-	if err != nil {
-		return false
+func certificateSnapshot(certs []*x509.Certificate) map[string]string {
+	snapshot := make(map[string]string, len(certs))
+	for _, cert := range certs {
+		fingerprint := sha256.Sum256(cert.Raw)
+		snapshot["anchors."+hex.EncodeToString(fingerprint[:8])] = fmt.Sprintf("%s (expires %s)",
+			cert.Subject.CommonName, cert.NotAfter.UTC().Format(time.RFC3339))
 	}
-	listener.Close()
-	return true
+	return snapshot
 }
@@ -0,0 +1,81 @@
+/**
+ * @fileoverview API documentation endpoints for the application listener.
+ * The OpenAPI document at /openapi.json is generated from the application router after
+ * all routes are mounted, with summaries for the built-in endpoints, and /docs renders it.
+ */
+
+package main
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/buildinfo"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/openapi"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/render"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/routes"
+)
+
+// APITitle names the API in the OpenAPI document and the documentation page
+const APITitle = "AI Project Tutorial API Server"
+
+// jsonResponse documents a successful JSON response
+func jsonResponse(description string) map[string]openapi.Response {
+	return map[string]openapi.Response{"200": {
+		Description: description,
+		Content:     map[string]openapi.MediaType{"application/json": {Schema: openapi.Schema{Type: "object"}}},
+	}}
+}
+
+// negotiatedResponse documents a successful response available in every rendered format
+func negotiatedResponse(description string) map[string]openapi.Response {
+	schema := openapi.MediaType{Schema: openapi.Schema{Type: "object"}}
+	return map[string]openapi.Response{"200": {
+		Description: description,
+		Content:     map[string]openapi.MediaType{render.JSON: schema, render.MsgPack: schema, render.XML: schema},
+	}}
+}
+
+// builtinOperations describes the built-in endpoints, keyed by their registered pattern
+var builtinOperations = map[string]openapi.Operation{
+	"GET /{$}":          {Summary: "Service information and key endpoints", Responses: negotiatedResponse("Service information")},
+	"GET /version":      {Summary: "Build version, commit, and date", Responses: jsonResponse("Build information")},
+	"GET /openapi.json": {Summary: "This OpenAPI document", Responses: jsonResponse("OpenAPI document")},
+	"GET /docs":         {Summary: "Interactive API documentation", Responses: map[string]openapi.Response{"200": {Description: "HTML page"}}},
+	"/health":           {Summary: "Liveness and build metadata", Responses: jsonResponse("Service health")},
+	"/ready": {Summary: "Readiness to accept traffic", Responses: map[string]openapi.Response{
+		"200": jsonResponse("Ready")["200"],
+		"503": {Description: "Not ready or shutting down"},
+	}},
+	"GET /metrics": {Summary: "Prometheus metrics", Responses: map[string]openapi.Response{"200": {Description: "Metrics in text exposition format"}}},
+	"POST /webhooks/{source}": {Summary: "Receive a webhook delivery, deduplicated by delivery ID or body", Responses: map[string]openapi.Response{
+		"202": {Description: "Delivery accepted and published as a webhook.received event"},
+	}},
+	"POST /v1/completions": {Summary: "Complete a conversation with the configured LLM provider", Responses: map[string]openapi.Response{
+		"200": jsonResponse("Completion")["200"],
+		"429": {Description: "Provider rate limited"},
+		"502": {Description: "Provider unavailable"},
+	}},
+	"POST /v1/completions/stream": {Summary: "Stream a completion as Server-Sent Events", Responses: map[string]openapi.Response{
+		"200": {Description: "Event stream of chunk events, ending with an error event if the provider fails"},
+	}},
+	"POST /jobs": {Summary: "Submit a job, deduplicated by X-Dedupe-Key or body", Responses: map[string]openapi.Response{
+		"202": {Description: "Job accepted and published as a job.submitted event"},
+		"400": {Description: "Invalid job submission"},
+	}},
+}
+
+/**
+ * @description Registers /openapi.json and /docs on the scope.
+ * The document is built on first request from the router returned by mounted, which is
+ * only available once every route has been registered.
+ */
+func registerAPIDocs(scope *routes.Scope, mounted func() *routes.Router) {
+	spec := sync.OnceValue(func() *openapi.Document {
+		return openapi.Generate(openapi.Info{Title: APITitle, Version: buildinfo.Get().Version}, mounted().Routes(), builtinOperations)
+	})
+	scope.HandleFunc("GET /openapi.json", withErrorHandling(func(w http.ResponseWriter, r *http.Request) {
+		spec().ServeHTTP(w, r)
+	}))
+	scope.HandleFunc("GET /docs", withErrorHandling(openapi.DocsHandler(APITitle, "/openapi.json")))
+}
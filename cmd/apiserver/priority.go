@@ -0,0 +1,26 @@
+/**
+ * @fileoverview Priority classification of application requests.
+ * Health probes bypass the concurrency limit, and operator traffic — admin routes and
+ * any request authenticated with the admin token — is queued ahead of normal requests.
+ */
+
+package main
+
+import (
+	"net/http"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/priority"
+)
+
+// newPriorityClassifier classifies by the default routes, then by the caller's credentials
+func newPriorityClassifier(admin config.AdminConfig) *priority.Classifier {
+	classifier := priority.NewClassifier()
+	if token := admin.Token; token != "" {
+		classifier.Tier = func(r *http.Request) (priority.Class, bool) {
+			return priority.High, httputil.HasBearerToken(r, token)
+		}
+	}
+	return classifier
+}
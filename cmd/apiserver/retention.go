@@ -0,0 +1,60 @@
+/**
+ * @fileoverview Retention manager wiring.
+ * Registers the store backend's expired idempotency keys with the retention manager and
+ * runs its purges in the background until shutdown.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/retention"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/store"
+)
+
+/**
+ * @description Starts purging the configured data types, when any policy is set.
+ * Backends that expire idempotency keys themselves, such as Redis, need no purge.
+ */
+func startRetention(settings config.RetentionConfig, stores *store.Stores) error {
+	policies, err := settings.ParsedPolicies()
+	if err != nil {
+		return err
+	}
+
+	purger, purges := stores.Purger()
+	if !purges {
+		delete(policies, retention.TypeIdempotencyKeys)
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+	interval := time.Duration(settings.Interval)
+	if interval <= 0 {
+		interval = retention.DefaultInterval
+	}
+
+	manager := retention.New(retention.Config{Policies: policies, Interval: interval, DryRun: settings.DryRun})
+	if purges {
+		manager.RegisterStore(purger)
+	}
+	if unmanaged := manager.Unmanaged(); len(unmanaged) > 0 {
+		fmt.Printf("⚠️ Retention policies for %s have no purge target and are ignored\n", strings.Join(unmanaged, ", "))
+	}
+
+	lifecycle.Go("retention", func(ctx context.Context) error {
+		manager.Run(ctx)
+		return nil
+	})
+	mode := ""
+	if settings.DryRun {
+		mode = " (dry run)"
+	}
+	fmt.Printf("✅ Retention purges every %v%s\n", interval, mode)
+	return nil
+}
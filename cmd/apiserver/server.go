@@ -0,0 +1,534 @@
+/**
+ * @fileoverview HTTP server construction, lifecycle, and shared handlers for the API server.
+ * Registers routes through the validated route registry, runs the server with retries,
+ * and performs graceful shutdown.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/buildinfo"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/deadline"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/debug"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/dedupe"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/events"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/grpcserver"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/health"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/health/fleet"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/lbhint"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/llm"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/middleware"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/priority"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/render"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/routes"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/state"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/store"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/streams"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/support"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/tlsutil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/ws"
+)
+
+// bodyLimits caps request bodies per route; the default is set from configuration
+var bodyLimits = middleware.NewBodyLimits(config.DefaultMaxBodyBytes)
+
+// handlerTimeouts bounds handler execution per route; the default is set from configuration
+var handlerTimeouts = middleware.NewTimeouts(0)
+
+// accessLog records every finished request; output and format are set by setupLogging
+var accessLog = middleware.NewAccessLog()
+
+// panicReporter forwards recovered panics to the configured error tracker, if any
+var panicReporter middleware.PanicReporter
+
+// standardLayers record response metrics, assign request IDs, log requests, recover from
+// panics, limit request bodies, and apply handler timeouts, outermost first. The access log
+// runs outside Recovery so requests that panicked are logged with their 500. The names label
+// route scopes, so the registry's middleware order check validates this exact chain.
+var standardLayers = []struct {
+	name       string
+	middleware middleware.Middleware
+}{
+	{"metrics", middleware.Observe(recordResponseMetrics)},
+	{"request-id", middleware.RequestID(middleware.RequestIDHeader)},
+	{"logging", accessLog.Middleware()},
+	{"recovery", middleware.Recovery(func(report middleware.PanicReport) {
+		if panicReporter != nil {
+			panicReporter(report)
+		}
+	})},
+	{"body-limit", bodyLimits.Middleware()},
+	{"timeout", handlerTimeouts.Middleware()},
+}
+
+// standardMiddleware applies standardLayers; it wraps every registered route
+var standardMiddleware = middleware.New(standardMiddlewareFuncs()...)
+
+// Per-route response metrics recorded by withErrorHandling
+var (
+	responseSizes = metrics.NewHistogramVec("http_response_size_bytes",
+		"Response body size by route.", "route", metrics.SizeBuckets)
+	serializationTimes = metrics.NewHistogramVec("http_response_serialization_seconds",
+		"Time spent encoding response bodies by route.", "route", metrics.DurationBuckets)
+)
+
+// responseSizeWarning is the body size above which a response is logged; zero disables the warning
+var responseSizeWarning int64
+
+// streamRoutes are the patterns of long-lived streams, exempt from handler timeouts and deadline budgets
+var streamRoutes = map[string]bool{}
+
+// inFlight tracks requests on the application listener so shutdown can report drain progress
+var inFlight = middleware.NewInFlight()
+
+// loadHints adds load-balancer hint headers when server.lb_hint_headers is enabled; nil otherwise
+var loadHints *lbhint.Hints
+
+// RootResponse describes the service at the root endpoint
+type RootResponse struct {
+	Service   string   `json:"service"`
+	Phase     string   `json:"phase"`
+	Endpoints []string `json:"endpoints"`
+	Timestamp string   `json:"timestamp"`
+}
+
+/**
+ * @description Creates and configures the HTTP server with health checker.
+ * Returns a configured http.Server with proper timeouts and error handling.
+ */
+func createHTTPServerWithHealthChecker(cfg config.Config, healthChecker *health.HealthChecker, bus *events.Bus, configHistory *config.History, stores *store.Stores, provider llm.Provider) (*http.Server, error) {
+	registry := routes.NewRegistry()
+	bodyLimits.SetDefault(cfg.Limits.MaxBodyBytes)
+	handlerTimeouts.SetDefault(time.Duration(cfg.Limits.RequestTimeout))
+	if url := cfg.Logging.ErrorTrackerURL; url != "" {
+		panicReporter = middleware.WebhookPanicReporter(url)
+		fmt.Println("✅ Reporting handler panics to the configured error tracker")
+	}
+
+	// Operational endpoints move to their own registry when a separate admin listener is configured
+	adminRegistry := registry
+	if cfg.Admin.Address != "" {
+		adminRegistry = routes.NewRegistry()
+	}
+	var router, adminRouter *routes.Router
+
+	// Register health endpoints using the health checker
+	builtin := standardScope(adminRegistry, "builtin")
+	builtin.HandleFunc("/health", withErrorHandling(healthChecker.HealthHandler))
+	builtin.HandleFunc("/ready", withErrorHandling(healthChecker.ReadinessHandler))
+	builtin.HandleFunc("/admin/health/diagnose", withErrorHandling(healthChecker.DiagnoseHandler))
+	builtin.HandleFunc("GET /admin/config/history", withErrorHandling(configHistory.Handler))
+	builtin.HandleFunc("GET /metrics", metrics.Handler)
+	builtin.HandleFunc("GET /admin/requests", withErrorHandling(inFlight.Handler))
+	builtin.HandleFunc("GET /admin/routes", withErrorHandling(func(w http.ResponseWriter, r *http.Request) {
+		listing := map[string][]routes.RouteInfo{"routes": router.Routes()}
+		if adminRouter != router {
+			listing["admin_routes"] = adminRouter.Routes()
+		}
+		jsoncase.Write(w, http.StatusOK, listing)
+	}))
+	public := standardScope(registry, "builtin")
+	public.HandleFunc("GET /{$}", withErrorHandling(handleRoot))
+	public.HandleFunc("GET /version", withErrorHandling(buildinfo.Handler))
+	registerAPIDocs(public, func() *routes.Router { return router })
+	registerIntake(public, bus, stores.Idempotency)
+
+	// Account for long-running streams and reap idle ones
+	tracker := streams.NewTracker(streams.Config{
+		MaxPerKey:   cfg.Streams.MaxPerClient,
+		IdleTimeout: time.Duration(cfg.Streams.IdleTimeout),
+	})
+	lifecycle.Go("stream-reaper", func(ctx context.Context) error {
+		tracker.Run(ctx)
+		return nil
+	})
+	sockets := ws.NewManager(ws.Config{
+		AllowedOrigins: cfg.Streams.WebSocketOrigins,
+		PingInterval:   time.Duration(cfg.Streams.WebSocketPingInterval),
+	})
+
+	// Serve completions from the configured LLM provider, tracking usage and estimated cost
+	if provider != nil {
+		prices, err := llm.ParsePricing(cfg.LLM.Pricing)
+		if err != nil {
+			return nil, fmt.Errorf("invalid llm.pricing: %w", err)
+		}
+		usage := llm.NewUsageTracker(llm.NewModelRegistry(prices), llm.DefaultUsageHistory)
+		registerCompletions(public, provider, cfg.LLM, tracker, usage)
+		if token := cfg.Admin.Token; token != "" {
+			builtin.HandleFunc("GET /admin/usage", withErrorHandling(httputil.RequireBearerToken(token, usage.Handler)))
+		}
+		fmt.Printf("✅ Completions served by the %s provider at /v1/completions\n", provider.Name())
+	}
+
+	// The admin event stream is only mounted when an admin token is configured
+	if token := cfg.Admin.Token; token != "" {
+		exemptStream("GET /admin/events")
+		builtin.Handle("GET /admin/events", tracker.Middleware("/admin/events", http.HandlerFunc(withErrorHandling(events.Handler(bus, token)))))
+		builtin.HandleFunc("GET /admin/streams", withErrorHandling(httputil.RequireBearerToken(token, func(w http.ResponseWriter, r *http.Request) {
+			jsoncase.Write(w, http.StatusOK, tracker.Streams())
+		})))
+		builtin.HandleFunc("GET /admin/support-bundle", withErrorHandling(httputil.RequireBearerToken(token,
+			support.Handler(supportBundleFiles(cfg, healthChecker, configHistory)))))
+		// State bundles carry redacted configuration, trust anchors, and stored responses
+		stateRegistry := newStateRegistry(cfg, stores)
+		bodyLimits.Set("POST /admin/state", state.MaxBundleBytes)
+		builtin.HandleFunc("GET /admin/state", withErrorHandling(httputil.RequireBearerToken(token, stateRegistry.ExportHandler)))
+		builtin.HandleFunc("POST /admin/state", withErrorHandling(httputil.RequireBearerToken(token, stateRegistry.ImportHandler)))
+		fmt.Println("✅ Admin event stream enabled at /admin/events")
+	}
+
+	// Serve a combined fleet view when peer instances are configured
+	if len(cfg.Health.FleetPeers) > 0 {
+		peers, err := fleet.ParsePeers(strings.Join(cfg.Health.FleetPeers, ","))
+		if err != nil {
+			return nil, fmt.Errorf("invalid health.fleet_peers: %w", err)
+		}
+		aggregator := fleet.New(fleet.Config{Peers: peers, MinHealthy: cfg.Health.FleetMinHealthy})
+		lifecycle.Go("fleet-aggregator", func(ctx context.Context) error {
+			aggregator.Run(ctx)
+			return nil
+		})
+		builtin.HandleFunc("GET /fleet/health", withErrorHandling(aggregator.Handler))
+		fmt.Printf("✅ Fleet aggregation enabled for %d peers at /fleet/health\n", len(peers))
+	}
+
+	// Debug endpoints are only available outside the prod profile
+	if cfg.Server.Profile != ProdProfile {
+		debugScope := standardScope(adminRegistry, "debug endpoints")
+		debug.Register(debugScope, withErrorHandling)
+		exemptStream("GET /debug/ws")
+		debugScope.Handle("GET /debug/ws", tracker.Middleware("/debug/ws", http.HandlerFunc(withErrorHandling(sockets.Handler(debug.EchoSocket).ServeHTTP))))
+		fmt.Println("✅ Debug endpoints enabled under /debug/")
+	}
+
+	// Profiling is only exposed on a separate admin listener, never on the application port
+	if cfg.Admin.Address != "" {
+		registerProfiling(standardScope(adminRegistry, "profiling"))
+	}
+
+	// Mount declarative routes when a routes file is configured
+	if routesFile := cfg.RoutesFile; routesFile != "" {
+		declared, err := routes.LoadFile(routesFile)
+		if err != nil {
+			return nil, err
+		}
+		versions, err := routes.ParseVersions(cfg.API.DeprecatedVersions, cfg.API.SunsetVersions, cfg.API.DeprecationLink)
+		if err != nil {
+			return nil, err
+		}
+		wrap := func(handler http.Handler) http.Handler {
+			return http.HandlerFunc(withErrorHandling(handler.ServeHTTP))
+		}
+		fileScope := standardScope(registry, "routes file "+routesFile)
+		for _, route := range declared {
+			// Versioned routes mount under their prefix with the version's deprecation headers
+			scope := fileScope
+			if route.Version != "" {
+				version, ok := versions[route.Version]
+				if !ok {
+					version = routes.APIVersion{Name: route.Version}
+				}
+				scope = fileScope.Version(version)
+			}
+			pattern := scope.Pattern(route.Path)
+			if route.MaxBodyBytes != 0 {
+				bodyLimits.Set(pattern, route.MaxBodyBytes)
+			}
+			if route.TimeoutMs != 0 {
+				handlerTimeouts.Set(pattern, time.Duration(route.TimeoutMs)*time.Millisecond)
+			}
+			if err := routes.Mount(scope, []routes.Route{route}, wrap); err != nil {
+				return nil, err
+			}
+		}
+		fmt.Printf("✅ Loaded %d declarative routes from %s\n", len(declared), routesFile)
+	}
+
+	// Front an existing backend under the proxy prefix; built-in and declared routes take precedence
+	if cfg.Proxy.Target != "" {
+		group := cfg.ProxyGroup()
+		// Proxied responses are streamed, so the buffering handler timeout is disabled; the proxy
+		// timeout bounds the wait for response headers in the transport and the whole exchange
+		// as a context deadline, and the caller's deadline budget still applies and is propagated
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.ResponseHeaderTimeout = group.Timeout
+		group.Transport = outboundCalls.Transport(transport)
+		proxy, err := group.Handler()
+		if err != nil {
+			return nil, err
+		}
+		handlerTimeouts.Set(group.Prefix, 0)
+		standardScope(registry, "proxy").Handle(group.Prefix, http.HandlerFunc(withErrorHandling(proxy.ServeHTTP)))
+		fmt.Printf("✅ Proxying %s to %s\n", group.Prefix, group.Target)
+	}
+
+	// Validate all registrations before mounting them
+	router, err := buildRouter(registry)
+	if err != nil {
+		return nil, err
+	}
+	adminRouter = router
+	if adminRegistry != registry {
+		if adminRouter, err = buildRouter(adminRegistry); err != nil {
+			return nil, err
+		}
+	}
+
+	// Warn about unexpectedly large responses when a threshold is configured
+	responseSizeWarning = cfg.Limits.ResponseSizeWarnBytes
+
+	// Replay stored responses to retried POST and PATCH requests carrying an Idempotency-Key
+	var handler http.Handler = router
+	if ttl := time.Duration(cfg.API.IdempotencyTTL); ttl > 0 {
+		deduper := dedupe.New(dedupe.Config{Store: stores.Idempotency, Namespace: "http", TTL: ttl})
+		handler = deduper.IdempotencyMiddleware(dedupe.IdempotencyConfig{}, handler)
+		fmt.Printf("✅ Idempotency-Key responses replayed for %s\n", ttl)
+	}
+
+	// Admit requests by priority class when a concurrency limit is configured
+	capacity := cfg.Limits.MaxConcurrentRequests
+	if capacity > 0 {
+		limiter := priority.NewLimiter(capacity, capacity*PriorityQueueFactor, PriorityQueueTimeout)
+		handler = limiter.Middleware(newPriorityClassifier(cfg.Admin), handler)
+		fmt.Printf("✅ Priority concurrency limit set to %d requests\n", capacity)
+	}
+
+	// Reject clients exceeding their request rate before they occupy a concurrency slot
+	if cfg.Limits.RateLimitRPS > 0 {
+		handler = middleware.RateLimit(middleware.RateLimitConfig{
+			Store: stores.Limiter,
+			Rate:  cfg.Limits.RateLimitRPS,
+			Burst: cfg.Limits.RateLimitBurst,
+		})(handler)
+		fmt.Printf("✅ Rate limit set to %g requests/second per client\n", cfg.Limits.RateLimitRPS)
+	}
+
+	// Bound each request by the caller's deadline budget, including time spent queued; streams run unbounded
+	handler = deadline.Middleware(deadline.Config{
+		Margin:  time.Duration(cfg.Deadline.Margin),
+		Default: time.Duration(cfg.Deadline.Default),
+		Max:     time.Duration(cfg.Deadline.Max),
+		Skip: func(r *http.Request) bool {
+			return streamRoutes[r.Method+" "+r.URL.Path]
+		},
+	}, handler)
+
+	// Report saturation and drain state to load balancers, counting queued requests
+	if cfg.Server.LBHintHeaders {
+		loadHints = lbhint.New(capacity)
+		handler = loadHints.Middleware(handler)
+		fmt.Println("✅ Load-balancer hint headers enabled")
+	}
+
+	// Add security headers to every response, including errors from routing and limits
+	security := middleware.SecurityHeaders(middleware.SecurityHeadersConfig{
+		HSTSMaxAge:            time.Duration(cfg.Security.HSTSMaxAge),
+		HSTSIncludeSubdomains: cfg.Security.HSTSIncludeSubdomains,
+		ContentSecurityPolicy: cfg.Security.ContentSecurityPolicy,
+		FrameOptions:          cfg.Security.FrameOptions,
+		ReferrerPolicy:        cfg.Security.ReferrerPolicy,
+	})
+	handler = security(handler)
+
+	// Count every request on the application listener, including rejected ones
+	handler = inFlight.Middleware()(handler)
+
+	candidates, err := getBindCandidates(cfg.Server)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &http.Server{
+		Addr:              candidates[0],
+		Handler:           handler,
+		ReadTimeout:       time.Duration(cfg.Server.ReadTimeout),
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeout),
+		WriteTimeout:      time.Duration(cfg.Server.WriteTimeout),
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeout),
+		MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+		ErrorLog:          log.New(os.Stderr, "HTTP: ", log.LstdFlags),
+	}
+	server.SetKeepAlivesEnabled(cfg.Server.KeepAlives)
+	// Serve HTTPS from a configured certificate, reloaded when rotated on disk, or from ACME
+	if cfg.TLS.CertFile != "" {
+		keyPair, err := tlsutil.NewKeyPair(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		keyPair.OnReload(func(err error) {
+			if err != nil {
+				log.Printf("Server certificate reload failed, keeping previous certificate: %v", err)
+				return
+			}
+			log.Printf("Server certificate reloaded from %s", cfg.TLS.CertFile)
+		})
+		lifecycle.Go("certificate-watcher", func(ctx context.Context) error {
+			keyPair.Watch(ctx, tlsutil.DefaultWatchInterval)
+			return nil
+		})
+		minVersion, _ := tlsutil.ParseMinVersion(cfg.TLS.MinVersion)
+		server.TLSConfig = tlsutil.ServerTLSConfig(minVersion, keyPair.GetCertificate)
+		healthChecker.AddReadinessCheck("server-certificate",
+			health.CertificateExpiryCheck("server certificate", keyPair.Certificates, TrustAnchorExpiryWarning))
+		fmt.Printf("✅ HTTPS enabled with TLS %s or newer\n", cfg.TLS.MinVersion)
+	} else if len(cfg.TLS.ACMEDomains) > 0 {
+		if err := setupACME(context.Background(), cfg.Server, cfg.TLS, server); err != nil {
+			return nil, err
+		}
+	}
+	// Serve the same handlers over QUIC when enabled, reusing the TLS configuration
+	if cfg.Server.HTTP3 {
+		http3Server = enableHTTP3(server)
+	}
+
+	// Serve operational endpoints on the admin listener, closed once the main server has drained
+	if cfg.Admin.Address != "" {
+		adminServer, err := startAdminServer(cfg.Server, cfg.Admin.Address, security(adminRouter))
+		if err != nil {
+			return nil, err
+		}
+		lifecycle.OnShutdown("admin-server", adminServer.Shutdown)
+	}
+
+	// End event streams so they do not hold up graceful shutdown
+	server.RegisterOnShutdown(bus.Close)
+	server.RegisterOnShutdown(func() { tracker.CloseAll(streams.ReasonShutdown) })
+	lifecycle.OnShutdown("websockets", func(ctx context.Context) error {
+		return sockets.CloseAll(ctx, ws.ReasonShutdown)
+	})
+
+	fmt.Println("✅ HTTP server configured successfully")
+	return server, nil
+}
+
+// exemptStream marks a long-lived stream route so neither handler timeouts nor deadline budgets end it
+func exemptStream(pattern string) {
+	streamRoutes[pattern] = true
+	handlerTimeouts.Set(pattern, 0)
+}
+
+// standardMiddlewareFuncs returns the middleware of standardLayers in order
+func standardMiddlewareFuncs() []middleware.Middleware {
+	funcs := make([]middleware.Middleware, len(standardLayers))
+	for i, layer := range standardLayers {
+		funcs[i] = layer.middleware
+	}
+	return funcs
+}
+
+// standardScope returns a scope of registry whose routes are labeled with standardLayers' names
+func standardScope(registry *routes.Registry, source string) *routes.Scope {
+	names := make([]string, len(standardLayers))
+	for i, layer := range standardLayers {
+		names[i] = layer.name
+	}
+	return registry.Scope(source, names...)
+}
+
+/**
+ * @description Validates a registry's routes, prints any warnings, and builds its router.
+ */
+func buildRouter(registry *routes.Registry) (*routes.Router, error) {
+	router, report, err := registry.Router()
+	for _, warning := range report.Warnings {
+		fmt.Printf("⚠️ Route warning: %s\n", warning)
+	}
+	return router, err
+}
+
+/**
+ * @description Binds the server's address, retrying while it is in use, then serves until shutdown,
+ * sharing the listener with rpc when gRPC is multiplexed.
+ * Returns nil after a graceful shutdown and an error when binding or serving fails.
+ */
+func startServerWithRetries(ctx context.Context, server *http.Server, rpc *grpcserver.Server, serverConfig config.ServerConfig) error {
+	candidates, err := getBindCandidates(serverConfig)
+	if err != nil {
+		return err
+	}
+
+	listener, err := bindWithRetries(ctx, bindNetwork("tcp", serverConfig.IPFamily), candidates)
+	if err != nil {
+		return &ServerError{
+			Message: "Server failed to bind",
+			Cause:   err,
+			Code:    500,
+		}
+	}
+	server.Addr = listener.Addr().String()
+	fmt.Printf("✅ Server listening on %s\n", server.Addr)
+	listener = limitListener(listener, serverConfig)
+	if http3Server != nil {
+		if err := startHTTP3(http3Server, serverConfig, server.Addr); err != nil {
+			listener.Close()
+			return &ServerError{
+				Message: "HTTP/3 server failed to bind",
+				Cause:   err,
+				Code:    500,
+			}
+		}
+	}
+
+	if err := serveHTTPAndGRPC(server, rpc, listener); !errors.Is(err, http.ErrServerClosed) {
+		return &ServerError{
+			Message: "Server stopped unexpectedly",
+			Cause:   err,
+			Code:    500,
+		}
+	}
+	fmt.Println("✅ Server stopped accepting connections")
+	return nil
+}
+
+/**
+ * @description Wraps a handler with the standard middleware stack.
+ * Kept as a function adapter for packages such as debug that take a handler-wrapping func.
+ */
+func withErrorHandling(handler func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+	return standardMiddleware.ThenFunc(handler).ServeHTTP
+}
+
+/**
+ * @description Records response size and serialization time under the matched route pattern
+ * and logs responses larger than the configured warning size.
+ */
+func recordResponseMetrics(r *http.Request, rw *httputil.ResponseWriter) {
+	route := r.Pattern
+	if route == "" {
+		route = "unmatched"
+	}
+	responseSizes.With(route).Observe(float64(rw.BytesWritten()))
+	if serialization := rw.SerializationTime(); serialization > 0 {
+		serializationTimes.With(route).Observe(serialization.Seconds())
+	}
+	if responseSizeWarning > 0 && rw.BytesWritten() > responseSizeWarning {
+		log.Printf("⚠️ Large response for %s %s (route %s): %d bytes exceeds %d", r.Method, r.URL.Path, route, rw.BytesWritten(), responseSizeWarning)
+	}
+}
+
+/**
+ * @description Root endpoint handler providing basic service information.
+ * Returns service name and available endpoints in the format the client accepts.
+ */
+func handleRoot(w http.ResponseWriter, r *http.Request) {
+	render.Write(w, r, http.StatusOK, RootResponse{
+		Service:   "AI Project Tutorial API Server",
+		Phase:     "0",
+		Endpoints: []string{"/health", "/ready", "/version", "/openapi.json", "/docs"},
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+}
@@ -0,0 +1,108 @@
+/**
+ * @fileoverview Graceful shutdown of the HTTP server and, when enabled, the HTTP/3 server.
+ * Runs as the http-server component's Stop, before the components it depends on stop
+ * and before shutdown hooks run, logging through slog how many requests are still in
+ * flight while the server drains.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// DrainProgressInterval is how often drain progress is logged during shutdown
+const DrainProgressInterval = time.Second
+
+/**
+ * @description Performs graceful shutdown of the HTTP server.
+ * Drains TCP and HTTP/3 connections together within timeout, forcing them closed if it
+ * passes, and logs the number of in-flight requests until they reach zero.
+ */
+func performGracefulShutdown(server *http.Server, timeout time.Duration) error {
+	slog.Info("graceful shutdown started", "timeout", timeout)
+
+	// Create context with timeout for shutdown
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Channel to track shutdown completion
+	shutdownComplete := make(chan error, 1)
+
+	go func() {
+		http3Complete := make(chan error, 1)
+		go func() { http3Complete <- shutdownHTTP3(ctx) }()
+		err := server.Shutdown(ctx)
+		shutdownComplete <- errors.Join(err, <-http3Complete)
+	}()
+	drained := make(chan struct{})
+	defer close(drained)
+	go logDrainProgress(drained)
+
+	// Wait for shutdown completion or timeout
+	var shutdownErr error
+	select {
+	case err := <-shutdownComplete:
+		if err != nil {
+			shutdownErr = &ServerError{
+				Message: "Error during server shutdown",
+				Cause:   err,
+				Code:    500,
+			}
+		} else {
+			slog.Info("server shutdown completed")
+		}
+
+	case <-ctx.Done():
+		// Force close if graceful shutdown times out
+		slog.Warn("graceful shutdown timed out, forcing server close", "remaining", inFlight.Count())
+		if http3Server != nil {
+			http3Server.Close()
+		}
+		if err := server.Close(); err != nil {
+			shutdownErr = &ServerError{
+				Message: "Error during forced server close",
+				Cause:   err,
+				Code:    500,
+			}
+		} else {
+			shutdownErr = &ServerError{
+				Message: "Server shutdown timed out and was forced to close",
+				Code:    408,
+			}
+		}
+	}
+	return shutdownErr
+}
+
+// logDrainProgress logs the in-flight request count whenever it changes until it reaches
+// zero or done is closed
+func logDrainProgress(done <-chan struct{}) {
+	ticker := time.NewTicker(DrainProgressInterval)
+	defer ticker.Stop()
+
+	last := -1
+	for {
+		remaining := inFlight.Count()
+		if remaining == 0 {
+			if last != 0 {
+				slog.Info("all in-flight requests completed")
+			}
+			return
+		}
+		if remaining != last {
+			slog.Info("draining in-flight requests", "remaining", remaining)
+			last = remaining
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,46 @@
+/**
+ * @fileoverview Database handle for the sql store backend.
+ * Registers the PostgreSQL and MySQL drivers and opens the database configured under
+ * store, so idempotency keys and rate-limit buckets can live in an existing database.
+ */
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/store"
+)
+
+// sqlDrivers maps store dialects to the database/sql driver registered for them
+var sqlDrivers = map[string]string{
+	store.DialectPostgres: "postgres",
+	store.DialectMySQL:    "mysql",
+}
+
+/**
+ * @description Opens the store database and checks that it is reachable.
+ * The caller closes the handle once the stores are no longer used.
+ */
+func openStoreDB(ctx context.Context, storeConfig config.StoreConfig) (*sql.DB, error) {
+	driver, ok := sqlDrivers[storeConfig.SQLDialect]
+	if !ok {
+		return nil, fmt.Errorf("unsupported store SQL dialect %q", storeConfig.SQLDialect)
+	}
+	db, err := sql.Open(driver, storeConfig.SQLDSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the store database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to the %s store database: %w", storeConfig.SQLDialect, err)
+	}
+	fmt.Printf("✅ Store backed by %s database\n", storeConfig.SQLDialect)
+	return db, nil
+}
@@ -0,0 +1,180 @@
+/**
+ * @fileoverview State bundle sections for the API server.
+ * Registers the configuration, upstream trust store, and stored idempotency keys with the
+ * state registry served at /admin/state, so an instance's state can be exported and
+ * imported into another during environment promotion.
+ */
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/state"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/store"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/tlsutil"
+)
+
+/**
+ * @description Creates the state registry with a section for each subsystem this instance runs.
+ */
+func newStateRegistry(cfg config.Config, stores *store.Stores) *state.Registry {
+	source, _ := os.Hostname()
+	registry := state.NewRegistry(source)
+	registry.Register(state.SectionConfig, configSection(cfg))
+	if caBundle := cfg.TLS.UpstreamCABundle; caBundle != "" {
+		registry.Register(state.SectionTrustStore, trustStoreSection(caBundle))
+	}
+	if lister, ok := stores.Lister(); ok {
+		registry.Register(state.SectionStore, storeSection(lister, stores.Idempotency))
+	}
+	return registry
+}
+
+// configSection exports the effective configuration with secrets redacted. Configuration
+// is loaded at startup, so imports are validated and compared with the running settings,
+// and each difference is logged for the operator to apply to this instance's file or
+// environment; the count is the number of differing settings
+func configSection(cfg config.Config) state.Section {
+	return state.Section{
+		Export: func(context.Context) (any, error) {
+			return cfg.Redacted(), nil
+		},
+		Import: func(_ context.Context, data json.RawMessage, dryRun bool) (int, error) {
+			imported := config.Default()
+			if err := json.Unmarshal(data, &imported); err != nil {
+				return 0, err
+			}
+			if err := imported.Validate(); err != nil {
+				return 0, err
+			}
+			running, err := config.Flatten(cfg.Redacted())
+			if err != nil {
+				return 0, err
+			}
+			bundled, err := config.Flatten(imported.Redacted())
+			if err != nil {
+				return 0, err
+			}
+			changes := config.Diff(running, bundled)
+			if !dryRun {
+				log.Printf("Imported configuration differs from the running configuration in %d settings", len(changes))
+				for _, change := range changes {
+					log.Printf("  %s %s: %q -> %q", change.Kind, change.Key, change.Old, change.New)
+				}
+			}
+			return len(changes), nil
+		},
+	}
+}
+
+// trustStoreSection exports the upstream CA bundle as PEM certificates. Imports replace the
+// bundle file, which the trust store watch then reloads; the count is the number of certificates
+func trustStoreSection(caBundle string) state.Section {
+	return state.Section{
+		Export: func(context.Context) (any, error) {
+			data, err := os.ReadFile(caBundle)
+			if err != nil {
+				return nil, err
+			}
+			anchors, err := tlsutil.ParseCertificates(data)
+			if err != nil {
+				return nil, err
+			}
+			certificates := make([]string, len(anchors))
+			for i, anchor := range anchors {
+				certificates[i] = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: anchor.Raw}))
+			}
+			return certificates, nil
+		},
+		Import: func(_ context.Context, data json.RawMessage, dryRun bool) (int, error) {
+			var certificates []string
+			if err := json.Unmarshal(data, &certificates); err != nil {
+				return 0, err
+			}
+			var bundle []byte
+			for _, certificate := range certificates {
+				bundle = append(bundle, certificate...)
+			}
+			anchors, err := tlsutil.ParseCertificates(bundle)
+			if err != nil {
+				return 0, err
+			}
+			if len(anchors) == 0 {
+				return 0, fmt.Errorf("bundle contains no certificates")
+			}
+			if !dryRun {
+				if err := replaceFile(caBundle, bundle); err != nil {
+					return 0, err
+				}
+			}
+			return len(anchors), nil
+		},
+	}
+}
+
+// storeSection exports stored idempotency values with their expiry. Imports store each value
+// for the rest of its lifetime, skipping values that have expired since the export
+func storeSection(lister store.Lister, idempotency store.IdempotencyStore) state.Section {
+	return state.Section{
+		Export: func(ctx context.Context) (any, error) {
+			return lister.Entries(ctx)
+		},
+		Import: func(ctx context.Context, data json.RawMessage, dryRun bool) (int, error) {
+			var entries []store.Entry
+			if err := json.Unmarshal(data, &entries); err != nil {
+				return 0, err
+			}
+			imported := 0
+			for i, entry := range entries {
+				if entry.Key == "" {
+					return 0, fmt.Errorf("entry %d has no key", i)
+				}
+				ttl := time.Until(entry.ExpiresAt)
+				if ttl <= 0 {
+					continue
+				}
+				if !dryRun {
+					if err := idempotency.Put(ctx, entry.Key, entry.Value, ttl); err != nil {
+						return 0, err
+					}
+				}
+				imported++
+			}
+			return imported, nil
+		},
+	}
+}
+
+// replaceFile atomically replaces path with data, keeping its permissions
+func replaceFile(path string, data []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Chmod(info.Mode().Perm()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
@@ -0,0 +1,115 @@
+/**
+ * @fileoverview Support bundle wiring for the API server.
+ * Serves a diagnostic archive at /admin/support-bundle and implements the
+ * "apiserver support-bundle" command that downloads it from a running server.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/buildinfo"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/health"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/support"
+)
+
+// SupportBundleTimeout bounds downloading a bundle from a running server
+const SupportBundleTimeout = 30 * time.Second
+
+// recentLogs retains recent log output for support bundles
+var recentLogs = support.NewLogBuffer(support.DefaultLogLines)
+
+/**
+ * @description Returns the files collected into a support bundle.
+ */
+func supportBundleFiles(cfg config.Config, healthChecker *health.HealthChecker, configHistory *config.History) func() []support.File {
+	return func() []support.File {
+		return []support.File{
+			support.JSONFile("version.json", func() (any, error) {
+				return map[string]any{
+					"build":      buildinfo.Get(),
+					"profile":    cfg.Server.Profile,
+					"started_at": healthChecker.GetStartTime().UTC().Format(time.RFC3339),
+					"uptime":     healthChecker.GetUptime().Round(time.Second).String(),
+				}, nil
+			}),
+			support.RedactedEnvironment(),
+			support.JSONFile("config.json", func() (any, error) {
+				return cfg.Redacted(), nil
+			}),
+			support.JSONFile("config-history.json", func() (any, error) {
+				return configHistory.Reloads(), nil
+			}),
+			support.JSONFile("health-history.json", func() (any, error) {
+				return map[string]any{
+					"diagnosis": healthChecker.Diagnose(),
+					"history":   healthChecker.AllHistory(),
+				}, nil
+			}),
+			support.GoroutineDump(),
+			support.HandlerSnapshot("metrics.txt", metrics.Default),
+			recentLogs.File("logs.txt"),
+		}
+	}
+}
+
+/**
+ * @description Runs the support-bundle command: downloads a bundle from a running server's
+ * admin endpoint, authenticating with -token or the configured admin token.
+ */
+func runSupportBundle(args []string) error {
+	cfg, err := config.Load(os.Getenv(ConfigFileEnv))
+	if err != nil {
+		return err
+	}
+
+	flags := flag.NewFlagSet("support-bundle", flag.ContinueOnError)
+	serverURL := flags.String("url", "http://localhost:"+cfg.Server.Port, "base URL of the running server")
+	output := flags.String("o", "support-bundle-"+time.Now().UTC().Format("20060102T150405Z")+".tar.gz", "output file")
+	token := flags.String("token", cfg.Admin.Token, "admin bearer token")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *token == "" {
+		return fmt.Errorf("an admin token is required (-token or ADMIN_TOKEN)")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, *serverURL+"/admin/support-bundle", nil)
+	if err != nil {
+		return fmt.Errorf("invalid server URL: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+*token)
+
+	client := &http.Client{Timeout: SupportBundleTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download support bundle: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download support bundle: server returned %s", resp.Status)
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", *output, err)
+	}
+	if _, err := io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+
+	fmt.Printf("✅ Support bundle written to %s\n", *output)
+	return nil
+}
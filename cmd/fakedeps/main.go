@@ -0,0 +1,81 @@
+/**
+ * @fileoverview Standalone runner for fake dependencies used in integration and soak tests.
+ * Starts a flaky TCP listener, a slow HTTP endpoint, and a failing gRPC health service
+ * as configured by flags, so pkg/health checks and resilience middleware can be
+ * exercised end-to-end against misbehaving dependencies.
+ */
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/testsupport"
+)
+
+/**
+ * @description Parses flags, starts the requested fakes, and runs until interrupted.
+ */
+func main() {
+	tcpAddr := flag.String("tcp-addr", "", "address for the flaky TCP listener (disabled when empty)")
+	tcpFailureRate := flag.Float64("tcp-failure-rate", 0.5, "probability that a TCP connection is dropped")
+	httpAddr := flag.String("http-addr", "", "address for the slow HTTP endpoint (disabled when empty)")
+	httpDelay := flag.Duration("http-delay", 2*time.Second, "delay before each HTTP response")
+	httpStatus := flag.Int("http-status", 200, "HTTP status code to return")
+	httpBody := flag.String("http-body", `{"status":"healthy"}`, "HTTP response body")
+	grpcAddr := flag.String("grpc-addr", "", "address for the fake gRPC health service (disabled when empty)")
+	grpcNotServing := flag.Bool("grpc-not-serving", false, "report NOT_SERVING from the gRPC health service")
+	grpcErrorCode := flag.Int("grpc-error-code", 0, "gRPC status code returned for every call (0 disables)")
+	flag.Parse()
+
+	if *tcpAddr == "" && *httpAddr == "" && *grpcAddr == "" {
+		fmt.Fprintln(os.Stderr, "at least one of -tcp-addr, -http-addr, or -grpc-addr is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *tcpAddr != "" {
+		listener, err := testsupport.NewFlakyTCPListener(*tcpAddr, *tcpFailureRate)
+		if err != nil {
+			log.Fatalf("Failed to start flaky TCP listener: %v", err)
+		}
+		defer listener.Close()
+		fmt.Printf("✅ Flaky TCP listener on %s (failure rate %.2f)\n", listener.Addr(), *tcpFailureRate)
+	}
+
+	if *httpAddr != "" {
+		server, err := testsupport.NewSlowHTTPServer(*httpAddr, *httpDelay, *httpStatus, *httpBody)
+		if err != nil {
+			log.Fatalf("Failed to start slow HTTP server: %v", err)
+		}
+		defer server.Close()
+		fmt.Printf("✅ Slow HTTP endpoint at %s (delay %v, status %d)\n", server.URL(), *httpDelay, *httpStatus)
+	}
+
+	if *grpcAddr != "" {
+		server, err := testsupport.NewFakeGRPCHealthServer(*grpcAddr)
+		if err != nil {
+			log.Fatalf("Failed to start fake gRPC health service: %v", err)
+		}
+		defer server.Close()
+		if *grpcNotServing {
+			server.SetStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+		}
+		server.SetError(codes.Code(*grpcErrorCode))
+		fmt.Printf("✅ Fake gRPC health service on %s\n", server.Addr())
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	<-signals
+	fmt.Println("Fake dependencies stopped")
+}
@@ -0,0 +1,110 @@
+/**
+ * @fileoverview RFC 7807 problem details for every error response.
+ * Middleware, routing, and handlers report failures through this package so clients
+ * always receive application/problem+json with a type, title, status, detail, the
+ * request path as the instance, and the request ID to quote when reporting an issue.
+ */
+
+package apierror
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+)
+
+// ContentType is the media type of problem responses
+const ContentType = "application/problem+json"
+
+// DefaultType is the problem type used when a problem has no more specific one; it tells
+// clients the title is just the status text
+const DefaultType = "about:blank"
+
+// requestIDHeader matches middleware.RequestIDHeader, which sets it on the response
+const requestIDHeader = "X-Request-ID"
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	// Type is a URI identifying the kind of problem; DefaultType when unset
+	Type string `json:"type"`
+	// Title is a short summary of the kind of problem; the status text when unset
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	// Detail explains this occurrence of the problem
+	Detail string `json:"detail,omitempty"`
+	// Instance identifies this occurrence; the request path when unset
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Errors lists per-field problems, e.g. validate.Errors
+	Errors any `json:"errors,omitempty"`
+}
+
+/**
+ * @description Creates a problem with the default type and title for status.
+ */
+func New(status int, detail string) *Problem {
+	return &Problem{Status: status, Detail: detail}
+}
+
+/**
+ * @description Creates a problem with a detail formatted from format and args.
+ */
+func Newf(status int, format string, args ...any) *Problem {
+	return New(status, fmt.Sprintf(format, args...))
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return p.title()
+	}
+	return p.title() + ": " + p.Detail
+}
+
+/**
+ * @description Writes the problem as the response, filling the type, title, instance, and
+ * request ID when unset. r may be nil when the request is not available.
+ */
+func (p *Problem) Write(w http.ResponseWriter, r *http.Request) error {
+	problem := *p
+	problem.Type = p.typ()
+	problem.Title = p.title()
+	if problem.RequestID == "" {
+		problem.RequestID = w.Header().Get(requestIDHeader)
+	}
+	if r != nil {
+		if problem.Instance == "" {
+			problem.Instance = r.URL.Path
+		}
+		if problem.RequestID == "" {
+			problem.RequestID = r.Header.Get(requestIDHeader)
+		}
+	}
+	return jsoncase.WriteAs(w, problem.Status, ContentType, problem)
+}
+
+/**
+ * @description Writes a problem with the given status and detail as the response.
+ */
+func Write(w http.ResponseWriter, r *http.Request, status int, detail string) {
+	New(status, detail).Write(w, r)
+}
+
+// typ returns the problem type, defaulting to DefaultType
+func (p *Problem) typ() string {
+	if p.Type == "" {
+		return DefaultType
+	}
+	return p.Type
+}
+
+// title returns the problem title, defaulting to the status text
+func (p *Problem) title() string {
+	if p.Title != "" {
+		return p.Title
+	}
+	if text := http.StatusText(p.Status); text != "" {
+		return text
+	}
+	return fmt.Sprintf("HTTP %d", p.Status)
+}
@@ -0,0 +1,204 @@
+/**
+ * @fileoverview Application lifecycle manager.
+ * An App starts its components in registration order under a root context, runs
+ * background goroutines tied to that context, and on a signal or a background failure
+ * shuts down: stopping listeners are notified, components stop in reverse order, shutdown
+ * hooks run, and the root context is cancelled. A component that fails to start stops
+ * the ones already started, so main only declares what the application is made of.
+ */
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// DefaultGoroutineWait bounds how long Shutdown waits for background goroutines to return
+const DefaultGoroutineWait = 5 * time.Second
+
+// Component is one part of the application, e.g. configuration, stores, or the HTTP server
+type Component struct {
+	Name string
+	// Start prepares the component; ctx is the application's root context
+	Start func(ctx context.Context) error
+	// Stop releases the component; it is responsible for bounding its own duration
+	Stop func(ctx context.Context) error
+}
+
+// App owns component startup and shutdown order
+type App struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu         sync.Mutex
+	components []Component
+	started    []Component
+	stopping   []func(reason string)
+	hooks      []*shutdownHook
+	failed     chan error
+	goroutines sync.WaitGroup
+	shutdown   sync.Once
+	stopErr    error
+}
+
+/**
+ * @description Creates an application whose root context is cancelled at the end of shutdown.
+ */
+func New() *App {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &App{ctx: ctx, cancel: cancel, failed: make(chan error, 1)}
+}
+
+/**
+ * @description Returns the root context, cancelled once shutdown completes.
+ */
+func (a *App) Context() context.Context {
+	return a.ctx
+}
+
+/**
+ * @description Adds a component; components start in the order they are added and stop in reverse.
+ */
+func (a *App) Add(component Component) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.components = append(a.components, component)
+}
+
+/**
+ * @description Registers fn to run when shutdown begins, before any component stops,
+ * with the reason for shutting down.
+ */
+func (a *App) OnStopping(fn func(reason string)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.stopping = append(a.stopping, fn)
+}
+
+/**
+ * @description Runs fn in the background with the root context. A non-nil error returned
+ * before shutdown makes Run shut the application down and return it.
+ */
+func (a *App) Go(name string, fn func(ctx context.Context) error) {
+	a.goroutines.Add(1)
+	go func() {
+		defer a.goroutines.Done()
+		if err := fn(a.ctx); err != nil && a.ctx.Err() == nil {
+			select {
+			case a.failed <- fmt.Errorf("%s: %w", name, err):
+			default:
+			}
+		}
+	}()
+}
+
+/**
+ * @description Starts every component in order. When one fails, those already started are
+ * stopped in reverse order and the error is returned.
+ */
+func (a *App) Start() error {
+	a.mu.Lock()
+	components := append([]Component(nil), a.components...)
+	a.mu.Unlock()
+
+	for _, component := range components {
+		if component.Start != nil {
+			if err := component.Start(a.ctx); err != nil {
+				a.Shutdown("start failed")
+				return fmt.Errorf("failed to start %s: %w", component.Name, err)
+			}
+		}
+		a.mu.Lock()
+		a.started = append(a.started, component)
+		a.mu.Unlock()
+	}
+	return nil
+}
+
+/**
+ * @description Starts the application, waits for one of signals or a background failure,
+ * then shuts down. Returns start, background, and shutdown errors joined.
+ */
+func (a *App) Run(signals ...os.Signal) error {
+	// Listen before starting so a signal during startup is not lost
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, signals...)
+	defer signal.Stop(received)
+
+	if err := a.Start(); err != nil {
+		return err
+	}
+
+	var runErr error
+	reason := ""
+	select {
+	case sig := <-received:
+		reason = sig.String()
+		log.Printf("Received signal: %v. Initiating graceful shutdown...", sig)
+	case runErr = <-a.failed:
+		reason = "failure"
+		log.Printf("Shutting down after failure: %v", runErr)
+	}
+	return errors.Join(runErr, a.Shutdown(reason))
+}
+
+/**
+ * @description Notifies stopping listeners, stops started components in reverse order, runs
+ * shutdown hooks, then cancels the root context and waits briefly for background goroutines.
+ * Later calls return the first call's result.
+ */
+func (a *App) Shutdown(reason string) error {
+	a.shutdown.Do(func() {
+		a.mu.Lock()
+		stopping := append([]func(reason string){}, a.stopping...)
+		started := append([]Component(nil), a.started...)
+		a.mu.Unlock()
+
+		for _, fn := range stopping {
+			fn(reason)
+		}
+
+		var problems []error
+		for i := len(started) - 1; i >= 0; i-- {
+			component := started[i]
+			if component.Stop == nil {
+				continue
+			}
+			if err := component.Stop(context.Background()); err != nil {
+				problems = append(problems, fmt.Errorf("failed to stop %s: %w", component.Name, err))
+			}
+		}
+		if err := a.runShutdownHooks(); err != nil {
+			problems = append(problems, err)
+		}
+
+		a.cancel()
+		if !a.waitGoroutines(DefaultGoroutineWait) {
+			log.Printf("⚠️ Background goroutines still running %v after shutdown", DefaultGoroutineWait)
+		}
+		a.stopErr = errors.Join(problems...)
+	})
+	return a.stopErr
+}
+
+// waitGoroutines reports whether every goroutine started with Go returned within timeout
+func (a *App) waitGoroutines(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		a.goroutines.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
@@ -0,0 +1,150 @@
+/**
+ * @fileoverview Shutdown hooks.
+ * Hooks close resources that are not components of their own, such as secondary
+ * listeners, flushers, or pools created inside another component. They run after every
+ * component has stopped, in registration order or after the hooks they depend on, each
+ * under its own timeout. Failures are collected rather than stopping the remaining hooks.
+ */
+
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DefaultShutdownHookTimeout bounds each shutdown hook that does not set its own timeout
+const DefaultShutdownHookTimeout = 5 * time.Second
+
+// shutdownHook is one named cleanup step
+type shutdownHook struct {
+	name    string
+	fn      func(context.Context) error
+	timeout time.Duration
+	after   []string
+}
+
+// HookOption configures a hook registered with OnShutdown
+type HookOption func(*shutdownHook)
+
+/**
+ * @description Gives the hook timeout instead of DefaultShutdownHookTimeout.
+ */
+func HookTimeout(timeout time.Duration) HookOption {
+	return func(h *shutdownHook) {
+		h.timeout = timeout
+	}
+}
+
+/**
+ * @description Runs the hook only after the named hooks have finished; names that were
+ * never registered are ignored.
+ */
+func HookAfter(names ...string) HookOption {
+	return func(h *shutdownHook) {
+		h.after = append(h.after, names...)
+	}
+}
+
+/**
+ * @description Registers fn to run during shutdown once every component has stopped.
+ * Its context expires after the hook's timeout.
+ */
+func (a *App) OnShutdown(name string, fn func(ctx context.Context) error, opts ...HookOption) {
+	hook := &shutdownHook{name: name, fn: fn, timeout: DefaultShutdownHookTimeout}
+	for _, opt := range opts {
+		opt(hook)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.hooks = append(a.hooks, hook)
+}
+
+// runShutdownHooks runs every registered hook in order, returning all failures joined
+func (a *App) runShutdownHooks() error {
+	a.mu.Lock()
+	hooks := append([]*shutdownHook(nil), a.hooks...)
+	a.mu.Unlock()
+
+	ordered, err := shutdownOrder(hooks)
+	var problems []error
+	if err != nil {
+		problems = append(problems, err)
+	}
+
+	for _, hook := range ordered {
+		start := time.Now()
+		if err := runShutdownHook(hook); err != nil {
+			log.Printf("⚠️ Shutdown hook %s failed: %v", hook.name, err)
+			problems = append(problems, fmt.Errorf("shutdown hook %s: %w", hook.name, err))
+			continue
+		}
+		log.Printf("✅ Shutdown hook %s completed in %v", hook.name, time.Since(start).Round(time.Millisecond))
+	}
+	return errors.Join(problems...)
+}
+
+// runShutdownHook runs one hook, giving up once its timeout passes even if fn ignores ctx
+func runShutdownHook(hook *shutdownHook) error {
+	ctx, cancel := context.WithTimeout(context.Background(), hook.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- hook.fn(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("timed out after %v", hook.timeout)
+	}
+}
+
+// shutdownOrder sorts hooks so each runs after its dependencies, otherwise keeping
+// registration order. Hooks caught in a dependency cycle run last, in registration order.
+func shutdownOrder(hooks []*shutdownHook) ([]*shutdownHook, error) {
+	registered := make(map[string]bool, len(hooks))
+	for _, hook := range hooks {
+		registered[hook.name] = true
+	}
+
+	done := make(map[string]bool, len(hooks))
+	ordered := make([]*shutdownHook, 0, len(hooks))
+	remaining := hooks
+	for len(remaining) > 0 {
+		var blocked []*shutdownHook
+		for _, hook := range remaining {
+			if dependenciesDone(hook, registered, done) {
+				ordered = append(ordered, hook)
+				done[hook.name] = true
+			} else {
+				blocked = append(blocked, hook)
+			}
+		}
+		if len(blocked) == len(remaining) {
+			names := make([]string, len(blocked))
+			for i, hook := range blocked {
+				names[i] = hook.name
+			}
+			return append(ordered, blocked...), fmt.Errorf("shutdown hooks %v depend on each other", names)
+		}
+		remaining = blocked
+	}
+	return ordered, nil
+}
+
+// dependenciesDone reports whether every registered dependency of hook has run
+func dependenciesDone(hook *shutdownHook, registered, done map[string]bool) bool {
+	for _, name := range hook.after {
+		if registered[name] && !done[name] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+/**
+ * @fileoverview Build metadata for the running binary, injected at link time.
+ * Exposes the semantic version, git commit, build date, and Go runtime version so
+ * responses and logs can be correlated with the exact build that produced them.
+ */
+
+package buildinfo
+
+import (
+	"net/http"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/render"
+)
+
+// DefaultVersion is reported when no version was injected and the module version is unknown
+const DefaultVersion = "0.1.0"
+
+// Values below are overridden at build time, e.g.:
+//
+//	go build -ldflags "-X github.com/ashleywang1/new-ai-project-tutorial/pkg/buildinfo.GitCommit=$(git rev-parse HEAD)"
+var (
+	// Version is the semantic version of the release, without a leading "v"
+	Version = ""
+	// GitCommit is the git commit hash the binary was built from
+	GitCommit = ""
+	// BuildDate is the RFC 3339 timestamp of the build
+	BuildDate = ""
+)
+
+// Info describes the build that produced the running binary
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+/**
+ * @description Returns the build metadata for the running binary.
+ * Falls back to the VCS information embedded by the Go toolchain when ldflags were not set.
+ */
+func Get() Info {
+	info := Info{
+		Version:   strings.TrimPrefix(Version, "v"),
+		GitCommit: GitCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+
+	if info.Version != "" && info.GitCommit != "" && info.BuildDate != "" {
+		return info
+	}
+
+	if embedded, ok := debug.ReadBuildInfo(); ok {
+		// Binaries installed with go install carry their module version
+		if info.Version == "" && isRelease(embedded.Main.Version) {
+			info.Version = strings.TrimPrefix(embedded.Main.Version, "v")
+		}
+		for _, setting := range embedded.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.GitCommit == "":
+				info.GitCommit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = DefaultVersion
+	}
+
+	return info
+}
+
+// pseudoVersion matches the timestamp and revision suffix of Go pseudo-versions
+var pseudoVersion = regexp.MustCompile(`\d{14}-[0-9a-f]{12}(\+dirty)?$`)
+
+// isRelease reports whether a module version names a release rather than a local or pseudo-version build
+func isRelease(version string) bool {
+	return strings.HasPrefix(version, "v") && !pseudoVersion.MatchString(version) && !strings.HasSuffix(version, "+dirty")
+}
+
+/**
+ * @description Returns a one-line description such as "0.1.0 (commit abc1234, built 2024-01-02T03:04:05Z, go1.23.0)".
+ */
+func (i Info) String() string {
+	details := []string{}
+	if i.GitCommit != "" {
+		commit := i.GitCommit
+		if len(commit) > 12 {
+			commit = commit[:12]
+		}
+		details = append(details, "commit "+commit)
+	}
+	if i.BuildDate != "" {
+		details = append(details, "built "+i.BuildDate)
+	}
+	details = append(details, i.GoVersion)
+	return i.Version + " (" + strings.Join(details, ", ") + ")"
+}
+
+/**
+ * @description HTTP handler for the version endpoint, returning the build metadata in the negotiated format.
+ */
+func Handler(w http.ResponseWriter, r *http.Request) {
+	render.Write(w, r, http.StatusOK, Get())
+}
@@ -0,0 +1,584 @@
+/**
+ * @fileoverview Typed application configuration.
+ * Defines every setting the API server reads, its defaults, and validation. Values are
+ * layered by Load: defaults, then an optional YAML/JSON/TOML file, then environment
+ * variables named by each field's env tag.
+ */
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/id"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/llm"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/retention"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/routes"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/tlsutil"
+)
+
+// DefaultMaxBodyBytes is the request body limit applied when none is configured
+const DefaultMaxBodyBytes = 10 << 20
+
+// Bounds for HTTP server settings, rejecting values that are almost certainly mistakes
+const (
+	MaxServerTimeout     = time.Hour
+	MaxReadHeaderTimeout = 5 * time.Minute
+	MaxShutdownTimeout   = 10 * time.Minute
+	MinMaxHeaderBytes    = 4 << 10
+	MaxMaxHeaderBytes    = 16 << 20
+)
+
+// Log formats accepted by LoggingConfig
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+	// LogFormatCommon is accepted for access logs only
+	LogFormatCommon = "common"
+)
+
+// Duration is a time.Duration written as a Go duration string ("30s") in files and env
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Config is the complete API server configuration
+type Config struct {
+	Server   ServerConfig   `json:"server" yaml:"server" toml:"server"`
+	TLS      TLSConfig      `json:"tls" yaml:"tls" toml:"tls"`
+	Logging  LoggingConfig  `json:"logging" yaml:"logging" toml:"logging"`
+	Health   HealthConfig   `json:"health" yaml:"health" toml:"health"`
+	API      APIConfig      `json:"api" yaml:"api" toml:"api"`
+	Admin    AdminConfig    `json:"admin" yaml:"admin" toml:"admin"`
+	Limits   LimitsConfig   `json:"limits" yaml:"limits" toml:"limits"`
+	Store    StoreConfig    `json:"store" yaml:"store" toml:"store"`
+	Security SecurityConfig `json:"security" yaml:"security" toml:"security"`
+	Streams  StreamsConfig  `json:"streams" yaml:"streams" toml:"streams"`
+	Deadline DeadlineConfig `json:"deadline" yaml:"deadline" toml:"deadline"`
+	Proxy    ProxyConfig    `json:"proxy" yaml:"proxy" toml:"proxy"`
+	GRPC     GRPCConfig     `json:"grpc" yaml:"grpc" toml:"grpc"`
+	// Embeddings enables startup validation of embedding dimensions against the vector store
+	Embeddings EmbeddingsConfig `json:"embeddings" yaml:"embeddings" toml:"embeddings"`
+	LLM        LLMConfig        `json:"llm" yaml:"llm" toml:"llm"`
+	// Retention purges expired records of each data type after its maximum age
+	Retention RetentionConfig `json:"retention" yaml:"retention" toml:"retention"`
+	// RoutesFile is an optional JSON file of declarative routes
+	RoutesFile string `json:"routes_file" yaml:"routes_file" toml:"routes_file" env:"ROUTES_FILE"`
+}
+
+// ServerConfig covers listening and lifecycle
+type ServerConfig struct {
+	Port string `json:"port" yaml:"port" toml:"port" env:"PORT"`
+	// BindAddresses are ordered bind candidates that override Port when set
+	BindAddresses []string `json:"bind_addresses" yaml:"bind_addresses" toml:"bind_addresses" env:"BIND_ADDRESSES"`
+	// BindHost is the IP or hostname listened on for Port and bind addresses without a host; empty means every interface
+	BindHost string `json:"bind_host" yaml:"bind_host" toml:"bind_host" env:"BIND_HOST"`
+	// IPFamily restricts listeners to ipv4 or ipv6, or allows both with dual
+	IPFamily        string   `json:"ip_family" yaml:"ip_family" toml:"ip_family" env:"BIND_IP_FAMILY"`
+	Profile         string   `json:"profile" yaml:"profile" toml:"profile" env:"APP_PROFILE"`
+	ShutdownTimeout Duration `json:"shutdown_timeout" yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	// DrainDelay is how long readiness fails on SIGTERM before the server stops accepting connections
+	DrainDelay    Duration `json:"drain_delay" yaml:"drain_delay" toml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+	LBHintHeaders bool     `json:"lb_hint_headers" yaml:"lb_hint_headers" toml:"lb_hint_headers" env:"LB_HINT_HEADERS"`
+	// TrustedProxies are CIDRs or addresses whose forwarding headers identify the client
+	TrustedProxies []string `json:"trusted_proxies" yaml:"trusted_proxies" toml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+	// ReadTimeout, WriteTimeout, and IdleTimeout of zero disable the corresponding http.Server timeout
+	ReadTimeout       Duration `json:"read_timeout" yaml:"read_timeout" toml:"read_timeout" env:"HTTP_READ_TIMEOUT"`
+	ReadHeaderTimeout Duration `json:"read_header_timeout" yaml:"read_header_timeout" toml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT"`
+	WriteTimeout      Duration `json:"write_timeout" yaml:"write_timeout" toml:"write_timeout" env:"HTTP_WRITE_TIMEOUT"`
+	IdleTimeout       Duration `json:"idle_timeout" yaml:"idle_timeout" toml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT"`
+	MaxHeaderBytes    int      `json:"max_header_bytes" yaml:"max_header_bytes" toml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES"`
+	// MaxConnections and MaxConnectionsPerHost cap open connections in total and per client IP; zero disables
+	MaxConnections        int `json:"max_connections" yaml:"max_connections" toml:"max_connections" env:"HTTP_MAX_CONNECTIONS"`
+	MaxConnectionsPerHost int `json:"max_connections_per_host" yaml:"max_connections_per_host" toml:"max_connections_per_host" env:"HTTP_MAX_CONNECTIONS_PER_HOST"`
+	// KeepAlives lets clients reuse connections for further requests, closed after IdleTimeout
+	KeepAlives bool `json:"keepalives" yaml:"keepalives" toml:"keepalives" env:"HTTP_KEEPALIVES"`
+	TCPNoDelay bool `json:"tcp_nodelay" yaml:"tcp_nodelay" toml:"tcp_nodelay" env:"HTTP_TCP_NODELAY"`
+	// TCPKeepAlive is the TCP keep-alive probe period; zero keeps the system default and negative disables probes
+	TCPKeepAlive Duration `json:"tcp_keepalive" yaml:"tcp_keepalive" toml:"tcp_keepalive" env:"HTTP_TCP_KEEPALIVE"`
+	// HTTP3 serves the same handlers over QUIC alongside TCP and advertises it with Alt-Svc; requires TLS
+	HTTP3 bool `json:"http3" yaml:"http3" toml:"http3" env:"HTTP3_ENABLED"`
+	// HTTP3Address is the UDP address for HTTP/3; empty uses the address the TCP listener bound
+	HTTP3Address string `json:"http3_address" yaml:"http3_address" toml:"http3_address" env:"HTTP3_ADDRESS"`
+}
+
+// TLSConfig covers HTTPS serving and TLS material for outbound connections
+type TLSConfig struct {
+	// CertFile and KeyFile enable HTTPS on the server listener when both are set
+	CertFile string `json:"cert_file" yaml:"cert_file" toml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile  string `json:"key_file" yaml:"key_file" toml:"key_file" env:"TLS_KEY_FILE"`
+	// MinVersion is the lowest TLS version accepted when serving, 1.2 or 1.3
+	MinVersion string `json:"min_version" yaml:"min_version" toml:"min_version" env:"TLS_MIN_VERSION"`
+	// ACMEDomains enable HTTPS with certificates obtained and renewed automatically over ACME
+	ACMEDomains []string `json:"acme_domains" yaml:"acme_domains" toml:"acme_domains" env:"TLS_ACME_DOMAINS"`
+	// ACMECacheDir persists ACME account keys and certificates across restarts
+	ACMECacheDir string `json:"acme_cache_dir" yaml:"acme_cache_dir" toml:"acme_cache_dir" env:"TLS_ACME_CACHE_DIR"`
+	ACMEEmail    string `json:"acme_email" yaml:"acme_email" toml:"acme_email" env:"TLS_ACME_EMAIL"`
+	// ACMEDirectoryURL selects the ACME CA; empty uses Let's Encrypt production
+	ACMEDirectoryURL string `json:"acme_directory_url" yaml:"acme_directory_url" toml:"acme_directory_url" env:"TLS_ACME_DIRECTORY_URL"`
+	// ACMEHTTPAddress serves HTTP-01 challenges and redirects other plain HTTP requests to HTTPS
+	ACMEHTTPAddress string `json:"acme_http_address" yaml:"acme_http_address" toml:"acme_http_address" env:"TLS_ACME_HTTP_ADDRESS"`
+
+	UpstreamCABundle   string `json:"upstream_ca_bundle" yaml:"upstream_ca_bundle" toml:"upstream_ca_bundle" env:"UPSTREAM_CA_BUNDLE"`
+	UpstreamClientCert string `json:"upstream_client_cert" yaml:"upstream_client_cert" toml:"upstream_client_cert" env:"UPSTREAM_CLIENT_CERT"`
+	UpstreamClientKey  string `json:"upstream_client_key" yaml:"upstream_client_key" toml:"upstream_client_key" env:"UPSTREAM_CLIENT_KEY"`
+}
+
+// LoggingConfig covers log verbosity and encoding
+type LoggingConfig struct {
+	// Level is debug, info, warn, or error
+	Level string `json:"level" yaml:"level" toml:"level" env:"LOG_LEVEL"`
+	// Format is text or json
+	Format string `json:"format" yaml:"format" toml:"format" env:"LOG_FORMAT"`
+	// ErrorTrackerURL receives a JSON report of every recovered handler panic
+	ErrorTrackerURL string `json:"error_tracker_url" yaml:"error_tracker_url" toml:"error_tracker_url" env:"ERROR_TRACKER_URL"`
+	// AccessLogFormat is json or common; empty uses json with the json log format and common otherwise
+	AccessLogFormat string `json:"access_log_format" yaml:"access_log_format" toml:"access_log_format" env:"ACCESS_LOG_FORMAT"`
+	// AccessLogExclude lists paths left out of the access log; a trailing slash excludes a subtree
+	AccessLogExclude []string `json:"access_log_exclude" yaml:"access_log_exclude" toml:"access_log_exclude" env:"ACCESS_LOG_EXCLUDE"`
+}
+
+// HealthConfig covers the health checker, webhooks, and fleet aggregation
+type HealthConfig struct {
+	WebhookURLs        []string `json:"webhook_urls" yaml:"webhook_urls" toml:"webhook_urls" env:"HEALTH_WEBHOOK_URLS"`
+	WebhookSecret      string   `json:"webhook_secret" yaml:"webhook_secret" toml:"webhook_secret" env:"HEALTH_WEBHOOK_SECRET"`
+	HistorySize        int      `json:"history_size" yaml:"history_size" toml:"history_size" env:"HEALTH_HISTORY_SIZE"`
+	SlowCheckThreshold Duration `json:"slow_check_threshold" yaml:"slow_check_threshold" toml:"slow_check_threshold" env:"HEALTH_SLOW_CHECK_THRESHOLD"`
+	FleetPeers         []string `json:"fleet_peers" yaml:"fleet_peers" toml:"fleet_peers" env:"FLEET_PEERS"`
+	FleetMinHealthy    int      `json:"fleet_min_healthy" yaml:"fleet_min_healthy" toml:"fleet_min_healthy" env:"FLEET_MIN_HEALTHY"`
+}
+
+// APIConfig covers response encoding, identifiers, versioning, and idempotency
+type APIConfig struct {
+	JSONFieldCase string `json:"json_field_case" yaml:"json_field_case" toml:"json_field_case" env:"JSON_FIELD_CASE"`
+	IDFormat      string `json:"id_format" yaml:"id_format" toml:"id_format" env:"ID_FORMAT"`
+	// DeprecatedVersions and SunsetVersions hold "v1=YYYY-MM-DD" entries dating each version's
+	// deprecation and removal, announced to clients in response headers
+	DeprecatedVersions []string `json:"deprecated_versions" yaml:"deprecated_versions" toml:"deprecated_versions" env:"API_DEPRECATED_VERSIONS"`
+	SunsetVersions     []string `json:"sunset_versions" yaml:"sunset_versions" toml:"sunset_versions" env:"API_SUNSET_VERSIONS"`
+	// DeprecationLink is linked from deprecated versions' responses as migration documentation
+	DeprecationLink string `json:"deprecation_link" yaml:"deprecation_link" toml:"deprecation_link" env:"API_DEPRECATION_LINK"`
+	// IdempotencyTTL is how long responses to POST and PATCH requests with an Idempotency-Key
+	// are replayed to retries; zero disables Idempotency-Key handling
+	IdempotencyTTL Duration `json:"idempotency_ttl" yaml:"idempotency_ttl" toml:"idempotency_ttl" env:"IDEMPOTENCY_TTL"`
+}
+
+// GRPCConfig covers the embedded gRPC server, which is off unless an address or multiplexing is set
+type GRPCConfig struct {
+	// Address serves gRPC on its own listener, e.g. ":9000"
+	Address string `json:"address" yaml:"address" toml:"address" env:"GRPC_ADDRESS"`
+	// Multiplex serves gRPC on the HTTP listener, told apart by content type; requires plaintext HTTP
+	Multiplex bool `json:"multiplex" yaml:"multiplex" toml:"multiplex" env:"GRPC_MULTIPLEX"`
+}
+
+// AdminConfig covers operator-only endpoints
+type AdminConfig struct {
+	// Token enables admin endpoints when set
+	Token string `json:"token" yaml:"token" toml:"token" env:"ADMIN_TOKEN"`
+	// Address serves health, metrics, debug, and admin endpoints on a separate listener
+	// instead of the application port when set, e.g. ":9090"
+	Address string `json:"address" yaml:"address" toml:"address" env:"ADMIN_ADDRESS"`
+}
+
+// LimitsConfig covers request admission, body sizes, and response size warnings; zero disables a limit
+type LimitsConfig struct {
+	MaxConcurrentRequests int   `json:"max_concurrent_requests" yaml:"max_concurrent_requests" toml:"max_concurrent_requests" env:"MAX_CONCURRENT_REQUESTS"`
+	ResponseSizeWarnBytes int64 `json:"response_size_warn_bytes" yaml:"response_size_warn_bytes" toml:"response_size_warn_bytes" env:"RESPONSE_SIZE_WARN_BYTES"`
+	// MaxBodyBytes limits request bodies on every route without an override; zero disables it
+	MaxBodyBytes int64 `json:"max_body_bytes" yaml:"max_body_bytes" toml:"max_body_bytes" env:"MAX_BODY_BYTES"`
+	// RateLimitRPS is the sustained requests per second allowed per client
+	RateLimitRPS float64 `json:"rate_limit_rps" yaml:"rate_limit_rps" toml:"rate_limit_rps" env:"RATE_LIMIT_RPS"`
+	// RateLimitBurst is how many requests a client may make at once; zero uses the rate rounded up
+	RateLimitBurst int `json:"rate_limit_burst" yaml:"rate_limit_burst" toml:"rate_limit_burst" env:"RATE_LIMIT_BURST"`
+	// RequestTimeout bounds handler execution on every route without an override; zero disables it
+	RequestTimeout Duration `json:"request_timeout" yaml:"request_timeout" toml:"request_timeout" env:"REQUEST_TIMEOUT"`
+}
+
+// SecurityConfig covers security response headers; empty values use the middleware defaults
+type SecurityConfig struct {
+	// HSTSMaxAge is advertised on HTTPS responses; a negative value disables HSTS
+	HSTSMaxAge            Duration `json:"hsts_max_age" yaml:"hsts_max_age" toml:"hsts_max_age" env:"SECURITY_HSTS_MAX_AGE"`
+	HSTSIncludeSubdomains bool     `json:"hsts_include_subdomains" yaml:"hsts_include_subdomains" toml:"hsts_include_subdomains" env:"SECURITY_HSTS_INCLUDE_SUBDOMAINS"`
+	ContentSecurityPolicy string   `json:"content_security_policy" yaml:"content_security_policy" toml:"content_security_policy" env:"SECURITY_CONTENT_SECURITY_POLICY"`
+	FrameOptions          string   `json:"frame_options" yaml:"frame_options" toml:"frame_options" env:"SECURITY_FRAME_OPTIONS"`
+	ReferrerPolicy        string   `json:"referrer_policy" yaml:"referrer_policy" toml:"referrer_policy" env:"SECURITY_REFERRER_POLICY"`
+}
+
+// StoreConfig selects the backend shared by rate limiting and idempotency state
+type StoreConfig struct {
+	// Backend is memory (default, per instance), or redis or sql (shared across instances)
+	Backend       string `json:"backend" yaml:"backend" toml:"backend" env:"STORE_BACKEND"`
+	RedisAddr     string `json:"redis_addr" yaml:"redis_addr" toml:"redis_addr" env:"REDIS_ADDR"`
+	RedisPassword string `json:"redis_password" yaml:"redis_password" toml:"redis_password" env:"REDIS_PASSWORD"`
+	RedisDB       int    `json:"redis_db" yaml:"redis_db" toml:"redis_db" env:"REDIS_DB"`
+	// SQLDialect is postgres or mysql for the sql backend
+	SQLDialect string `json:"sql_dialect" yaml:"sql_dialect" toml:"sql_dialect" env:"STORE_SQL_DIALECT"`
+	// SQLDSN is the driver's connection string for the sql backend
+	SQLDSN string `json:"sql_dsn" yaml:"sql_dsn" toml:"sql_dsn" env:"STORE_SQL_DSN"`
+	// KeyPrefix namespaces keys when the backend is shared with other services
+	KeyPrefix string `json:"key_prefix" yaml:"key_prefix" toml:"key_prefix" env:"STORE_KEY_PREFIX"`
+}
+
+// StreamsConfig covers long-running streaming connections; zero disables a limit
+type StreamsConfig struct {
+	MaxPerClient int      `json:"max_per_client" yaml:"max_per_client" toml:"max_per_client" env:"STREAM_MAX_PER_CLIENT"`
+	IdleTimeout  Duration `json:"idle_timeout" yaml:"idle_timeout" toml:"idle_timeout" env:"STREAM_IDLE_TIMEOUT"`
+	// WebSocketOrigins lists origin host patterns allowed to open WebSockets besides the server's own host
+	WebSocketOrigins []string `json:"websocket_origins" yaml:"websocket_origins" toml:"websocket_origins" env:"WEBSOCKET_ALLOWED_ORIGINS"`
+	// WebSocketPingInterval is how often WebSockets are pinged to detect dead peers; zero uses the default
+	WebSocketPingInterval Duration `json:"websocket_ping_interval" yaml:"websocket_ping_interval" toml:"websocket_ping_interval" env:"WEBSOCKET_PING_INTERVAL"`
+}
+
+// DeadlineConfig covers request deadline budgets; zero uses the package defaults
+type DeadlineConfig struct {
+	Margin  Duration `json:"margin" yaml:"margin" toml:"margin" env:"REQUEST_DEADLINE_MARGIN"`
+	Default Duration `json:"default" yaml:"default" toml:"default" env:"REQUEST_DEADLINE_DEFAULT"`
+	Max     Duration `json:"max" yaml:"max" toml:"max" env:"REQUEST_DEADLINE_MAX"`
+}
+
+// ProxyConfig fronts an existing backend under a path prefix; proxy mode is off without a target
+type ProxyConfig struct {
+	Target string `json:"target" yaml:"target" toml:"target" env:"PROXY_TARGET"`
+	// Prefix is the path forwarded to the backend, ending in "/"
+	Prefix string `json:"prefix" yaml:"prefix" toml:"prefix" env:"PROXY_PREFIX"`
+	// Rewrite replaces Prefix in the forwarded path; empty strips it
+	Rewrite string `json:"rewrite" yaml:"rewrite" toml:"rewrite" env:"PROXY_REWRITE"`
+	// ForwardHeaders limits the request headers passed to the backend; empty passes all of them
+	ForwardHeaders []string `json:"forward_headers" yaml:"forward_headers" toml:"forward_headers" env:"PROXY_FORWARD_HEADERS"`
+	// Timeout bounds each proxied request, overriding limits.request_timeout; zero uses it
+	Timeout Duration `json:"timeout" yaml:"timeout" toml:"timeout" env:"PROXY_TIMEOUT"`
+}
+
+/**
+ * @description Returns the configuration used when no file or environment overrides apply.
+ */
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:              "8080",
+			IPFamily:          IPFamilyDual,
+			Profile:           "dev",
+			ShutdownTimeout:   Duration(30 * time.Second),
+			DrainDelay:        Duration(5 * time.Second),
+			ReadTimeout:       Duration(15 * time.Second),
+			ReadHeaderTimeout: Duration(5 * time.Second),
+			WriteTimeout:      Duration(15 * time.Second),
+			IdleTimeout:       Duration(60 * time.Second),
+			MaxHeaderBytes:    1 << 20,
+			KeepAlives:        true,
+			TCPNoDelay:        true,
+		},
+		TLS:     TLSConfig{MinVersion: "1.2", ACMEHTTPAddress: ":80"},
+		Logging: LoggingConfig{Level: "info", Format: LogFormatText},
+		Health:  HealthConfig{FleetMinHealthy: 1},
+		Limits:  LimitsConfig{MaxBodyBytes: DefaultMaxBodyBytes},
+		Proxy:   ProxyConfig{Prefix: "/"},
+		LLM:     LLMConfig{BreakerCooldown: Duration(llm.DefaultBreakerCooldown)},
+		// Expired idempotency keys in the memory store are kept an hour past their TTL
+		Retention: RetentionConfig{
+			Policies: []string{retention.TypeIdempotencyKeys + "=1h"},
+			Interval: Duration(retention.DefaultInterval),
+		},
+	}
+}
+
+/**
+ * @description Checks every setting, returning all problems joined into one error.
+ */
+func (c Config) Validate() error {
+	var problems []error
+	invalid := func(field string, format string, args ...any) {
+		problems = append(problems, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 0 || port > 65535 {
+		invalid("server.port", "%q is not a valid port", c.Server.Port)
+	}
+	c.validateNetwork(invalid)
+	c.Embeddings.validate(invalid)
+	c.LLM.validate(invalid)
+	c.Retention.validate(invalid)
+	if _, err := httputil.ParseTrustedProxies(c.Server.TrustedProxies); err != nil {
+		invalid("server.trusted_proxies", "%v", err)
+	}
+	if c.Server.Profile == "" {
+		invalid("server.profile", "must not be empty")
+	}
+	if c.Server.ShutdownTimeout <= 0 || time.Duration(c.Server.ShutdownTimeout) > MaxShutdownTimeout {
+		invalid("server.shutdown_timeout", "must be positive and at most %v", MaxShutdownTimeout)
+	}
+	serverTimeouts := []struct {
+		field string
+		value Duration
+	}{
+		{"server.read_timeout", c.Server.ReadTimeout},
+		{"server.write_timeout", c.Server.WriteTimeout},
+		{"server.idle_timeout", c.Server.IdleTimeout},
+	}
+	for _, timeout := range serverTimeouts {
+		if timeout.value < 0 || time.Duration(timeout.value) > MaxServerTimeout {
+			invalid(timeout.field, "must be between 0 (disabled) and %v", MaxServerTimeout)
+		}
+	}
+	// A missing header timeout leaves the server open to slow-header attacks, so it cannot be disabled
+	if c.Server.ReadHeaderTimeout <= 0 || time.Duration(c.Server.ReadHeaderTimeout) > MaxReadHeaderTimeout {
+		invalid("server.read_header_timeout", "must be positive and at most %v", MaxReadHeaderTimeout)
+	} else if c.Server.ReadTimeout > 0 && c.Server.ReadHeaderTimeout > c.Server.ReadTimeout {
+		invalid("server.read_header_timeout", "must not exceed server.read_timeout")
+	}
+	if c.Server.MaxHeaderBytes < MinMaxHeaderBytes || c.Server.MaxHeaderBytes > MaxMaxHeaderBytes {
+		invalid("server.max_header_bytes", "must be between %d and %d", MinMaxHeaderBytes, MaxMaxHeaderBytes)
+	}
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		invalid("tls.key_file", "server certificate and key must be set together")
+	}
+	if len(c.TLS.ACMEDomains) > 0 {
+		if c.TLS.CertFile != "" {
+			invalid("tls.acme_domains", "cannot be combined with tls.cert_file")
+		}
+		if c.TLS.ACMECacheDir == "" {
+			invalid("tls.acme_cache_dir", "is required with tls.acme_domains")
+		}
+	}
+	if c.Server.HTTP3 && c.TLS.CertFile == "" && len(c.TLS.ACMEDomains) == 0 {
+		invalid("server.http3", "requires tls.cert_file or tls.acme_domains")
+	}
+	if _, err := tlsutil.ParseMinVersion(c.TLS.MinVersion); err != nil {
+		invalid("tls.min_version", "%v", err)
+	}
+	if c.TLS.UpstreamCABundle == "" && (c.TLS.UpstreamClientCert != "" || c.TLS.UpstreamClientKey != "") {
+		invalid("tls.upstream_client_cert", "requires tls.upstream_ca_bundle")
+	}
+	if (c.TLS.UpstreamClientCert == "") != (c.TLS.UpstreamClientKey == "") {
+		invalid("tls.upstream_client_key", "client certificate and key must be set together")
+	}
+
+	if _, err := ParseLogLevel(c.Logging.Level); err != nil {
+		invalid("logging.level", "%v", err)
+	}
+	if c.Logging.Format != LogFormatText && c.Logging.Format != LogFormatJSON {
+		invalid("logging.format", "%q must be text or json", c.Logging.Format)
+	}
+	switch c.Logging.AccessLogFormat {
+	case "", LogFormatJSON, LogFormatCommon:
+	default:
+		invalid("logging.access_log_format", "%q must be json or common", c.Logging.AccessLogFormat)
+	}
+
+	if _, err := jsoncase.ParseStyle(c.API.JSONFieldCase); err != nil {
+		invalid("api.json_field_case", "%v", err)
+	}
+	if _, err := id.ParseFormat(c.API.IDFormat); err != nil {
+		invalid("api.id_format", "%v", err)
+	}
+	if _, err := routes.ParseVersions(c.API.DeprecatedVersions, c.API.SunsetVersions, c.API.DeprecationLink); err != nil {
+		invalid("api", "%v", err)
+	}
+
+	for _, origin := range c.Streams.WebSocketOrigins {
+		if _, err := path.Match(origin, ""); err != nil {
+			invalid("streams.websocket_origins", "%q is not a valid pattern: %v", origin, err)
+		}
+	}
+
+	if c.GRPC.Multiplex {
+		if c.GRPC.Address != "" {
+			invalid("grpc.multiplex", "cannot be combined with grpc.address")
+		}
+		if c.TLS.CertFile != "" || len(c.TLS.ACMEDomains) > 0 {
+			invalid("grpc.multiplex", "requires plaintext HTTP; serve gRPC on grpc.address when TLS is enabled")
+		}
+	}
+
+	if c.Proxy.Target != "" {
+		if err := c.ProxyGroup().Validate(); err != nil {
+			invalid("proxy", "%v", err)
+		}
+	}
+
+	if c.Limits.RateLimitRPS < 0 {
+		invalid("limits.rate_limit_rps", "must not be negative")
+	}
+	switch c.Store.Backend {
+	case "", "memory":
+	case "redis":
+		if c.Store.RedisAddr == "" {
+			invalid("store.redis_addr", "is required with the redis backend")
+		}
+	case "sql":
+		if c.Store.SQLDialect != "postgres" && c.Store.SQLDialect != "mysql" {
+			invalid("store.sql_dialect", "%q must be postgres or mysql", c.Store.SQLDialect)
+		}
+		if c.Store.SQLDSN == "" {
+			invalid("store.sql_dsn", "is required with the sql backend")
+		}
+	default:
+		invalid("store.backend", "%q must be memory, redis, or sql", c.Store.Backend)
+	}
+
+	nonNegative := []struct {
+		field string
+		value int64
+	}{
+		{"server.drain_delay", int64(c.Server.DrainDelay)},
+		{"server.max_connections", int64(c.Server.MaxConnections)},
+		{"server.max_connections_per_host", int64(c.Server.MaxConnectionsPerHost)},
+		{"health.history_size", int64(c.Health.HistorySize)},
+		{"health.slow_check_threshold", int64(c.Health.SlowCheckThreshold)},
+		{"health.fleet_min_healthy", int64(c.Health.FleetMinHealthy)},
+		{"api.idempotency_ttl", int64(c.API.IdempotencyTTL)},
+		{"limits.max_concurrent_requests", int64(c.Limits.MaxConcurrentRequests)},
+		{"limits.response_size_warn_bytes", c.Limits.ResponseSizeWarnBytes},
+		{"limits.max_body_bytes", c.Limits.MaxBodyBytes},
+		{"limits.rate_limit_burst", int64(c.Limits.RateLimitBurst)},
+		{"limits.request_timeout", int64(c.Limits.RequestTimeout)},
+		{"store.redis_db", int64(c.Store.RedisDB)},
+		{"streams.max_per_client", int64(c.Streams.MaxPerClient)},
+		{"streams.idle_timeout", int64(c.Streams.IdleTimeout)},
+		{"streams.websocket_ping_interval", int64(c.Streams.WebSocketPingInterval)},
+		{"deadline.margin", int64(c.Deadline.Margin)},
+		{"deadline.default", int64(c.Deadline.Default)},
+		{"deadline.max", int64(c.Deadline.Max)},
+		{"proxy.timeout", int64(c.Proxy.Timeout)},
+	}
+	for _, setting := range nonNegative {
+		if setting.value < 0 {
+			invalid(setting.field, "must not be negative")
+		}
+	}
+
+	return errors.Join(problems...)
+}
+
+// urlSecretParams are query parameters that carry credentials without a sensitive-looking name
+var urlSecretParams = map[string]bool{"key": true, "sig": true, "signature": true, "auth": true, "code": true}
+
+/**
+ * @description Returns a copy safe to expose in diagnostics, with secrets replaced by Redacted,
+ * including the credentials embedded in URL-valued settings.
+ */
+func (c Config) Redacted() Config {
+	if c.Admin.Token != "" {
+		c.Admin.Token = Redacted
+	}
+	if c.Health.WebhookSecret != "" {
+		c.Health.WebhookSecret = Redacted
+	}
+	c.Logging.ErrorTrackerURL = RedactURL(c.Logging.ErrorTrackerURL)
+	c.Proxy.Target = RedactURL(c.Proxy.Target)
+	c.Embeddings.QdrantURL = RedactURL(c.Embeddings.QdrantURL)
+	if len(c.Health.WebhookURLs) > 0 {
+		webhooks := make([]string, len(c.Health.WebhookURLs))
+		for i, webhook := range c.Health.WebhookURLs {
+			webhooks[i] = RedactURL(webhook)
+		}
+		c.Health.WebhookURLs = webhooks
+	}
+	if len(c.Health.FleetPeers) > 0 {
+		c.Health.FleetPeers = redactPeers(c.Health.FleetPeers)
+	}
+	if c.Store.RedisPassword != "" {
+		c.Store.RedisPassword = Redacted
+	}
+	if c.Store.SQLDSN != "" {
+		c.Store.SQLDSN = Redacted
+	}
+	if c.Embeddings.QdrantAPIKey != "" {
+		c.Embeddings.QdrantAPIKey = Redacted
+	}
+	c.LLM = c.LLM.redacted()
+	return c
+}
+
+/**
+ * @description Strips the userinfo of a URL and replaces the values of query parameters that
+ * look like secrets with Redacted, keeping the URL valid. Values that do not parse as URLs
+ * are redacted whole.
+ */
+func RedactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return Redacted
+	}
+
+	parsed.User = nil
+	if parsed.RawQuery != "" {
+		params := strings.Split(parsed.RawQuery, "&")
+		for i, param := range params {
+			name, _, _ := strings.Cut(param, "=")
+			if unescaped, err := url.QueryUnescape(name); err == nil && (IsSensitive(unescaped) || urlSecretParams[strings.ToLower(unescaped)]) {
+				params[i] = name + "=" + Redacted
+			}
+		}
+		parsed.RawQuery = strings.Join(params, "&")
+	}
+
+	return parsed.String()
+}
+
+// redactPeers redacts the URLs of "url" or "name=url" fleet peers
+func redactPeers(entries []string) []string {
+	peers := make([]string, len(entries))
+	for i, peer := range entries {
+		if name, peerURL, ok := strings.Cut(peer, "="); ok && !strings.Contains(name, "://") {
+			peers[i] = name + "=" + RedactURL(peerURL)
+		} else {
+			peers[i] = RedactURL(peer)
+		}
+	}
+	return peers
+}
+
+/**
+ * @description Returns the reverse proxy route group described by the proxy settings.
+ */
+func (c Config) ProxyGroup() routes.ProxyGroup {
+	return routes.ProxyGroup{
+		Target:         c.Proxy.Target,
+		Prefix:         c.Proxy.Prefix,
+		Rewrite:        c.Proxy.Rewrite,
+		ForwardHeaders: c.Proxy.ForwardHeaders,
+		Timeout:        time.Duration(c.Proxy.Timeout),
+	}
+}
+
+/**
+ * @description Parses a log level name (debug, info, warn, error); empty means info.
+ */
+func ParseLogLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q (expected debug, info, warn, or error)", name)
+	}
+}
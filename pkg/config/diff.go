@@ -0,0 +1,127 @@
+/**
+ * @fileoverview Structured, redacted diffs between configuration snapshots.
+ * Configuration is flattened to dotted keys so any struct or map can be compared,
+ * and values under sensitive-looking keys are never included in a diff.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Change kinds reported in a diff
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// Redacted replaces sensitive values in diffs
+const Redacted = "[REDACTED]"
+
+// sensitiveWords mark keys whose values must never be logged or exposed
+var sensitiveWords = []string{"password", "secret", "token", "credential", "private", "apikey", "api_key", "access_key"}
+
+// Change is one difference between two snapshots
+type Change struct {
+	Key  string `json:"key"`
+	Kind string `json:"kind"`
+	Old  string `json:"old,omitempty"`
+	New  string `json:"new,omitempty"`
+}
+
+/**
+ * @description Flattens a struct, map, or slice into dotted keys using its JSON encoding.
+ * Nested objects become "parent.child" and array elements "parent[0]".
+ */
+func Flatten(v any) (map[string]string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to decode configuration: %w", err)
+	}
+
+	flat := make(map[string]string)
+	flattenInto(flat, "", decoded)
+	return flat, nil
+}
+
+/**
+ * @description Compares two flattened snapshots, returning changes sorted by key
+ * with sensitive values redacted.
+ */
+func Diff(old, new map[string]string) []Change {
+	var changes []Change
+	for key, oldValue := range old {
+		newValue, ok := new[key]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Key: key, Kind: ChangeRemoved, Old: oldValue})
+		case newValue != oldValue:
+			changes = append(changes, Change{Key: key, Kind: ChangeChanged, Old: oldValue, New: newValue})
+		}
+	}
+	for key, newValue := range new {
+		if _, ok := old[key]; !ok {
+			changes = append(changes, Change{Key: key, Kind: ChangeAdded, New: newValue})
+		}
+	}
+
+	for i := range changes {
+		if IsSensitive(changes[i].Key) {
+			changes[i].Old = redact(changes[i].Old)
+			changes[i].New = redact(changes[i].New)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes
+}
+
+/**
+ * @description Reports whether a key names a secret whose value must be redacted.
+ */
+func IsSensitive(key string) bool {
+	lower := strings.ToLower(key)
+	for _, word := range sensitiveWords {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redact hides a non-empty value
+func redact(value string) string {
+	if value == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// flattenInto walks decoded JSON, writing leaf values under dotted keys
+func flattenInto(flat map[string]string, prefix string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			flattenInto(flat, key, child)
+		}
+	case []any:
+		for i, child := range v {
+			flattenInto(flat, fmt.Sprintf("%s[%d]", prefix, i), child)
+		}
+	case nil:
+		flat[prefix] = "null"
+	default:
+		flat[prefix] = fmt.Sprint(v)
+	}
+}
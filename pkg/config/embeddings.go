@@ -0,0 +1,76 @@
+/**
+ * @fileoverview Embedding model and vector collection settings.
+ * When a Qdrant server is configured, the server checks at startup, and again on every
+ * readiness probe, that each embedding model's dimensions match the vector size of the
+ * collection it writes to, so a mismatch fails fast instead of returning garbage matches.
+ */
+
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// EmbeddingsConfig describes the embedding models and the Qdrant server storing their vectors
+type EmbeddingsConfig struct {
+	// QdrantURL enables dimension validation of every model against its collection on that server
+	QdrantURL    string `json:"qdrant_url" yaml:"qdrant_url" toml:"qdrant_url" env:"QDRANT_URL"`
+	QdrantAPIKey string `json:"qdrant_api_key" yaml:"qdrant_api_key" toml:"qdrant_api_key" env:"QDRANT_API_KEY"`
+	// Models holds "model=collection:dimensions" entries, one per embedding model in use
+	Models []string `json:"models" yaml:"models" toml:"models" env:"EMBEDDING_MODELS"`
+}
+
+// EmbeddingModel is one embedding model and the collection its vectors are stored in
+type EmbeddingModel struct {
+	Model      string
+	Collection string
+	Dimensions int
+}
+
+/**
+ * @description Parses "model=collection:dimensions" entries, e.g.
+ * "text-embedding-3-small=docs:1536". Each model may appear only once.
+ */
+func ParseEmbeddingModels(entries []string) ([]EmbeddingModel, error) {
+	models := make([]EmbeddingModel, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		model, target, ok := strings.Cut(entry, "=")
+		separator := strings.LastIndex(target, ":")
+		if !ok || model == "" || separator <= 0 {
+			return nil, fmt.Errorf("invalid embedding model %q: expected model=collection:dimensions", entry)
+		}
+		dimensions, err := strconv.Atoi(target[separator+1:])
+		if err != nil || dimensions <= 0 {
+			return nil, fmt.Errorf("invalid dimensions in embedding model %q: must be a positive integer", entry)
+		}
+		if seen[model] {
+			return nil, fmt.Errorf("embedding model %s is listed more than once", model)
+		}
+		seen[model] = true
+		models = append(models, EmbeddingModel{Model: model, Collection: target[:separator], Dimensions: dimensions})
+	}
+	return models, nil
+}
+
+// validate reports incomplete embedding settings
+func (e EmbeddingsConfig) validate(invalid func(field string, format string, args ...any)) {
+	if _, err := ParseEmbeddingModels(e.Models); err != nil {
+		invalid("embeddings.models", "%v", err)
+	}
+	if e.QdrantURL == "" {
+		if len(e.Models) > 0 {
+			invalid("embeddings.models", "requires embeddings.qdrant_url")
+		}
+		return
+	}
+	if parsed, err := url.Parse(e.QdrantURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		invalid("embeddings.qdrant_url", "%q is not a valid URL", e.QdrantURL)
+	}
+	if len(e.Models) == 0 {
+		invalid("embeddings.models", "is required with embeddings.qdrant_url")
+	}
+}
@@ -0,0 +1,101 @@
+/**
+ * @fileoverview Audit history of applied configuration reloads.
+ * Keeps a bounded list of redacted diffs with the subsystems each reload re-initialized,
+ * served at /admin/config/history so config-related incidents can be traced.
+ */
+
+package config
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+)
+
+// DefaultHistorySize is the number of reloads retained
+const DefaultHistorySize = 50
+
+// Reload records one applied configuration change
+type Reload struct {
+	ID         int      `json:"id"`
+	Timestamp  string   `json:"timestamp"`
+	Source     string   `json:"source"`
+	Subsystems []string `json:"subsystems"`
+	Changes    []Change `json:"changes"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// HistoryResponse is the JSON body served by Handler
+type HistoryResponse struct {
+	Reloads []Reload `json:"reloads"`
+}
+
+// History is a bounded, concurrency-safe list of reloads
+type History struct {
+	mu      sync.Mutex
+	size    int
+	nextID  int
+	reloads []Reload
+}
+
+/**
+ * @description Creates a history retaining the last size reloads; non-positive uses DefaultHistorySize.
+ */
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &History{size: size}
+}
+
+/**
+ * @description Appends a reload; reloadErr records an attempt that was rejected and left config unchanged.
+ */
+func (h *History) Record(source string, subsystems []string, changes []Change, reloadErr error) Reload {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.nextID++
+	reload := Reload{
+		ID:         h.nextID,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Source:     source,
+		Subsystems: subsystems,
+		Changes:    changes,
+	}
+	if reloadErr != nil {
+		reload.Error = reloadErr.Error()
+	}
+	if reload.Changes == nil {
+		reload.Changes = []Change{}
+	}
+
+	h.reloads = append(h.reloads, reload)
+	if len(h.reloads) > h.size {
+		h.reloads = h.reloads[len(h.reloads)-h.size:]
+	}
+	return reload
+}
+
+/**
+ * @description Returns retained reloads, newest first.
+ */
+func (h *History) Reloads() []Reload {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	reloads := make([]Reload, len(h.reloads))
+	for i, reload := range h.reloads {
+		reloads[len(h.reloads)-1-i] = reload
+	}
+	return reloads
+}
+
+/**
+ * @description Serves the reload history as JSON.
+ */
+func (h *History) Handler(w http.ResponseWriter, r *http.Request) {
+	jsoncase.Write(w, http.StatusOK, HistoryResponse{Reloads: h.Reloads()})
+}
@@ -0,0 +1,98 @@
+/**
+ * @fileoverview Completion provider settings.
+ * Selects a provider registered with pkg/llm by name and passes it "key=value" options,
+ * so the provider behind the completion endpoints is chosen by configuration, and prices
+ * models so each completion's estimated cost can be tracked. A circuit breaker with
+ * fallbacks keeps answering, flagged as degraded, while the provider is down.
+ */
+
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/llm"
+)
+
+// LLMConfig selects the provider serving /v1/completions
+type LLMConfig struct {
+	// Provider is a registered provider name, e.g. mock; empty disables the completion endpoints
+	Provider string `json:"provider" yaml:"provider" toml:"provider" env:"LLM_PROVIDER"`
+	// Options holds "key=value" provider options, e.g. "mode=scripted"
+	Options []string `json:"options" yaml:"options" toml:"options" env:"LLM_OPTIONS"`
+	// Model is used for requests that do not name one
+	Model string `json:"model" yaml:"model" toml:"model" env:"LLM_MODEL"`
+	// Pricing holds "model=prompt:completion" prices in USD per million tokens
+	Pricing []string `json:"pricing" yaml:"pricing" toml:"pricing" env:"LLM_PRICING"`
+	// CostHeaders adds each completion's estimated cost to its response
+	CostHeaders bool `json:"cost_headers" yaml:"cost_headers" toml:"cost_headers" env:"LLM_COST_HEADERS"`
+	// BreakerFailures is how many consecutive retryable failures open the provider's circuit
+	// breaker, after which fallbacks answer instead; zero disables the breaker
+	BreakerFailures int      `json:"breaker_failures" yaml:"breaker_failures" toml:"breaker_failures" env:"LLM_BREAKER_FAILURES"`
+	BreakerCooldown Duration `json:"breaker_cooldown" yaml:"breaker_cooldown" toml:"breaker_cooldown" env:"LLM_BREAKER_COOLDOWN"`
+	// FallbackCache answers with the last answer to an identical request while the breaker is open
+	FallbackCache bool `json:"fallback_cache" yaml:"fallback_cache" toml:"fallback_cache" env:"LLM_FALLBACK_CACHE"`
+	// FallbackModel is tried, e.g. a cheaper model, when no cached answer exists
+	FallbackModel string `json:"fallback_model" yaml:"fallback_model" toml:"fallback_model" env:"LLM_FALLBACK_MODEL"`
+	// FallbackMessage is answered when no other fallback succeeds
+	FallbackMessage string `json:"fallback_message" yaml:"fallback_message" toml:"fallback_message" env:"LLM_FALLBACK_MESSAGE"`
+}
+
+/**
+ * @description Parses Options into the map passed to the provider factory.
+ */
+func (l LLMConfig) ProviderOptions() (map[string]string, error) {
+	options := make(map[string]string, len(l.Options))
+	for _, entry := range l.Options {
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid provider option %q: expected key=value", entry)
+		}
+		options[key] = strings.TrimSpace(value)
+	}
+	return options, nil
+}
+
+// validate reports unknown providers and malformed options
+func (l LLMConfig) validate(invalid func(field string, format string, args ...any)) {
+	if _, err := l.ProviderOptions(); err != nil {
+		invalid("llm.options", "%v", err)
+	}
+	if _, err := llm.ParsePricing(l.Pricing); err != nil {
+		invalid("llm.pricing", "%v", err)
+	}
+	if l.BreakerFailures < 0 {
+		invalid("llm.breaker_failures", "must not be negative")
+	}
+	if l.BreakerCooldown < 0 {
+		invalid("llm.breaker_cooldown", "must not be negative")
+	}
+	if l.BreakerFailures == 0 && (l.FallbackCache || l.FallbackModel != "" || l.FallbackMessage != "") {
+		invalid("llm.breaker_failures", "is required with fallbacks, which only answer while the breaker is open")
+	}
+	if l.Provider == "" {
+		if len(l.Options) > 0 {
+			invalid("llm.options", "requires llm.provider")
+		}
+		return
+	}
+	if providers := llm.Providers(); !slices.Contains(providers, l.Provider) {
+		invalid("llm.provider", "%q must be one of %s", l.Provider, strings.Join(providers, ", "))
+	}
+}
+
+// redacted masks the values of options named like secrets, e.g. api_key
+func (l LLMConfig) redacted() LLMConfig {
+	options := make([]string, len(l.Options))
+	for i, entry := range l.Options {
+		if key, _, ok := strings.Cut(entry, "="); ok && IsSensitive(key) {
+			entry = key + "=" + Redacted
+		}
+		options[i] = entry
+	}
+	l.Options = options
+	return l
+}
@@ -0,0 +1,81 @@
+/**
+ * @fileoverview Listener address settings.
+ * The server binds Port, or each of BindAddresses in turn, on BindHost when an address
+ * names no host, restricted to the address family chosen by IPFamily; the admin and gRPC
+ * listeners follow the same rules. IP literals that cannot be bound in that family are
+ * rejected up front rather than failing at bind time.
+ */
+
+package config
+
+import (
+	"net"
+	"strings"
+)
+
+// Address families accepted by ServerConfig.IPFamily
+const (
+	// IPFamilyDual binds IPv6 and IPv4 together where the host allows it
+	IPFamilyDual = "dual"
+	IPFamilyIPv4 = "ipv4"
+	IPFamilyIPv6 = "ipv6"
+)
+
+// validateNetwork reports invalid address family, bind host, and listener address settings
+func (c Config) validateNetwork(invalid func(field string, format string, args ...any)) {
+	s := c.Server
+	switch s.IPFamily {
+	case IPFamilyDual, IPFamilyIPv4, IPFamilyIPv6:
+	default:
+		invalid("server.ip_family", "%q must be dual, ipv4, or ipv6", s.IPFamily)
+		return
+	}
+
+	if host := s.BindHost; host != "" {
+		if _, _, err := net.SplitHostPort(host); err == nil || strings.ContainsAny(host, "[]") {
+			invalid("server.bind_host", "%q must be a host without a port or brackets", host)
+		} else if !inFamily(host, s.IPFamily) {
+			invalid("server.bind_host", "%q is not an %s address", host, s.IPFamily)
+		}
+	}
+	for _, address := range s.BindAddresses {
+		if !strings.Contains(address, ":") {
+			address = ":" + address
+		}
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			invalid("server.bind_addresses", "%q is not a valid address", address)
+		} else if !inFamily(host, s.IPFamily) {
+			invalid("server.bind_addresses", "%q is not an %s address", address, s.IPFamily)
+		}
+	}
+	listeners := []struct{ field, address string }{
+		{"server.http3_address", s.HTTP3Address},
+		{"admin.address", c.Admin.Address},
+		{"grpc.address", c.GRPC.Address},
+	}
+	for _, listener := range listeners {
+		address := listener.address
+		if address == "" {
+			continue
+		}
+		if !strings.Contains(address, ":") {
+			address = ":" + address
+		}
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			invalid(listener.field, "%q is not a valid address", listener.address)
+		} else if !inFamily(host, s.IPFamily) {
+			invalid(listener.field, "%q is not an %s address", listener.address, s.IPFamily)
+		}
+	}
+}
+
+// inFamily reports whether host can be bound in family; names are only resolved at bind time
+func inFamily(host, family string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil || family == IPFamilyDual {
+		return true
+	}
+	return (ip.To4() != nil) == (family == IPFamilyIPv4)
+}
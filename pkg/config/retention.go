@@ -0,0 +1,41 @@
+/**
+ * @fileoverview Data retention settings.
+ * Gives each data type a maximum age after which the retention manager purges its records,
+ * how often purges run, and a dry-run mode that only reports what would be deleted.
+ */
+
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/retention"
+)
+
+// RetentionConfig configures the retention manager
+type RetentionConfig struct {
+	// Policies holds "type=age" entries, e.g. "idempotency_keys=24h,sessions=30d"; empty disables purging
+	Policies []string `json:"policies" yaml:"policies" toml:"policies" env:"RETENTION_POLICIES"`
+	// Interval is how often purges run
+	Interval Duration `json:"interval" yaml:"interval" toml:"interval" env:"RETENTION_INTERVAL"`
+	// DryRun logs what each purge would delete without deleting it
+	DryRun bool `json:"dry_run" yaml:"dry_run" toml:"dry_run" env:"RETENTION_DRY_RUN"`
+}
+
+/**
+ * @description Parses Policies into each data type's maximum age.
+ */
+func (r RetentionConfig) ParsedPolicies() (map[string]time.Duration, error) {
+	return retention.ParsePolicies(strings.Join(r.Policies, ","))
+}
+
+// validate reports malformed policies
+func (r RetentionConfig) validate(invalid func(field string, format string, args ...any)) {
+	if _, err := r.ParsedPolicies(); err != nil {
+		invalid("retention.policies", "%v", err)
+	}
+	if r.Interval < 0 {
+		invalid("retention.interval", "must not be negative")
+	}
+}
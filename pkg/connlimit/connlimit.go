@@ -0,0 +1,173 @@
+/**
+ * @fileoverview Connection limiting and TCP tuning for server listeners.
+ * Caps the connections open at once, waiting to accept more until one closes so a
+ * flood queues in the kernel backlog instead of exhausting file descriptors, and caps
+ * the connections a single client host may hold, closing extra ones immediately.
+ * Accepted TCP connections get the configured NODELAY and keep-alive settings.
+ */
+
+package connlimit
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+var (
+	openConnections     = metrics.NewGauge("http_connections_open", "Connections currently open on limited listeners.")
+	rejectedConnections = metrics.NewCounter("http_connections_rejected_total", "Connections closed because their client host was at its connection limit.")
+)
+
+// Config configures a Listener; zero values leave the corresponding behavior unchanged
+type Config struct {
+	// MaxConnections caps the connections open at once
+	MaxConnections int
+	// MaxPerHost caps the connections open at once from one client IP
+	MaxPerHost int
+	// NoDelay sets TCP_NODELAY on accepted connections when not nil
+	NoDelay *bool
+	// KeepAlive is the TCP keep-alive probe period; negative disables keep-alive probes
+	KeepAlive time.Duration
+}
+
+// Listener limits and tunes the connections accepted from an inner listener
+type Listener struct {
+	net.Listener
+	config Config
+	// slots holds one token per open connection when MaxConnections is set
+	slots chan struct{}
+	// closed stops Accept from waiting for a slot once the listener is closed
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	mu     sync.Mutex
+	byHost map[string]int
+}
+
+// conn releases its listener slot and host count when closed
+type conn struct {
+	net.Conn
+	release sync.Once
+	onClose func()
+}
+
+/**
+ * @description Wraps inner so accepted connections are limited and tuned according to config.
+ */
+func NewListener(inner net.Listener, config Config) *Listener {
+	l := &Listener{Listener: inner, config: config, closed: make(chan struct{}), byHost: make(map[string]int)}
+	if config.MaxConnections > 0 {
+		l.slots = make(chan struct{}, config.MaxConnections)
+	}
+	return l
+}
+
+/**
+ * @description Waits for a free connection slot, then accepts the next connection whose
+ * client host is within its limit, closing those that are not.
+ */
+func (l *Listener) Accept() (net.Conn, error) {
+	for {
+		if l.slots != nil {
+			select {
+			case l.slots <- struct{}{}:
+			case <-l.closed:
+				return nil, net.ErrClosed
+			}
+		}
+		c, err := l.Listener.Accept()
+		if err != nil {
+			l.releaseSlot()
+			return nil, err
+		}
+
+		host := remoteHost(c)
+		if !l.admitHost(host) {
+			rejectedConnections.Add(1)
+			c.Close()
+			l.releaseSlot()
+			continue
+		}
+
+		l.tune(c)
+		openConnections.Add(1)
+		return &conn{Conn: c, onClose: func() {
+			openConnections.Add(-1)
+			l.releaseHost(host)
+			l.releaseSlot()
+		}}, nil
+	}
+}
+
+/**
+ * @description Closes the inner listener and wakes an Accept waiting for a free slot.
+ */
+func (l *Listener) Close() error {
+	l.closeOnce.Do(func() { close(l.closed) })
+	return l.Listener.Close()
+}
+
+func (c *conn) Close() error {
+	err := c.Conn.Close()
+	c.release.Do(c.onClose)
+	return err
+}
+
+// admitHost counts a connection from host unless it is at its limit
+func (l *Listener) admitHost(host string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.config.MaxPerHost > 0 && l.byHost[host] >= l.config.MaxPerHost {
+		return false
+	}
+	l.byHost[host]++
+	return true
+}
+
+// releaseHost forgets one connection from host
+func (l *Listener) releaseHost(host string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.byHost[host] <= 1 {
+		delete(l.byHost, host)
+	} else {
+		l.byHost[host]--
+	}
+}
+
+// releaseSlot frees a connection slot, if slots are limited
+func (l *Listener) releaseSlot() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// tune applies the TCP options to a TCP connection
+func (l *Listener) tune(c net.Conn) {
+	tcp, ok := c.(*net.TCPConn)
+	if !ok {
+		return
+	}
+	if l.config.NoDelay != nil {
+		tcp.SetNoDelay(*l.config.NoDelay)
+	}
+	switch {
+	case l.config.KeepAlive < 0:
+		tcp.SetKeepAlive(false)
+	case l.config.KeepAlive > 0:
+		tcp.SetKeepAlive(true)
+		tcp.SetKeepAlivePeriod(l.config.KeepAlive)
+	}
+}
+
+// remoteHost returns the IP of the connection's peer
+func remoteHost(c net.Conn) string {
+	host, _, err := net.SplitHostPort(c.RemoteAddr().String())
+	if err != nil {
+		return c.RemoteAddr().String()
+	}
+	return host
+}
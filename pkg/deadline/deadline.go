@@ -0,0 +1,183 @@
+/**
+ * @fileoverview Deadline budgets correlated across the call chain.
+ * Reads the caller's deadline from a request header or W3C trace baggage, reserves a margin
+ * for writing the response, and exposes the remaining budget on the request context so
+ * outbound LLM, database, and HTTP calls give up before the caller does. Requests that
+ * arrive with no budget left, or whose budget runs out, get 504 instead of racing the
+ * caller's own timeout.
+ */
+
+package deadline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/baggage"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+)
+
+const (
+	// TimeoutHeader carries the remaining budget as a Go duration ("1.5s") or integer milliseconds
+	TimeoutHeader = "X-Request-Timeout"
+	// BaggageKey is the W3C baggage member carrying an absolute deadline in Unix milliseconds
+	BaggageKey = "deadline"
+	// DefaultMargin is reserved from every budget for serializing and writing the response
+	DefaultMargin = 50 * time.Millisecond
+)
+
+// ErrBudgetExhausted is returned when too little budget remains to start an outbound call
+var ErrBudgetExhausted = errors.New("deadline budget exhausted")
+
+// Config configures the budget middleware
+type Config struct {
+	// Margin is reserved for the response; zero uses DefaultMargin
+	Margin time.Duration
+	// Default is the budget for requests that carry none; zero leaves them unbounded
+	Default time.Duration
+	// Max caps budgets requested by callers; zero accepts any budget
+	Max time.Duration
+	// Skip reports requests left unbounded, such as long-lived event streams and WebSockets
+	Skip func(*http.Request) bool
+}
+
+/**
+ * @description Returns the absolute deadline requested by r, from TimeoutHeader or the
+ * baggage deadline member, and whether one was present and valid.
+ */
+func FromRequest(r *http.Request, now time.Time) (time.Time, bool) {
+	if value := strings.TrimSpace(r.Header.Get(TimeoutHeader)); value != "" {
+		if budget, err := parseTimeout(value); err == nil {
+			return now.Add(budget), true
+		}
+	}
+	if member := baggageDeadline(r.Header.Get("baggage")); member != "" {
+		if millis, err := strconv.ParseInt(member, 10, 64); err == nil {
+			return time.UnixMilli(millis), true
+		}
+	}
+	return time.Time{}, false
+}
+
+/**
+ * @description Wraps next so its context carries the caller's deadline minus the margin.
+ * Responds 504 without calling next when no budget remains, and 504 when next returns
+ * after the budget expired without writing a response. Requests matched by config.Skip
+ * pass through without a deadline.
+ */
+func Middleware(config Config, next http.Handler) http.Handler {
+	if config.Margin <= 0 {
+		config.Margin = DefaultMargin
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if config.Skip != nil && config.Skip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		now := time.Now()
+		deadline, ok := FromRequest(r, now)
+		if !ok {
+			if config.Default <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			deadline = now.Add(config.Default)
+		}
+		if config.Max > 0 && deadline.Sub(now) > config.Max {
+			deadline = now.Add(config.Max)
+		}
+
+		deadline = deadline.Add(-config.Margin)
+		if !deadline.After(now) {
+			apierror.Write(w, r, http.StatusGatewayTimeout, "request deadline budget exhausted")
+			return
+		}
+
+		ctx, cancel := context.WithDeadline(r.Context(), deadline)
+		defer cancel()
+
+		rw := httputil.NewResponseWriter(w)
+		next.ServeHTTP(rw, r.WithContext(ctx))
+		if !rw.WroteHeader() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			apierror.Write(rw, r, http.StatusGatewayTimeout, "request deadline budget exhausted")
+		}
+	})
+}
+
+/**
+ * @description Returns the budget left on ctx and whether ctx has a deadline.
+ */
+func Remaining(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+/**
+ * @description Derives a context for one outbound call: bounded by limit when positive and by
+ * the remaining budget. Fails with ErrBudgetExhausted when less than minimum remains, so
+ * callers skip calls that cannot finish in time.
+ */
+func ForCall(ctx context.Context, limit, minimum time.Duration) (context.Context, context.CancelFunc, error) {
+	if remaining, ok := Remaining(ctx); ok && remaining < max(minimum, 1) {
+		return nil, nil, fmt.Errorf("%w: %v left, need %v", ErrBudgetExhausted, remaining.Round(time.Millisecond), minimum)
+	}
+	if limit > 0 {
+		ctx, cancel := context.WithTimeout(ctx, limit)
+		return ctx, cancel, nil
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, cancel, nil
+}
+
+/**
+ * @description Sets TimeoutHeader on an outbound request from its context's remaining budget,
+ * replacing any value copied from the inbound request.
+ */
+func Inject(req *http.Request) {
+	if remaining, ok := Remaining(req.Context()); ok {
+		req.Header.Set(TimeoutHeader, strconv.FormatInt(max(remaining.Milliseconds(), 0), 10))
+	}
+}
+
+/**
+ * @description Wraps a proxying handler so the forwarded request carries the remaining budget.
+ */
+func Propagate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); ok {
+			r = r.Clone(r.Context())
+			Inject(r)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// parseTimeout accepts a Go duration or integer milliseconds
+func parseTimeout(value string) (time.Duration, error) {
+	if millis, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Duration(millis) * time.Millisecond, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// baggageDeadline returns the deadline member of a W3C baggage header, if any
+func baggageDeadline(header string) string {
+	if header == "" {
+		return ""
+	}
+	bag, err := baggage.Parse(header)
+	if err != nil {
+		return ""
+	}
+	return bag.Member(BaggageKey).Value()
+}
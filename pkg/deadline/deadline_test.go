@@ -0,0 +1,227 @@
+package deadline
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFromRequest(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	absolute := now.Add(3 * time.Second)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   time.Time
+		wantOK bool
+	}{
+		{name: "none"},
+		{name: "duration", header: map[string]string{TimeoutHeader: "1.5s"}, want: now.Add(1500 * time.Millisecond), wantOK: true},
+		{name: "milliseconds", header: map[string]string{TimeoutHeader: " 250 "}, want: now.Add(250 * time.Millisecond), wantOK: true},
+		{name: "invalid timeout", header: map[string]string{TimeoutHeader: "soon"}},
+		{
+			name:   "baggage",
+			header: map[string]string{"baggage": "tenant=acme," + BaggageKey + "=" + strconv.FormatInt(absolute.UnixMilli(), 10)},
+			want:   absolute,
+			wantOK: true,
+		},
+		{name: "invalid baggage member", header: map[string]string{"baggage": BaggageKey + "=tomorrow"}},
+		{name: "malformed baggage", header: map[string]string{"baggage": ";;"}},
+		{
+			name: "header preferred over baggage",
+			header: map[string]string{
+				TimeoutHeader: "1s",
+				"baggage":     BaggageKey + "=" + strconv.FormatInt(absolute.UnixMilli(), 10),
+			},
+			want:   now.Add(time.Second),
+			wantOK: true,
+		},
+		{
+			name: "baggage used when header is invalid",
+			header: map[string]string{
+				TimeoutHeader: "soon",
+				"baggage":     BaggageKey + "=" + strconv.FormatInt(absolute.UnixMilli(), 10),
+			},
+			want:   absolute,
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			got, ok := FromRequest(req, now)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("FromRequest() = %v, %v; want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		timeout string
+		path    string
+		// hang waits for the context instead of responding
+		hang         bool
+		wantStatus   int
+		wantDeadline bool
+		// wantMax bounds the budget seen by the handler when it has a deadline
+		wantMax time.Duration
+	}{
+		{name: "no budget passes through", wantStatus: http.StatusOK},
+		{name: "caller budget minus margin", timeout: "1s", wantStatus: http.StatusOK, wantDeadline: true, wantMax: time.Second - DefaultMargin},
+		{name: "default budget", config: Config{Default: 2 * time.Second}, wantStatus: http.StatusOK, wantDeadline: true, wantMax: 2*time.Second - DefaultMargin},
+		{name: "capped by max", config: Config{Max: time.Second}, timeout: "1m", wantStatus: http.StatusOK, wantDeadline: true, wantMax: time.Second - DefaultMargin},
+		{name: "budget within margin", timeout: "40ms", wantStatus: http.StatusGatewayTimeout},
+		{name: "custom margin", config: Config{Margin: 10 * time.Millisecond}, timeout: "40ms", wantStatus: http.StatusOK, wantDeadline: true, wantMax: 30 * time.Millisecond},
+		{
+			name:       "skipped route",
+			config:     Config{Default: time.Second, Skip: func(r *http.Request) bool { return r.URL.Path == "/events" }},
+			path:       "/events",
+			wantStatus: http.StatusOK,
+		},
+		{name: "budget runs out", timeout: "60ms", hang: true, wantStatus: http.StatusGatewayTimeout, wantDeadline: true, wantMax: 10 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var hasDeadline bool
+			var budget time.Duration
+			handler := Middleware(tt.config, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				budget, hasDeadline = Remaining(r.Context())
+				if tt.hang {
+					<-r.Context().Done()
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			path := tt.path
+			if path == "" {
+				path = "/"
+			}
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			if tt.timeout != "" {
+				req.Header.Set(TimeoutHeader, tt.timeout)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if hasDeadline != tt.wantDeadline {
+				t.Errorf("handler saw deadline = %v, want %v", hasDeadline, tt.wantDeadline)
+			}
+			if tt.wantDeadline && (budget <= 0 || budget > tt.wantMax) {
+				t.Errorf("handler budget = %v, want within (0, %v]", budget, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestForCall(t *testing.T) {
+	tests := []struct {
+		name    string
+		budget  time.Duration
+		limit   time.Duration
+		minimum time.Duration
+		wantErr error
+		// wantMax bounds the call's deadline; zero means it has none
+		wantMax time.Duration
+	}{
+		{name: "unbounded"},
+		{name: "limit only", limit: time.Second, wantMax: time.Second},
+		{name: "budget below limit", budget: 500 * time.Millisecond, limit: time.Second, wantMax: 500 * time.Millisecond},
+		{name: "limit below budget", budget: time.Minute, limit: time.Second, wantMax: time.Second},
+		{name: "budget below minimum", budget: 100 * time.Millisecond, minimum: time.Second, wantErr: ErrBudgetExhausted},
+		{name: "budget spent", budget: -time.Second, wantErr: ErrBudgetExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent := context.Background()
+			if tt.budget != 0 {
+				var cancel context.CancelFunc
+				parent, cancel = context.WithTimeout(parent, tt.budget)
+				defer cancel()
+			}
+
+			ctx, cancel, err := ForCall(parent, tt.limit, tt.minimum)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ForCall() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			defer cancel()
+
+			remaining, ok := Remaining(ctx)
+			if ok != (tt.wantMax > 0) {
+				t.Fatalf("call has deadline = %v, want %v", ok, tt.wantMax > 0)
+			}
+			if ok && (remaining <= 0 || remaining > tt.wantMax) {
+				t.Errorf("call budget = %v, want within (0, %v]", remaining, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestPropagate(t *testing.T) {
+	tests := []struct {
+		name   string
+		budget time.Duration
+		// inbound is a TimeoutHeader value copied from the inbound request
+		inbound string
+		want    func(value string) bool
+	}{
+		{name: "no deadline keeps the inbound header", inbound: "9000", want: func(value string) bool { return value == "9000" }},
+		{name: "no deadline and no header", want: func(value string) bool { return value == "" }},
+		{
+			name:    "remaining budget replaces the inbound header",
+			budget:  time.Second,
+			inbound: "9000",
+			want: func(value string) bool {
+				millis, err := strconv.Atoi(value)
+				return err == nil && millis > 0 && millis <= 1000
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.inbound != "" {
+				req.Header.Set(TimeoutHeader, tt.inbound)
+			}
+			if tt.budget > 0 {
+				ctx, cancel := context.WithTimeout(req.Context(), tt.budget)
+				defer cancel()
+				req = req.WithContext(ctx)
+			}
+
+			var forwarded string
+			Propagate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				forwarded = r.Header.Get(TimeoutHeader)
+			})).ServeHTTP(httptest.NewRecorder(), req)
+
+			if !tt.want(forwarded) {
+				t.Errorf("forwarded %s = %q", TimeoutHeader, forwarded)
+			}
+			if got := req.Header.Get(TimeoutHeader); got != tt.inbound {
+				t.Errorf("inbound %s changed to %q, want %q", TimeoutHeader, got, tt.inbound)
+			}
+		})
+	}
+}
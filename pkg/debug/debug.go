@@ -0,0 +1,153 @@
+/**
+ * @fileoverview Debug endpoints for exercising the middleware stack and client behavior.
+ * Provides echo, header inspection, artificial delay, and arbitrary status responses,
+ * plus a WebSocket echo handler.
+ * Intended for development and tutorials only; never mount these in production.
+ */
+
+package debug
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/ws"
+)
+
+const (
+	// MaxEchoBodyBytes limits how much of the request body /debug/echo reflects
+	MaxEchoBodyBytes = 1 << 20
+	// MaxDelay caps the delay accepted by /debug/delay
+	MaxDelay = 60 * time.Second
+)
+
+// EchoResponse describes the request as received by the server
+type EchoResponse struct {
+	Method     string              `json:"method"`
+	Path       string              `json:"path"`
+	Query      map[string][]string `json:"query,omitempty"`
+	Headers    map[string][]string `json:"headers"`
+	Body       string              `json:"body,omitempty"`
+	RemoteAddr string              `json:"remote_addr"`
+	// ClientIP is the client address after trusted proxy headers are applied
+	ClientIP string `json:"client_ip"`
+	Host     string `json:"host"`
+}
+
+// Registrar accepts handler registrations; *http.ServeMux satisfies it
+type Registrar interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+/**
+ * @description Registers all debug endpoints under /debug/ on the mux.
+ * Each handler is passed through wrap so it runs behind the same middleware as other routes.
+ */
+func Register(mux Registrar, wrap func(func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request)) {
+	if wrap == nil {
+		wrap = func(handler func(http.ResponseWriter, *http.Request)) func(http.ResponseWriter, *http.Request) {
+			return handler
+		}
+	}
+
+	mux.HandleFunc("/debug/echo", wrap(EchoHandler))
+	mux.HandleFunc("/debug/headers", wrap(HeadersHandler))
+	mux.HandleFunc("/debug/delay", wrap(DelayHandler))
+	mux.HandleFunc("/debug/status", wrap(StatusHandler))
+}
+
+/**
+ * @description Reflects the request method, path, query, headers, and body back as JSON.
+ */
+func EchoHandler(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxEchoBodyBytes))
+	if err != nil {
+		apierror.Write(w, r, http.StatusBadRequest, fmt.Sprintf("failed to read body: %v", err))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, EchoResponse{
+		Method:     r.Method,
+		Path:       r.URL.Path,
+		Query:      r.URL.Query(),
+		Headers:    r.Header,
+		Body:       string(body),
+		RemoteAddr: r.RemoteAddr,
+		ClientIP:   httputil.ClientIP(r),
+		Host:       r.Host,
+	})
+}
+
+/**
+ * @description Returns the request headers as JSON.
+ */
+func HeadersHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, r.Header)
+}
+
+/**
+ * @description Waits for the duration given by the ms query parameter before responding.
+ * Returns early without a body if the client disconnects or the request context is cancelled.
+ */
+func DelayHandler(w http.ResponseWriter, r *http.Request) {
+	ms, err := strconv.Atoi(r.URL.Query().Get("ms"))
+	if err != nil || ms < 0 {
+		apierror.Write(w, r, http.StatusBadRequest, "ms must be a non-negative integer")
+		return
+	}
+
+	delay := time.Duration(ms) * time.Millisecond
+	if delay > MaxDelay {
+		apierror.Write(w, r, http.StatusBadRequest, fmt.Sprintf("ms must not exceed %d", MaxDelay.Milliseconds()))
+		return
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		writeJSON(w, http.StatusOK, map[string]any{"delayed_ms": ms})
+	case <-r.Context().Done():
+	}
+}
+
+/**
+ * @description Responds with the status code given by the code query parameter.
+ */
+func StatusHandler(w http.ResponseWriter, r *http.Request) {
+	code, err := strconv.Atoi(r.URL.Query().Get("code"))
+	if err != nil || code < 200 || code > 599 {
+		apierror.Write(w, r, http.StatusBadRequest, "code must be an integer between 200 and 599")
+		return
+	}
+
+	writeJSON(w, code, map[string]any{"status": code, "text": http.StatusText(code)})
+}
+
+// writeJSON encodes value as the JSON response body with the given status
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	jsoncase.Write(w, status, value)
+}
+
+/**
+ * @description Echoes every WebSocket message back to the client until it disconnects.
+ */
+func EchoSocket(ctx context.Context, conn *ws.Conn) error {
+	for {
+		message, err := conn.Read(ctx)
+		if err != nil {
+			return err
+		}
+		if err := conn.Write(ctx, message); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,147 @@
+/**
+ * @fileoverview Request deduplication for webhook deliveries and job submissions.
+ * Identifies repeated work by a caller-provided delivery ID or a hash of the request
+ * content, processes the first occurrence, and answers duplicates with the original
+ * result for a TTL so retry storms cannot trigger double-processing.
+ */
+
+package dedupe
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/store"
+)
+
+const (
+	// DefaultTTL is how long a completed result is replayed to duplicates
+	DefaultTTL = 24 * time.Hour
+	// DefaultPendingTTL bounds how long an in-flight claim blocks duplicates if the processor dies
+	DefaultPendingTTL = 5 * time.Minute
+	// keyPrefix namespaces dedupe entries in a shared idempotency store
+	keyPrefix = "dedupe:"
+)
+
+// ErrInProgress is returned when a duplicate arrives while the original is still being processed
+var ErrInProgress = errors.New("dedupe: original request is still in progress")
+
+// Deduper runs work at most once per key within the TTL
+type Deduper struct {
+	store      store.IdempotencyStore
+	namespace  string
+	ttl        time.Duration
+	pendingTTL time.Duration
+}
+
+// Config configures a Deduper
+type Config struct {
+	// Store persists claims and results; use store.New to share the configured backend
+	Store store.IdempotencyStore
+	// Namespace separates keys for different endpoints or job types
+	Namespace  string
+	TTL        time.Duration
+	PendingTTL time.Duration
+}
+
+/**
+ * @description Creates a Deduper, applying default TTLs.
+ */
+func New(config Config) *Deduper {
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+	if config.PendingTTL <= 0 {
+		config.PendingTTL = DefaultPendingTTL
+	}
+	return &Deduper{store: config.Store, namespace: config.Namespace, ttl: config.TTL, pendingTTL: config.PendingTTL}
+}
+
+/**
+ * @description Returns a stable key for content without a caller-provided ID.
+ */
+func ContentKey(parts ...[]byte) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		// Length-prefix each part so boundaries cannot be shifted between parts
+		fmt.Fprintf(hash, "%d:", len(part))
+		hash.Write(part)
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+/**
+ * @description Runs fn for the first occurrence of key and stores its result.
+ * Duplicates receive the stored result with duplicate=true, or ErrInProgress while the
+ * original is running. A failed fn releases the claim so a retry can process the work.
+ */
+func (d *Deduper) Do(ctx context.Context, key string, fn func() ([]byte, error)) (result []byte, duplicate bool, err error) {
+	storeKey := d.storeKey(key)
+
+	if stored, err := d.store.Get(ctx, storeKey); err == nil {
+		return stored, true, nil
+	} else if !errors.Is(err, store.ErrNotFound) {
+		return nil, false, fmt.Errorf("dedupe: failed to look up %s: %w", key, err)
+	}
+
+	claimed, err := d.store.Reserve(ctx, storeKey, d.pendingTTL)
+	if err != nil {
+		return nil, false, fmt.Errorf("dedupe: failed to claim %s: %w", key, err)
+	}
+	if !claimed {
+		// The original may have completed between Get and Reserve
+		if stored, err := d.store.Get(ctx, storeKey); err == nil {
+			return stored, true, nil
+		}
+		return nil, true, ErrInProgress
+	}
+
+	result, err = fn()
+	if err != nil {
+		d.release(storeKey)
+		return nil, false, err
+	}
+	if err := d.store.Put(ctx, storeKey, result, d.ttl); err != nil {
+		return result, false, fmt.Errorf("dedupe: failed to store result for %s: %w", key, err)
+	}
+	return result, false, nil
+}
+
+/**
+ * @description Runs fn once per key like Do, encoding the result as JSON so typed
+ * job results can be replayed to duplicate submissions.
+ */
+func DoJSON[T any](ctx context.Context, d *Deduper, key string, fn func() (T, error)) (T, bool, error) {
+	var value T
+	data, duplicate, err := d.Do(ctx, key, func() ([]byte, error) {
+		result, err := fn()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(result)
+	})
+	if err != nil {
+		return value, duplicate, err
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, duplicate, fmt.Errorf("dedupe: failed to decode stored result for %s: %w", key, err)
+	}
+	return value, duplicate, nil
+}
+
+// release drops a claim without a result; it uses a fresh context so cancellation cannot strand the claim
+func (d *Deduper) release(storeKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	d.store.Delete(ctx, storeKey)
+}
+
+// storeKey namespaces a dedupe key within the shared store
+func (d *Deduper) storeKey(key string) string {
+	return keyPrefix + d.namespace + ":" + key
+}
@@ -0,0 +1,310 @@
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/store"
+)
+
+func TestContentKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      [][]byte
+		wantEqual bool
+	}{
+		{name: "same parts", a: [][]byte{[]byte("POST"), []byte("/hooks")}, b: [][]byte{[]byte("POST"), []byte("/hooks")}, wantEqual: true},
+		{name: "different content", a: [][]byte{[]byte("POST"), []byte("/hooks")}, b: [][]byte{[]byte("POST"), []byte("/jobs")}},
+		{name: "shifted boundary", a: [][]byte{[]byte("ab"), []byte("c")}, b: [][]byte{[]byte("a"), []byte("bc")}},
+		{name: "empty part", a: [][]byte{[]byte("a"), nil}, b: [][]byte{[]byte("a")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := ContentKey(tt.a...), ContentKey(tt.b...)
+			if !strings.HasPrefix(a, "sha256:") {
+				t.Errorf("ContentKey() = %q, want a sha256: prefix", a)
+			}
+			if (a == b) != tt.wantEqual {
+				t.Errorf("keys equal = %v, want %v (%s, %s)", a == b, tt.wantEqual, a, b)
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	errFailed := errors.New("failed")
+
+	tests := []struct {
+		name string
+		// prepare runs against the store before the call under test
+		prepare       func(d *Deduper, s store.IdempotencyStore)
+		fn            func() ([]byte, error)
+		wantResult    string
+		wantDuplicate bool
+		wantErr       error
+		wantCalled    bool
+	}{
+		{
+			name:       "first occurrence runs",
+			fn:         func() ([]byte, error) { return []byte("new"), nil },
+			wantResult: "new",
+			wantCalled: true,
+		},
+		{
+			name: "duplicate replays the stored result",
+			prepare: func(d *Deduper, s store.IdempotencyStore) {
+				d.Do(context.Background(), "key", func() ([]byte, error) { return []byte("first"), nil })
+			},
+			fn:            func() ([]byte, error) { return []byte("second"), nil },
+			wantResult:    "first",
+			wantDuplicate: true,
+		},
+		{
+			name: "failure releases the claim",
+			prepare: func(d *Deduper, s store.IdempotencyStore) {
+				d.Do(context.Background(), "key", func() ([]byte, error) { return nil, errFailed })
+			},
+			fn:         func() ([]byte, error) { return []byte("retried"), nil },
+			wantResult: "retried",
+			wantCalled: true,
+		},
+		{
+			name: "claim in progress",
+			prepare: func(d *Deduper, s store.IdempotencyStore) {
+				s.Reserve(context.Background(), d.storeKey("key"), DefaultPendingTTL)
+			},
+			fn:            func() ([]byte, error) { return []byte("concurrent"), nil },
+			wantDuplicate: true,
+			wantErr:       ErrInProgress,
+		},
+		{
+			name:       "failure is returned",
+			fn:         func() ([]byte, error) { return nil, errFailed },
+			wantErr:    errFailed,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := store.NewMemoryIdempotencyStore()
+			d := New(Config{Store: s, Namespace: "test"})
+			if tt.prepare != nil {
+				tt.prepare(d, s)
+			}
+
+			called := false
+			result, duplicate, err := d.Do(context.Background(), "key", func() ([]byte, error) {
+				called = true
+				return tt.fn()
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+			if string(result) != tt.wantResult {
+				t.Errorf("Do() result = %q, want %q", result, tt.wantResult)
+			}
+			if duplicate != tt.wantDuplicate {
+				t.Errorf("Do() duplicate = %v, want %v", duplicate, tt.wantDuplicate)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("fn called = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
+
+func TestDoSeparatesNamespaces(t *testing.T) {
+	s := store.NewMemoryIdempotencyStore()
+	webhooks := New(Config{Store: s, Namespace: "webhooks"})
+	jobs := New(Config{Store: s, Namespace: "jobs"})
+
+	webhooks.Do(context.Background(), "key", func() ([]byte, error) { return []byte("webhook"), nil })
+	result, duplicate, err := jobs.Do(context.Background(), "key", func() ([]byte, error) { return []byte("job"), nil })
+	if err != nil || duplicate || string(result) != "job" {
+		t.Errorf("Do() in another namespace = %q, %v, %v; want \"job\", false, nil", result, duplicate, err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		config MiddlewareConfig
+		// status is what the handler answers on every call
+		status       int
+		first        func() *http.Request
+		second       func() *http.Request
+		wantCalls    int
+		wantReplayed bool
+		wantStatus   int
+	}{
+		{
+			name:   "delivery ID replays",
+			status: http.StatusAccepted,
+			first: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/hooks", strings.NewReader("a"))
+				req.Header.Set("X-GitHub-Delivery", "d1")
+				return req
+			},
+			second: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/hooks", strings.NewReader("b"))
+				req.Header.Set("X-GitHub-Delivery", "d1")
+				return req
+			},
+			wantCalls:    1,
+			wantReplayed: true,
+			wantStatus:   http.StatusAccepted,
+		},
+		{
+			name:   "different delivery IDs",
+			status: http.StatusAccepted,
+			first: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/hooks", nil)
+				req.Header.Set("Webhook-Id", "d1")
+				return req
+			},
+			second: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/hooks", nil)
+				req.Header.Set("Webhook-Id", "d2")
+				return req
+			},
+			wantCalls:  2,
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:   "same ID on another path",
+			status: http.StatusAccepted,
+			first: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/hooks", nil)
+				req.Header.Set("X-Dedupe-Key", "d1")
+				return req
+			},
+			second: func() *http.Request {
+				req := httptest.NewRequest(http.MethodPost, "/jobs", nil)
+				req.Header.Set("X-Dedupe-Key", "d1")
+				return req
+			},
+			wantCalls:  2,
+			wantStatus: http.StatusAccepted,
+		},
+		{
+			name:   "identical body hashed",
+			config: MiddlewareConfig{HashBody: true},
+			status: http.StatusCreated,
+			first: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"n":1}`))
+			},
+			second: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"n":1}`))
+			},
+			wantCalls:    1,
+			wantReplayed: true,
+			wantStatus:   http.StatusCreated,
+		},
+		{
+			name:   "different body hashed",
+			config: MiddlewareConfig{HashBody: true},
+			status: http.StatusCreated,
+			first: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"n":1}`))
+			},
+			second: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"n":2}`))
+			},
+			wantCalls:  2,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:   "no key without hashing",
+			status: http.StatusCreated,
+			first: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"n":1}`))
+			},
+			second: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(`{"n":1}`))
+			},
+			wantCalls:  2,
+			wantStatus: http.StatusCreated,
+		},
+		{
+			name:         "client error replays",
+			config:       MiddlewareConfig{HashBody: true},
+			status:       http.StatusBadRequest,
+			first:        func() *http.Request { return httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader("x")) },
+			second:       func() *http.Request { return httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader("x")) },
+			wantCalls:    1,
+			wantReplayed: true,
+			wantStatus:   http.StatusBadRequest,
+		},
+		{
+			name:       "server error is not stored",
+			config:     MiddlewareConfig{HashBody: true},
+			status:     http.StatusBadGateway,
+			first:      func() *http.Request { return httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader("x")) },
+			second:     func() *http.Request { return httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader("x")) },
+			wantCalls:  2,
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			d := New(Config{Store: store.NewMemoryIdempotencyStore(), Namespace: "test"})
+			handler := d.Middleware(tt.config, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("X-Request-Id", "original")
+				w.Header().Set("X-Result", "stored")
+				w.WriteHeader(tt.status)
+				w.Write([]byte("response"))
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), tt.first())
+			second := tt.second()
+			second.Header.Set("X-Request-Id", "retry")
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, second)
+
+			if calls != tt.wantCalls {
+				t.Errorf("handler called %d times, want %d", calls, tt.wantCalls)
+			}
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("second status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if replayed := recorder.Header().Get(ReplayedHeader) == "true"; replayed != tt.wantReplayed {
+				t.Errorf("replayed = %v, want %v", replayed, tt.wantReplayed)
+			}
+			if !tt.wantReplayed {
+				return
+			}
+			if got := recorder.Body.String(); got != "response" {
+				t.Errorf("replayed body = %q, want %q", got, "response")
+			}
+			if got := recorder.Header().Get("X-Result"); got != "stored" {
+				t.Errorf("replayed X-Result = %q, want %q", got, "stored")
+			}
+			if got := recorder.Header().Get("X-Request-Id"); got != "retry" {
+				t.Errorf("replayed X-Request-Id = %q, want the retry's own ID", got)
+			}
+		})
+	}
+}
+
+func TestMiddlewareRejectsLargeBodies(t *testing.T) {
+	d := New(Config{Store: store.NewMemoryIdempotencyStore()})
+	handler := d.Middleware(MiddlewareConfig{HashBody: true, MaxBodyBytes: 4}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler called for an oversized body")
+	}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader("too large")))
+	if recorder.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusRequestEntityTooLarge)
+	}
+}
@@ -0,0 +1,104 @@
+/**
+ * @fileoverview Idempotency-Key middleware for retried non-idempotent requests.
+ * Clients send a unique Idempotency-Key with a POST or PATCH; the first request is
+ * processed and its response stored for the Deduper's TTL, and retries with the same
+ * key get that response back instead of repeating the side effect. Reusing a key with
+ * a different body is rejected, and a retry arriving while the original is still
+ * running gets 409 so payments and job submissions never run twice. Keys are scoped to
+ * the caller, so two clients choosing the same key never see each other's responses.
+ */
+
+package dedupe
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client's key for a request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks a response replayed for a retried key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// MaxIdempotencyKeyLength bounds keys; UUIDs and similar random tokens fit easily
+	MaxIdempotencyKeyLength = 255
+)
+
+// DefaultIdempotentMethods are the methods handled when IdempotencyConfig leaves Methods empty
+var DefaultIdempotentMethods = []string{http.MethodPost, http.MethodPatch}
+
+// IdempotencyConfig configures IdempotencyMiddleware
+type IdempotencyConfig struct {
+	// Methods are the request methods keys apply to; defaults to DefaultIdempotentMethods
+	Methods []string
+	// Required rejects requests using those methods without a key
+	Required bool
+	// MaxBodyBytes limits request bodies fingerprinted and responses stored
+	MaxBodyBytes int64
+	// Scope identifies the caller a key belongs to; defaults to ScopeByCredentials
+	Scope func(*http.Request) string
+}
+
+/**
+ * @description Scopes keys to the caller's credentials: a hash of the Authorization header
+ * when present, otherwise the client IP.
+ */
+func ScopeByCredentials(r *http.Request) string {
+	if credentials := r.Header.Get("Authorization"); credentials != "" {
+		return "auth:" + ContentKey([]byte(credentials))
+	}
+	return "ip:" + httputil.ClientIP(r)
+}
+
+/**
+ * @description Wraps next so requests carrying an Idempotency-Key are processed once per caller,
+ * key, method, and path, with retries receiving the stored response and an Idempotent-Replayed
+ * header. Server errors are not stored, so a retry after a 5xx is processed again.
+ */
+func (d *Deduper) IdempotencyMiddleware(config IdempotencyConfig, next http.Handler) http.Handler {
+	if len(config.Methods) == 0 {
+		config.Methods = DefaultIdempotentMethods
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if config.Scope == nil {
+		config.Scope = ScopeByCredentials
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !slices.Contains(config.Methods, r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := r.Header.Get(IdempotencyKeyHeader)
+		switch {
+		case key == "" && config.Required:
+			apierror.Write(w, r, http.StatusBadRequest, "an "+IdempotencyKeyHeader+" header is required")
+			return
+		case key == "":
+			next.ServeHTTP(w, r)
+			return
+		case len(key) > MaxIdempotencyKeyLength:
+			apierror.Newf(http.StatusBadRequest, "%s must not exceed %d characters", IdempotencyKeyHeader, MaxIdempotencyKeyLength).Write(w, r)
+			return
+		}
+
+		body, err := bufferBody(r, config.MaxBodyBytes)
+		if err != nil {
+			apierror.Write(w, r, http.StatusRequestEntityTooLarge, "request body exceeds the idempotency limit")
+			return
+		}
+
+		scoped := ContentKey([]byte(config.Scope(r)), []byte(r.Method+" "+r.URL.Path), []byte(key))
+		d.serve(w, r, "idempotency:"+scoped, replayOptions{
+			header:      IdempotentReplayedHeader,
+			fingerprint: ContentKey([]byte(r.URL.RawQuery), body),
+			limit:       config.MaxBodyBytes,
+		}, next)
+	})
+}
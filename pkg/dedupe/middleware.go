@@ -0,0 +1,252 @@
+/**
+ * @fileoverview HTTP middleware applying deduplication to inbound webhooks and job submissions.
+ * Keys come from a delivery ID header when present, otherwise from a hash of the method,
+ * path, and body. Successful and client-error responses are replayed to duplicates, without
+ * the per-request headers of the original response, and each replay is logged and counted
+ * since replays never reach the access log inside the router.
+ */
+
+package dedupe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+const (
+	// ReplayedHeader marks a response replayed from the dedupe store
+	ReplayedHeader = "X-Dedupe-Replayed"
+	// DefaultMaxBodyBytes limits request bodies hashed and responses stored
+	DefaultMaxBodyBytes = 1 << 20
+)
+
+// replayedResponses counts responses replayed to duplicate or retried requests
+var replayedResponses = metrics.NewCounter("dedupe_replayed_responses_total", "Stored responses replayed to duplicate or retried requests.")
+
+// requestIDHeader identifies each request; a replay carries the retry's own ID, when it sent one
+const requestIDHeader = "X-Request-Id"
+
+// perRequestHeaders describe one response rather than its content, so they are never stored
+var perRequestHeaders = []string{
+	requestIDHeader, "Date",
+	"Connection", "Keep-Alive", "Proxy-Connection", "Te", "Trailer", "Transfer-Encoding", "Upgrade",
+}
+
+// DefaultKeyHeaders are common delivery ID headers sent by webhook providers
+var DefaultKeyHeaders = []string{"X-Dedupe-Key", "Webhook-Id", "X-GitHub-Delivery", "X-Delivery-ID"}
+
+// MiddlewareConfig configures Middleware
+type MiddlewareConfig struct {
+	// KeyHeaders are checked in order for a caller-provided ID; defaults to DefaultKeyHeaders
+	KeyHeaders []string
+	// HashBody derives a key from the request content when no key header is present
+	HashBody     bool
+	MaxBodyBytes int64
+}
+
+// storedResponse is the persisted form of a response
+type storedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+	// Fingerprint identifies the request content for keys that must not be reused with other content
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// replayOptions describes how a middleware replays stored responses
+type replayOptions struct {
+	// header is set to "true" on replayed responses
+	header string
+	// fingerprint must match the stored one when set
+	fingerprint string
+	limit       int64
+}
+
+// errNotStored aborts Do without caching when the response should not be replayed
+var errNotStored = errors.New("response not stored")
+
+/**
+ * @description Wraps next so duplicate requests receive the first response instead of being reprocessed.
+ * Responses with 5xx status or bodies larger than MaxBodyBytes are not stored, so retries reach next.
+ */
+func (d *Deduper) Middleware(config MiddlewareConfig, next http.Handler) http.Handler {
+	if len(config.KeyHeaders) == 0 {
+		config.KeyHeaders = DefaultKeyHeaders
+	}
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key, err := requestKey(r, config)
+		if err != nil {
+			apierror.Write(w, r, http.StatusRequestEntityTooLarge, "request body exceeds the dedupe limit")
+			return
+		}
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		d.serve(w, r, key, replayOptions{header: ReplayedHeader, limit: config.MaxBodyBytes}, next)
+	})
+}
+
+// serve runs next for the first request with key, storing its response unless it is a
+// server error or too large, and replays the stored response to duplicates
+func (d *Deduper) serve(w http.ResponseWriter, r *http.Request, key string, options replayOptions, next http.Handler) {
+	recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK, limit: options.limit}
+	data, duplicate, err := d.Do(r.Context(), key, func() ([]byte, error) {
+		// Headers set by outer middleware before next runs are set again for every retry
+		outer := make(map[string]bool, len(w.Header()))
+		for name := range w.Header() {
+			outer[name] = true
+		}
+		next.ServeHTTP(recorder, r)
+		if recorder.status >= http.StatusInternalServerError || recorder.overflow {
+			return nil, errNotStored
+		}
+		return json.Marshal(storedResponse{
+			Status:      recorder.status,
+			Header:      storedHeader(recorder.Header(), outer),
+			Body:        recorder.body.Bytes(),
+			Fingerprint: options.fingerprint,
+		})
+	})
+
+	switch {
+	case errors.Is(err, ErrInProgress):
+		w.Header().Set("Retry-After", "1")
+		apierror.Write(w, r, http.StatusConflict, "duplicate request is still being processed")
+	case duplicate && err == nil:
+		if status, ok := replay(w, r, data, options); ok {
+			replayedResponses.Add(1)
+			log.Printf("Replayed stored %d response to %s %s from %s, request ID %q",
+				status, r.Method, r.URL.Path, httputil.ClientIP(r), r.Header.Get(requestIDHeader))
+		}
+	case errors.Is(err, errNotStored):
+		// next already wrote the response
+	case err != nil && recorder.wroteHeader:
+		log.Printf("Dedupe store error for %s %s: %v", r.Method, r.URL.Path, err)
+	case err != nil:
+		log.Printf("Dedupe store unavailable for %s %s, processing without dedupe: %v", r.Method, r.URL.Path, err)
+		next.ServeHTTP(w, r)
+	}
+}
+
+// requestKey returns the caller-provided ID or, when enabled, a content hash of the request
+func requestKey(r *http.Request, config MiddlewareConfig) (string, error) {
+	for _, header := range config.KeyHeaders {
+		if value := r.Header.Get(header); value != "" {
+			return "id:" + r.Method + " " + r.URL.Path + ":" + value, nil
+		}
+	}
+	if !config.HashBody || r.Body == nil {
+		return "", nil
+	}
+
+	body, err := bufferBody(r, config.MaxBodyBytes)
+	if err != nil {
+		return "", err
+	}
+	return ContentKey([]byte(r.Method), []byte(r.URL.RequestURI()), body), nil
+}
+
+// bufferBody reads the request body, up to limit bytes, and replaces it with a copy for next
+func bufferBody(r *http.Request, limit int64) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, limit+1))
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, errors.New("request body exceeds dedupe limit")
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// storedHeader copies the response headers worth replaying: those next set, minus per-request ones
+func storedHeader(header http.Header, outer map[string]bool) http.Header {
+	stored := header.Clone()
+	for name := range outer {
+		delete(stored, name)
+	}
+	for _, name := range perRequestHeaders {
+		stored.Del(name)
+	}
+	return stored
+}
+
+// replay writes a stored response and returns its status, refusing with 422 when it was
+// stored for different content; ok is false when nothing was replayed
+func replay(w http.ResponseWriter, r *http.Request, data []byte, options replayOptions) (status int, ok bool) {
+	var stored storedResponse
+	if err := json.Unmarshal(data, &stored); err != nil {
+		apierror.Write(w, r, http.StatusInternalServerError, "stored response is unreadable")
+		return 0, false
+	}
+	if options.fingerprint != "" && stored.Fingerprint != options.fingerprint {
+		apierror.Write(w, r, http.StatusUnprocessableEntity, "key was already used for a different request")
+		return 0, false
+	}
+	for key, values := range stored.Header {
+		if !slices.Contains(perRequestHeaders, http.CanonicalHeaderKey(key)) {
+			w.Header()[key] = values
+		}
+	}
+	if requestID := r.Header.Get(requestIDHeader); requestID != "" {
+		w.Header().Set(requestIDHeader, requestID)
+	}
+	w.Header().Set(options.header, "true")
+	w.WriteHeader(stored.Status)
+	w.Write(stored.Body)
+	return stored.Status, true
+}
+
+// responseRecorder passes the response through while keeping a bounded copy
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+	limit       int64
+	overflow    bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	if !r.overflow {
+		if int64(r.body.Len()+len(b)) > r.limit {
+			r.overflow = true
+			r.body.Reset()
+		} else {
+			r.body.Write(b)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
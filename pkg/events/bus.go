@@ -0,0 +1,144 @@
+/**
+ * @fileoverview In-process bus for structured lifecycle and operational events.
+ * Publishers never block: each subscriber has a bounded buffer and events that do not fit
+ * are dropped for that subscriber. A short replay buffer lets reconnecting clients catch up.
+ */
+
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types published by the API server
+const (
+	TypeConfigReloaded    = "config.reloaded"
+	TypeCheckTransitioned = "check.transitioned"
+	TypeShutdownStarted   = "shutdown.started"
+	TypeWebhookReceived   = "webhook.received"
+	TypeJobSubmitted      = "job.submitted"
+)
+
+// DefaultReplaySize is the number of recent events kept for reconnecting subscribers
+const DefaultReplaySize = 100
+
+// Event is a single structured occurrence on the bus
+type Event struct {
+	ID   uint64    `json:"id"`
+	Type string    `json:"type"`
+	Time time.Time `json:"time"`
+	Data any       `json:"data,omitempty"`
+}
+
+// Bus fans events out to subscribers
+type Bus struct {
+	mu          sync.Mutex
+	nextID      uint64
+	subscribers map[chan Event]struct{}
+	recent      []Event
+	replaySize  int
+	dropped     uint64
+	closed      bool
+}
+
+/**
+ * @description Creates a bus that keeps the last replaySize events for replay.
+ * A non-positive replaySize uses DefaultReplaySize.
+ */
+func NewBus(replaySize int) *Bus {
+	if replaySize <= 0 {
+		replaySize = DefaultReplaySize
+	}
+	return &Bus{
+		subscribers: make(map[chan Event]struct{}),
+		replaySize:  replaySize,
+	}
+}
+
+/**
+ * @description Publishes an event of the given type to every subscriber without blocking.
+ */
+func (b *Bus) Publish(eventType string, data any) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	event := Event{ID: b.nextID, Type: eventType, Time: time.Now().UTC(), Data: data}
+
+	b.recent = append(b.recent, event)
+	if len(b.recent) > b.replaySize {
+		b.recent = b.recent[len(b.recent)-b.replaySize:]
+	}
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			b.dropped++
+		}
+	}
+	return event
+}
+
+/**
+ * @description Registers a subscriber with the given buffer size.
+ * Events newer than afterID are replayed first; pass 0 to receive only new events.
+ * The returned cancel function unsubscribes and closes the channel.
+ */
+func (b *Bus) Subscribe(buffer int, afterID uint64) (<-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var replay []Event
+	if afterID > 0 {
+		for _, event := range b.recent {
+			if event.ID > afterID {
+				replay = append(replay, event)
+			}
+		}
+	}
+
+	ch := make(chan Event, buffer+len(replay))
+	for _, event := range replay {
+		ch <- event
+	}
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+	return ch, cancel
+}
+
+/**
+ * @description Closes every subscriber channel after its buffered events so streams end during shutdown.
+ * Events published afterwards are still kept for replay but not delivered.
+ */
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
+
+/**
+ * @description Returns the number of events dropped because a subscriber was too slow.
+ */
+func (b *Bus) Dropped() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropped
+}
@@ -0,0 +1,89 @@
+/**
+ * @fileoverview Server-Sent Events handler streaming bus events to operators.
+ * Requires a bearer token, honours Last-Event-ID for replay, sends periodic heartbeats
+ * so proxies keep idle streams open, and reports tracker-initiated closes to the client.
+ */
+
+package events
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/streams"
+)
+
+const (
+	// HeartbeatInterval is how often an idle stream receives a comment line
+	HeartbeatInterval = 15 * time.Second
+	// subscriberBuffer bounds how far a slow client can fall behind before events are dropped
+	subscriberBuffer = 64
+)
+
+/**
+ * @description Returns a handler that streams bus events as SSE to clients presenting token.
+ * An optional ?type= filter accepts a comma-separated list of event types.
+ */
+func Handler(bus *Bus, token string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !httputil.HasBearerToken(r, token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			apierror.Write(w, r, http.StatusUnauthorized, "a valid bearer token is required")
+			return
+		}
+
+		var types map[string]bool
+		if filter := r.URL.Query().Get("type"); filter != "" {
+			types = make(map[string]bool)
+			for _, t := range strings.Split(filter, ",") {
+				types[strings.TrimSpace(t)] = true
+			}
+		}
+
+		lastID, _ := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64)
+		stream, cancel := bus.Subscribe(subscriberBuffer, lastID)
+		defer cancel()
+
+		sse, err := httputil.NewSSEWriter(w, r)
+		if err != nil {
+			apierror.Write(w, r, http.StatusInternalServerError, "streaming unsupported")
+			return
+		}
+
+		heartbeat := time.NewTicker(HeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-sse.Done():
+				// Tell the client why the tracker ended the stream, if it did
+				if stream := streams.FromContext(r.Context()); stream != nil && stream.CloseReason() != "" {
+					sse.SendJSON("", "close", map[string]string{"reason": stream.CloseReason()})
+				}
+				return
+			case <-heartbeat.C:
+				// Heartbeats bypass activity tracking so idle streams are still reaped
+				if _, err := streams.WriteKeepalive(w, []byte(": heartbeat\n\n")); err != nil {
+					return
+				}
+				if err := sse.Flush(); err != nil {
+					return
+				}
+			case event, ok := <-stream:
+				if !ok {
+					return
+				}
+				if types != nil && !types[event.Type] {
+					continue
+				}
+				if err := sse.SendJSON(strconv.FormatUint(event.ID, 10), event.Type, event); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
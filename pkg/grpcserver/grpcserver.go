@@ -0,0 +1,181 @@
+/**
+ * @fileoverview Embedded gRPC server with the same operational behavior as the HTTP server.
+ * Every RPC passes through recovery, logging, and metrics interceptors; the standard
+ * grpc.health.v1 service and server reflection are registered; and shutdown drains
+ * in-flight RPCs before forcing connections closed. The server can run on its own
+ * listener or share the HTTP listener, split by content type with cmux.
+ */
+
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/soheilhy/cmux"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+var (
+	grpcRequests  = metrics.NewCounterVec("grpc_requests_total", "gRPC calls handled, by status code.", "code")
+	grpcDurations = metrics.NewHistogramVec("grpc_request_duration_seconds", "gRPC call duration in seconds, by method.", "method", metrics.DurationBuckets)
+	grpcPanics    = metrics.NewCounter("grpc_panics_total", "gRPC handler panics recovered.")
+)
+
+// quietMethodPrefix marks health probes, which are counted but not logged
+const quietMethodPrefix = "/grpc.health.v1.Health/"
+
+// Server is a gRPC server with health checking and reflection registered
+type Server struct {
+	grpc   *grpc.Server
+	health *health.Server
+}
+
+/**
+ * @description Creates a server with recovery, logging, and metrics interceptors, the
+ * grpc.health.v1 service reporting SERVING, and server reflection. opts are passed to
+ * grpc.NewServer after the interceptors.
+ */
+func New(opts ...grpc.ServerOption) *Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryInterceptor),
+		grpc.ChainStreamInterceptor(streamInterceptor),
+	}, opts...)
+
+	s := &Server{grpc: grpc.NewServer(opts...), health: health.NewServer()}
+	healthpb.RegisterHealthServer(s.grpc, s.health)
+	reflection.Register(s.grpc)
+	return s
+}
+
+/**
+ * @description Registers a service implementation and reports it SERVING, so generated
+ * RegisterXServer functions accept the Server directly.
+ */
+func (s *Server) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	s.grpc.RegisterService(desc, impl)
+	s.health.SetServingStatus(desc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+}
+
+/**
+ * @description Serves RPCs on listener until Shutdown; returns nil after a shutdown.
+ */
+func (s *Server) Serve(listener net.Listener) error {
+	if err := s.grpc.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+/**
+ * @description Serves gRPC and HTTP on one listener, sending HTTP/2 requests with a gRPC
+ * content type to the gRPC server and everything else to serveHTTP. Returns when serveHTTP
+ * returns, closing the shared listener. TLS must be terminated in front of the listener.
+ */
+func (s *Server) ServeMultiplexed(listener net.Listener, serveHTTP func(net.Listener) error) error {
+	mux := cmux.New(listener)
+	grpcListener := mux.MatchWithWriters(cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"))
+	httpListener := mux.Match(cmux.Any())
+
+	go func() {
+		if err := s.Serve(grpcListener); err != nil && !errors.Is(err, cmux.ErrServerClosed) {
+			log.Printf("gRPC server stopped: %v", err)
+		}
+	}()
+	go func() {
+		if err := mux.Serve(); err != nil && !errors.Is(err, net.ErrClosed) {
+			log.Printf("Listener multiplexer stopped: %v", err)
+		}
+	}()
+
+	err := serveHTTP(httpListener)
+	mux.Close()
+	return err
+}
+
+/**
+ * @description Reports every service as serving or not serving on grpc.health.v1, e.g.
+ * NOT_SERVING while shutting down so clients stop picking this instance.
+ */
+func (s *Server) SetServing(serving bool) {
+	if serving {
+		s.health.Resume()
+	} else {
+		s.health.Shutdown()
+	}
+}
+
+/**
+ * @description Stops accepting RPCs and waits for in-flight ones to finish, forcing
+ * connections closed if ctx expires first.
+ */
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpc.Stop()
+		return ctx.Err()
+	}
+}
+
+// unaryInterceptor recovers panics and records every unary call
+func unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	start := time.Now()
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(info.FullMethod, p)
+		}
+		observe(info.FullMethod, start, err)
+	}()
+	return handler(ctx, req)
+}
+
+// streamInterceptor recovers panics and records every streaming call
+func streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	start := time.Now()
+	defer func() {
+		if p := recover(); p != nil {
+			err = recovered(info.FullMethod, p)
+		}
+		observe(info.FullMethod, start, err)
+	}()
+	return handler(srv, stream)
+}
+
+// recovered logs a handler panic and converts it to an Internal status
+func recovered(method string, value any) error {
+	grpcPanics.Add(1)
+	log.Printf("Panic in gRPC %s: %v\n%s", method, value, debug.Stack())
+	return status.Error(codes.Internal, "internal server error")
+}
+
+// observe counts and times a finished call and logs it unless it is a health probe
+func observe(method string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	code := status.Code(err)
+	grpcRequests.With(code.String()).Add(1)
+	grpcDurations.With(method).Observe(elapsed.Seconds())
+	if !strings.HasPrefix(method, quietMethodPrefix) {
+		log.Printf("gRPC %s %s %.3fms", method, code, float64(elapsed.Microseconds())/1000)
+	}
+}
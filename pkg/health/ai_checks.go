@@ -0,0 +1,238 @@
+/**
+ * @fileoverview Health checks for AI serving dependencies such as embedding models, upstream
+ * model APIs, and vector stores. Catches configuration mismatches and quota exhaustion that
+ * would otherwise produce silently wrong results or failed requests.
+ * Checks are plain CheckFuncs and can also be invoked once at startup to fail fast.
+ */
+
+package health
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LLM providers with built-in endpoints for LLMProviderCheck; any http(s) base URL is
+// treated as an OpenAI-compatible API
+const (
+	LLMProviderOpenAI    = "openai"
+	LLMProviderAnthropic = "anthropic"
+)
+
+const (
+	// LLMProviderTimeout bounds a single model-listing request
+	LLMProviderTimeout = 10 * time.Second
+	// anthropicAPIVersion is the API version header sent to Anthropic
+	anthropicAPIVersion = "2023-06-01"
+	// statusOverloaded is returned by some providers when they shed load
+	statusOverloaded = 529
+)
+
+// llmErrorResponse covers the error shapes used by OpenAI-compatible and Anthropic APIs
+type llmErrorResponse struct {
+	Error struct {
+		Type    string `json:"type"`
+		Code    any    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// DimensionFunc reports the vector dimensionality of a model or collection
+type DimensionFunc func() (int, error)
+
+// ErrDimensionMismatch is wrapped by EmbeddingDimensionCheck when the dimensions disagree,
+// as opposed to failing to determine them
+var ErrDimensionMismatch = errors.New("embedding dimension mismatch")
+
+/**
+ * @description Creates a check that verifies an embedding model's output dimensionality
+ * matches the dimensionality of the target vector collection.
+ * Mismatched dimensions make similarity search return garbage instead of failing loudly.
+ */
+func EmbeddingDimensionCheck(model, collection string, modelDims, collectionDims DimensionFunc) CheckFunc {
+	return func() error {
+		expected, err := modelDims()
+		if err != nil {
+			return fmt.Errorf("failed to determine dimensions of embedding model %s: %w", model, err)
+		}
+
+		actual, err := collectionDims()
+		if err != nil {
+			return fmt.Errorf("failed to determine dimensions of collection %s: %w", collection, err)
+		}
+
+		if expected != actual {
+			return fmt.Errorf("%w: embedding model %s produces %d dimensions but collection %s expects %d",
+				ErrDimensionMismatch, model, expected, collection, actual)
+		}
+
+		return nil
+	}
+}
+
+/**
+ * @description Returns a DimensionFunc that always reports the given dimensionality.
+ * Useful when a model's dimensions are known from configuration rather than probed.
+ */
+func StaticDimensions(dims int) DimensionFunc {
+	return func() (int, error) {
+		return dims, nil
+	}
+}
+
+/**
+ * @description Creates a check that lists models from an upstream LLM API, authenticating with
+ * the key in the apiKeyEnv environment variable. provider is "openai", "anthropic", or the base
+ * URL of an OpenAI-compatible API (e.g. "http://vllm:8000/v1"). Rate limiting, quota
+ * exhaustion, and overload report degraded; authentication and server errors fail.
+ */
+func LLMProviderCheck(provider, apiKeyEnv string) CheckFunc {
+	client := &http.Client{Timeout: LLMProviderTimeout}
+
+	return func() error {
+		apiKey := os.Getenv(apiKeyEnv)
+		req, err := llmModelsRequest(provider, apiKey)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("LLM provider %s unreachable: %w", provider, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, httpMaxDrainBytes))
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return nil
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == statusOverloaded:
+			return Degraded(fmt.Errorf("LLM provider %s %s", provider, llmErrorDetail(resp.StatusCode, body)))
+		case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+			return fmt.Errorf("LLM provider %s rejected the API key from %s: %s", provider, apiKeyEnv, llmErrorDetail(resp.StatusCode, body))
+		default:
+			return fmt.Errorf("LLM provider %s %s", provider, llmErrorDetail(resp.StatusCode, body))
+		}
+	}
+}
+
+// llmModelsRequest builds the authenticated model-listing request for provider
+func llmModelsRequest(provider, apiKey string) (*http.Request, error) {
+	var endpoint string
+	switch {
+	case provider == LLMProviderOpenAI:
+		endpoint = "https://api.openai.com/v1/models"
+	case provider == LLMProviderAnthropic:
+		endpoint = "https://api.anthropic.com/v1/models"
+	case strings.HasPrefix(provider, "http://") || strings.HasPrefix(provider, "https://"):
+		endpoint = strings.TrimSuffix(provider, "/") + "/models"
+	default:
+		return nil, fmt.Errorf("unknown LLM provider %q (expected openai, anthropic, or a base URL)", provider)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LLM provider URL: %w", err)
+	}
+	if provider == LLMProviderAnthropic {
+		if apiKey == "" {
+			return nil, fmt.Errorf("no API key configured for %s", provider)
+		}
+		req.Header.Set("x-api-key", apiKey)
+		req.Header.Set("anthropic-version", anthropicAPIVersion)
+	} else if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	} else if provider == LLMProviderOpenAI {
+		return nil, fmt.Errorf("no API key configured for %s", provider)
+	}
+	return req, nil
+}
+
+// llmErrorDetail describes an error response, naming quota exhaustion explicitly
+func llmErrorDetail(status int, body []byte) string {
+	var parsed llmErrorResponse
+	if json.Unmarshal(body, &parsed) != nil || parsed.Error.Message == "" {
+		return fmt.Sprintf("returned HTTP %d", status)
+	}
+	if code, _ := parsed.Error.Code.(string); code == "insufficient_quota" || parsed.Error.Type == "insufficient_quota" {
+		return fmt.Sprintf("quota exhausted (HTTP %d): %s", status, parsed.Error.Message)
+	}
+	if status == http.StatusTooManyRequests {
+		return fmt.Sprintf("rate limited (HTTP %d): %s", status, parsed.Error.Message)
+	}
+	return fmt.Sprintf("returned HTTP %d: %s", status, parsed.Error.Message)
+}
+
+/**
+ * @description Creates a check that verifies model weights at path exist, are at least minSize
+ * bytes, and match expectedSHA256 (hex; "" skips the checksum). The checksum is only
+ * recomputed when the file's size or modification time changes, so large files are hashed
+ * once rather than on every probe.
+ */
+func ModelFileCheck(path, expectedSHA256 string, minSize int64) CheckFunc {
+	expectedSHA256 = strings.ToLower(strings.TrimSpace(expectedSHA256))
+
+	var mu sync.Mutex
+	var verifiedSize int64
+	var verifiedModTime time.Time
+
+	return func() error {
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("model file %s does not exist", path)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to stat model file %s: %w", path, err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("model file %s is a directory", path)
+		}
+		if info.Size() < minSize {
+			return fmt.Errorf("model file %s is %s, expected at least %s (incomplete download?)",
+				path, formatBytes(uint64(info.Size())), formatBytes(uint64(minSize)))
+		}
+		if expectedSHA256 == "" {
+			return nil
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if info.Size() == verifiedSize && info.ModTime().Equal(verifiedModTime) {
+			return nil
+		}
+
+		actual, err := fileSHA256(path)
+		if err != nil {
+			return fmt.Errorf("failed to checksum model file %s: %w", path, err)
+		}
+		if actual != expectedSHA256 {
+			return fmt.Errorf("model file %s has SHA-256 %s, expected %s", path, actual, expectedSHA256)
+		}
+		verifiedSize, verifiedModTime = info.Size(), info.ModTime()
+		return nil
+	}
+}
+
+// fileSHA256 returns the hex SHA-256 digest of the file at path
+func fileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -7,9 +7,10 @@
 package health
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"net"
-	"net/http"
 	"os"
 	"time"
 )
@@ -47,24 +48,40 @@ func TCPConnectionCheck(host, port string, timeout time.Duration) CheckFunc {
 }
 
 /**
- * @description Creates a check that performs an HTTP GET request to verify service availability.
- * Useful for checking external HTTP dependencies and health endpoints.
+ * @description Creates a check that pings a database connection pool within the timeout.
+ * Works with any database/sql driver; verifies a connection can be established or reused.
  */
-func HTTPCheck(url string, timeout time.Duration, expectedStatusCode int) CheckFunc {
+func DatabaseCheck(db *sql.DB, timeout time.Duration) CheckFunc {
+	return DatabaseQueryCheck(db, timeout, "")
+}
+
+/**
+ * @description Creates a check that pings a database and then runs a validation query.
+ * The query (e.g. "SELECT 1") must succeed within the same timeout; an empty query only pings.
+ */
+func DatabaseQueryCheck(db *sql.DB, timeout time.Duration, validationQuery string) CheckFunc {
 	return func() error {
-		client := &http.Client{
-			Timeout: timeout,
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := db.PingContext(ctx); err != nil {
+			return fmt.Errorf("database ping failed: %w", err)
+		}
+
+		if validationQuery == "" {
+			return nil
 		}
 
-		resp, err := client.Get(url)
+		rows, err := db.QueryContext(ctx, validationQuery)
 		if err != nil {
-			return fmt.Errorf("HTTP request failed to %s: %w", url, err)
+			return fmt.Errorf("database validation query failed: %w", err)
 		}
-		defer resp.Body.Close()
+		defer rows.Close()
 
-		if resp.StatusCode != expectedStatusCode {
-			return fmt.Errorf("unexpected status code from %s: got %d, expected %d",
-				url, resp.StatusCode, expectedStatusCode)
+		for rows.Next() {
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("database validation query failed: %w", err)
 		}
 
 		return nil
@@ -0,0 +1,40 @@
+/**
+ * @fileoverview Clock abstraction used by the health package for all time measurements.
+ * Allows uptime, timestamps, and time-based caching to be driven deterministically.
+ * Defaults to the system clock when no clock is configured.
+ */
+
+package health
+
+import "time"
+
+// Clock provides the current time and elapsed durations
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+}
+
+// systemClock implements Clock using the time package
+type systemClock struct{}
+
+/**
+ * @description Returns the current wall-clock time.
+ */
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+/**
+ * @description Returns the time elapsed since t.
+ */
+func (systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+/**
+ * @description Returns a Clock backed by the system time.
+ * Used as the default when HealthCheckerConfig.Clock is nil.
+ */
+func SystemClock() Clock {
+	return systemClock{}
+}
@@ -0,0 +1,124 @@
+/**
+ * @fileoverview Cloud instance metadata service check.
+ * Verifies the metadata endpoint that supplies instance identity and credentials is
+ * reachable, using each provider's required headers and the IMDSv2 session-token flow
+ * on AWS.
+ */
+
+package health
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Cloud providers supported by CloudMetadataCheck
+const (
+	CloudAWS   = "aws"
+	CloudGCP   = "gcp"
+	CloudAzure = "azure"
+)
+
+const (
+	awsMetadataBase   = "http://169.254.169.254"
+	gcpMetadataBase   = "http://metadata.google.internal"
+	azureMetadataBase = "http://169.254.169.254"
+	// awsTokenTTLSeconds is the lifetime requested for IMDSv2 session tokens
+	awsTokenTTLSeconds = "60"
+)
+
+/**
+ * @description Creates a check that fetches the instance ID from the provider's metadata service.
+ * On AWS an IMDSv2 session token is requested first, falling back to IMDSv1 only when the
+ * endpoint does not support tokens.
+ */
+func CloudMetadataCheck(provider string, timeout time.Duration) CheckFunc {
+	switch provider {
+	case CloudAWS:
+		return cloudMetadataCheck(provider, awsMetadataBase, timeout)
+	case CloudGCP:
+		return cloudMetadataCheck(provider, gcpMetadataBase, timeout)
+	case CloudAzure:
+		return cloudMetadataCheck(provider, azureMetadataBase, timeout)
+	default:
+		return func() error {
+			return fmt.Errorf("unknown cloud provider %q (expected aws, gcp, or azure)", provider)
+		}
+	}
+}
+
+// cloudMetadataCheck queries the provider's metadata service at base
+func cloudMetadataCheck(provider, base string, timeout time.Duration) CheckFunc {
+	// Metadata services are link-local; never route them through an HTTP proxy
+	client := &http.Client{Timeout: timeout, Transport: &http.Transport{Proxy: nil}}
+
+	return func() error {
+		var req *http.Request
+		var err error
+		switch provider {
+		case CloudAWS:
+			req, err = http.NewRequest(http.MethodGet, base+"/latest/meta-data/instance-id", nil)
+			if err == nil {
+				var token string
+				if token, err = awsMetadataToken(client, base); err == nil && token != "" {
+					req.Header.Set("X-aws-ec2-metadata-token", token)
+				}
+			}
+		case CloudGCP:
+			req, err = http.NewRequest(http.MethodGet, base+"/computeMetadata/v1/instance/id", nil)
+			if err == nil {
+				req.Header.Set("Metadata-Flavor", "Google")
+			}
+		case CloudAzure:
+			req, err = http.NewRequest(http.MethodGet, base+"/metadata/instance/compute/vmId?api-version=2021-02-01&format=text", nil)
+			if err == nil {
+				req.Header.Set("Metadata", "true")
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("%s metadata service: %w", provider, err)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("%s metadata service unreachable: %w", provider, err)
+		}
+		defer resp.Body.Close()
+		identity, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("%s metadata service returned HTTP %d", provider, resp.StatusCode)
+		}
+		if len(identity) == 0 {
+			return fmt.Errorf("%s metadata service returned an empty instance ID", provider)
+		}
+		return nil
+	}
+}
+
+// awsMetadataToken requests an IMDSv2 session token; an empty token means the endpoint only supports IMDSv1
+func awsMetadataToken(client *http.Client, base string) (string, error) {
+	req, err := http.NewRequest(http.MethodPut, base+"/latest/api/token", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", awsTokenTTLSeconds)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// A token request that times out from a container usually means the hop limit is 1
+		return "", fmt.Errorf("IMDSv2 token request failed (check the instance's metadata hop limit): %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		token, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return string(token), err
+	case http.StatusNotFound, http.StatusMethodNotAllowed:
+		return "", nil
+	default:
+		return "", fmt.Errorf("IMDSv2 token request returned HTTP %d", resp.StatusCode)
+	}
+}
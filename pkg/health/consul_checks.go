@@ -0,0 +1,66 @@
+/**
+ * @fileoverview Consul agent check.
+ * Asks the local agent for the current Raft leader, which only succeeds when the agent is
+ * up and connected to a cluster that has elected one, so discovery-dependent services
+ * do not report ready against an isolated or leaderless agent.
+ */
+
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// ConsulTimeout bounds a single Consul agent request
+const ConsulTimeout = 5 * time.Second
+
+/**
+ * @description Creates a check that queries /v1/status/leader on the Consul agent at addr
+ * ("host:port" or a URL; "" uses http://127.0.0.1:8500) and requires an elected leader.
+ * Sends CONSUL_HTTP_TOKEN when set, for agents with ACLs enabled.
+ */
+func ConsulCheck(addr string) CheckFunc {
+	switch {
+	case addr == "":
+		addr = "http://127.0.0.1:8500"
+	case !strings.Contains(addr, "://"):
+		addr = "http://" + addr
+	}
+	endpoint := strings.TrimSuffix(addr, "/") + "/v1/status/leader"
+	client := &http.Client{Timeout: ConsulTimeout}
+
+	return func() error {
+		req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+		if err != nil {
+			return fmt.Errorf("invalid consul address: %w", err)
+		}
+		if token := os.Getenv("CONSUL_HTTP_TOKEN"); token != "" {
+			req.Header.Set("X-Consul-Token", token)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("consul agent unreachable: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("consul leader query returned HTTP %d", resp.StatusCode)
+		}
+
+		var leader string
+		if err := json.NewDecoder(io.LimitReader(resp.Body, httpMaxDrainBytes)).Decode(&leader); err != nil {
+			return fmt.Errorf("invalid consul leader response: %w", err)
+		}
+		if leader == "" {
+			return errors.New("consul cluster has no leader")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !(linux || darwin || freebsd)
+
+/**
+ * @fileoverview Process CPU time fallback for platforms without getrusage.
+ */
+
+package health
+
+import (
+	"errors"
+	"time"
+)
+
+// processCPUTime is unsupported on this platform
+func processCPUTime() (time.Duration, error) {
+	return 0, errors.New("process CPU time is not supported on this platform")
+}
@@ -0,0 +1,21 @@
+//go:build linux || darwin || freebsd
+
+/**
+ * @fileoverview Process CPU time from getrusage on Linux, macOS, and FreeBSD.
+ */
+
+package health
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime returns the user plus system CPU time consumed by this process
+func processCPUTime() (time.Duration, error) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, err
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano()), nil
+}
@@ -0,0 +1,56 @@
+/**
+ * @fileoverview Disk space health check.
+ * Flags low free space on the filesystem holding logs, models, or uploads before writes
+ * start failing. Filesystem statistics come from statfs on supported platforms.
+ */
+
+package health
+
+import (
+	"fmt"
+)
+
+// diskUsage reports free (available to unprivileged users) and total bytes for a filesystem
+type diskUsage struct {
+	free  uint64
+	total uint64
+}
+
+/**
+ * @description Creates a check that fails when the filesystem containing path has less than
+ * minFreeBytes available or less than minFreePercent (0-100) of its capacity free.
+ * A zero threshold disables that limit.
+ */
+func DiskSpaceCheck(path string, minFreeBytes uint64, minFreePercent float64) CheckFunc {
+	return func() error {
+		usage, err := statDisk(path)
+		if err != nil {
+			return fmt.Errorf("failed to read disk usage for %s: %w", path, err)
+		}
+
+		if minFreeBytes > 0 && usage.free < minFreeBytes {
+			return fmt.Errorf("low disk space on %s: %s free, need %s", path, formatBytes(usage.free), formatBytes(minFreeBytes))
+		}
+		if minFreePercent > 0 && usage.total > 0 {
+			percent := float64(usage.free) / float64(usage.total) * 100
+			if percent < minFreePercent {
+				return fmt.Errorf("low disk space on %s: %.1f%% free, need %.1f%%", path, percent, minFreePercent)
+			}
+		}
+		return nil
+	}
+}
+
+// formatBytes renders a byte count with a binary unit suffix
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
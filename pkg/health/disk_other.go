@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd)
+
+/**
+ * @fileoverview Disk usage fallback for platforms without statfs.
+ */
+
+package health
+
+import "errors"
+
+// statDisk is unsupported on this platform
+func statDisk(path string) (diskUsage, error) {
+	return diskUsage{}, errors.New("disk usage is not supported on this platform")
+}
@@ -0,0 +1,22 @@
+//go:build linux || darwin || freebsd
+
+/**
+ * @fileoverview statfs-backed disk usage for Linux, macOS, and FreeBSD.
+ */
+
+package health
+
+import "syscall"
+
+// statDisk reads filesystem statistics for path via statfs
+func statDisk(path string) (diskUsage, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return diskUsage{}, err
+	}
+	blockSize := uint64(stat.Bsize)
+	return diskUsage{
+		free:  uint64(stat.Bavail) * blockSize,
+		total: uint64(stat.Blocks) * blockSize,
+	}, nil
+}
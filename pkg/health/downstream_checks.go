@@ -0,0 +1,86 @@
+/**
+ * @fileoverview Health checks that cascade the health of other services.
+ * Parses the JSON produced by this package's HealthHandler on a downstream service
+ * and propagates its healthy/degraded/unhealthy state into the local checker.
+ */
+
+package health
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/**
+ * @description Creates a check that calls a downstream service's health endpoint and propagates its status.
+ * When onlyChecks is non-empty, only those named downstream checks are considered instead of the aggregate.
+ * Downstream degradation is reported as Degraded; downstream failure fails this check.
+ */
+func DownstreamHealthCheck(url string, timeout time.Duration, onlyChecks ...string) CheckFunc {
+	client := &http.Client{
+		Timeout: timeout,
+	}
+
+	return func() error {
+		resp, err := client.Get(url)
+		if err != nil {
+			return fmt.Errorf("downstream health request failed to %s: %w", url, err)
+		}
+		defer resp.Body.Close()
+
+		var downstream CheckResult
+		if err := json.NewDecoder(resp.Body).Decode(&downstream); err != nil {
+			return fmt.Errorf("failed to parse downstream health response from %s (status %d): %w",
+				url, resp.StatusCode, err)
+		}
+
+		if len(onlyChecks) == 0 {
+			return downstreamStatusError(url, downstream.Status, resp.StatusCode)
+		}
+		return downstreamChecksError(url, downstream.Checks, onlyChecks)
+	}
+}
+
+// downstreamStatusError maps a downstream aggregate status to a local check outcome
+func downstreamStatusError(url, status string, statusCode int) error {
+	switch status {
+	case StatusHealthy:
+		return nil
+	case StatusDegraded:
+		return Degraded(fmt.Errorf("downstream %s is degraded", url))
+	case "":
+		return fmt.Errorf("downstream %s returned no status (HTTP %d)", url, statusCode)
+	default:
+		return fmt.Errorf("downstream %s is %s", url, status)
+	}
+}
+
+// downstreamChecksError evaluates selected downstream checks, failing over degrading
+func downstreamChecksError(url string, checks map[string]string, names []string) error {
+	var failed, degraded []string
+
+	for _, name := range names {
+		value, ok := checks[name]
+		switch {
+		case !ok:
+			failed = append(failed, name+" (missing)")
+		case value == "ok":
+		case strings.HasPrefix(value, StatusDegraded):
+			degraded = append(degraded, name)
+		default:
+			failed = append(failed, name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("downstream %s checks failing: %s", url, strings.Join(failed, ", "))
+	}
+	if len(degraded) > 0 {
+		return Degraded(errors.New("downstream " + url + " checks degraded: " + strings.Join(degraded, ", ")))
+	}
+	return nil
+}
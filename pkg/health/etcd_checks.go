@@ -0,0 +1,93 @@
+/**
+ * @fileoverview etcd cluster check using each member's /health endpoint.
+ * Probes every configured endpoint in parallel and reports per-member reachability:
+ * a cluster with a healthy quorum but unreachable members is degraded, and one
+ * without a healthy quorum fails.
+ */
+
+package health
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// etcdHealthResponse is the body served by etcd's /health endpoint
+type etcdHealthResponse struct {
+	Health string `json:"health"`
+	Reason string `json:"reason"`
+}
+
+/**
+ * @description Creates a check that calls /health on each etcd endpoint (e.g. "https://etcd-0:2379").
+ * tlsConfig, when set, supplies the CA and client certificate for https endpoints.
+ */
+func EtcdCheck(endpoints []string, timeout time.Duration, tlsConfig *tls.Config) CheckFunc {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	client := &http.Client{Timeout: timeout, Transport: transport}
+
+	return func() error {
+		if len(endpoints) == 0 {
+			return errors.New("no etcd endpoints configured")
+		}
+
+		results := make([]error, len(endpoints))
+		var wg sync.WaitGroup
+		for i, endpoint := range endpoints {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				results[i] = probeEtcdMember(client, endpoint)
+			}()
+		}
+		wg.Wait()
+
+		var healthy int
+		var failures []string
+		for i, err := range results {
+			if err == nil {
+				healthy++
+				continue
+			}
+			failures = append(failures, fmt.Sprintf("%s: %v", endpoints[i], err))
+		}
+
+		switch {
+		case len(failures) == 0:
+			return nil
+		case healthy > len(endpoints)/2:
+			return Degraded(fmt.Errorf("etcd %d/%d members healthy; %s", healthy, len(endpoints), strings.Join(failures, "; ")))
+		default:
+			return fmt.Errorf("etcd has no healthy quorum (%d/%d members healthy); %s", healthy, len(endpoints), strings.Join(failures, "; "))
+		}
+	}
+}
+
+// probeEtcdMember calls one member's /health endpoint
+func probeEtcdMember(client *http.Client, endpoint string) error {
+	resp, err := client.Get(strings.TrimSuffix(endpoint, "/") + "/health")
+	if err != nil {
+		return fmt.Errorf("unreachable: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body etcdHealthResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, httpMaxDrainBytes)).Decode(&body); err != nil {
+		return fmt.Errorf("invalid health response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if body.Health != "true" {
+		if body.Reason != "" {
+			return fmt.Errorf("unhealthy: %s", body.Reason)
+		}
+		return errors.New("unhealthy")
+	}
+	return nil
+}
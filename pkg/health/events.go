@@ -0,0 +1,154 @@
+/**
+ * @fileoverview Check execution and event reporting for the HealthChecker.
+ * Runs individual checks, measures their duration, and reports failures, slow checks,
+ * and status transitions through a pluggable structured Logger and optional tracer spans.
+ */
+
+package health
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DefaultSlowCheckThreshold is the duration after which a check is logged as slow
+const DefaultSlowCheckThreshold = 2 * time.Second
+
+// Logger receives structured health events; *slog.Logger satisfies this interface
+type Logger interface {
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// TransitionEvent describes a change in a check's or aggregate's status
+type TransitionEvent struct {
+	Kind      string `json:"kind"`
+	Check     string `json:"check"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Error     string `json:"error,omitempty"`
+	Timestamp string `json:"timestamp"`
+}
+
+// TransitionListener is notified of status transitions; it must not block
+type TransitionListener func(TransitionEvent)
+
+// nopLogger discards all events
+type nopLogger struct{}
+
+func (nopLogger) Info(string, ...any)  {}
+func (nopLogger) Warn(string, ...any)  {}
+func (nopLogger) Error(string, ...any) {}
+
+/**
+ * @description Executes a single check, logging failures and slow executions
+ * and recording the outcome so status transitions can be reported.
+ * When a tracer is configured the check runs inside a span that is a child of ctx.
+ */
+func (hc *HealthChecker) runCheck(ctx context.Context, kind, name string, check CheckFunc) error {
+	var span trace.Span
+	if hc.tracer != nil {
+		_, span = hc.tracer.Start(ctx, "health.check "+name,
+			trace.WithAttributes(
+				attribute.String("health.check.kind", kind),
+				attribute.String("health.check.name", name),
+			))
+	}
+
+	start := hc.clock.Now()
+	err := check()
+	duration := hc.clock.Since(start)
+
+	if span != nil {
+		endCheckSpan(span, err, duration)
+	}
+
+	if duration > hc.slowThreshold {
+		hc.logger.Warn("health check slow", "kind", kind, "check", name, "duration", duration)
+	}
+	status := "ok"
+	switch {
+	case isPending(err):
+		hc.logger.Warn("health check failed below its failure threshold", "kind", kind, "check", name, "duration", duration, "error", err)
+	case IsDegraded(err):
+		status = "degraded"
+		hc.logger.Warn("health check degraded", "kind", kind, "check", name, "duration", duration, "error", err)
+	case err != nil:
+		status = "failed"
+		hc.logger.Error("health check failed", "kind", kind, "check", name, "duration", duration, "error", err)
+	}
+	hc.recordHistory(kind, name, status, err, duration)
+	hc.recordTransition(kind, name, status, err)
+
+	return err
+}
+
+/**
+ * @description Adds a listener notified whenever a check or aggregate status changes.
+ * Listeners run synchronously on the checking goroutine and must hand off slow work.
+ */
+func (hc *HealthChecker) AddTransitionListener(listener TransitionListener) {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+	hc.listeners = append(hc.listeners, listener)
+}
+
+/**
+ * @description Remembers the latest status for a check or aggregate and reports when it changes.
+ * The first observation is recorded without reporting a transition.
+ */
+func (hc *HealthChecker) recordTransition(kind, name, status string, err error) {
+	key := kind + "/" + name
+
+	hc.mu.Lock()
+	previous, seen := hc.lastStatus[key]
+	hc.lastStatus[key] = status
+	listeners := hc.listeners
+	hc.mu.Unlock()
+
+	if !seen || previous == status {
+		return
+	}
+
+	hc.logger.Info("health status changed", "kind", kind, "check", name, "from", previous, "to", status)
+
+	event := TransitionEvent{
+		Kind:      kind,
+		Check:     name,
+		From:      previous,
+		To:        status,
+		Timestamp: hc.clock.Now().UTC().Format(time.RFC3339),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	for _, listener := range listeners {
+		listener(event)
+	}
+}
+
+// endCheckSpan records the check outcome and duration on the span and ends it
+func endCheckSpan(span trace.Span, err error, duration time.Duration) {
+	outcome := "ok"
+	switch {
+	case isPending(err):
+		// Not yet counted as a failure
+	case IsDegraded(err):
+		outcome = "degraded"
+	case err != nil:
+		outcome = "failed"
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.SetAttributes(
+		attribute.String("health.check.outcome", outcome),
+		attribute.Int64("health.check.duration_ms", duration.Milliseconds()),
+	)
+	span.End()
+}
@@ -0,0 +1,90 @@
+/**
+ * @fileoverview Health checks for files the service depends on.
+ * Covers config files, mounted secrets, and data files, including detection of
+ * feeds that have stopped being updated, and directories that must accept writes.
+ */
+
+package health
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+/**
+ * @description Creates a check that fails when path is missing, cannot be opened with the
+ * required access, or (when maxAge is positive) was last modified more than maxAge ago.
+ * Access is verified by opening the file, so it reflects the process's real permissions;
+ * the write probe opens without truncating or modifying the file.
+ */
+func FileCheck(path string, mustBeReadable, mustBeWritable bool, maxAge time.Duration) CheckFunc {
+	return func() error {
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("file %s does not exist", path)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+
+		if mustBeReadable {
+			if err := probeOpen(path, os.O_RDONLY); err != nil {
+				return fmt.Errorf("file %s is not readable: %w", path, err)
+			}
+		}
+		if mustBeWritable {
+			if info.IsDir() {
+				return fmt.Errorf("%s is a directory; use WritableDirectoryCheck to probe write access", path)
+			}
+			if err := probeOpen(path, os.O_WRONLY); err != nil {
+				return fmt.Errorf("file %s is not writable: %w", path, err)
+			}
+		}
+
+		if maxAge > 0 {
+			if age := time.Since(info.ModTime()); age > maxAge {
+				return fmt.Errorf("file %s is stale: last modified %v ago, limit %v", path, age.Round(time.Second), maxAge)
+			}
+		}
+		return nil
+	}
+}
+
+// probeOpen opens and immediately closes path with the given access flag
+func probeOpen(path string, flag int) error {
+	file, err := os.OpenFile(path, flag, 0)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+/**
+ * @description Creates a check that creates, writes, syncs, and removes a temporary file in dir,
+ * failing when the directory is missing, read-only (e.g. a read-only root filesystem), or full.
+ */
+func WritableDirectoryCheck(dir string) CheckFunc {
+	return func() error {
+		file, err := os.CreateTemp(dir, ".healthcheck-*")
+		if err != nil {
+			return fmt.Errorf("directory %s is not writable: %w", dir, err)
+		}
+		defer os.Remove(file.Name())
+
+		_, writeErr := file.Write([]byte("ok"))
+		if writeErr == nil {
+			writeErr = file.Sync()
+		}
+		closeErr := file.Close()
+		if err := errors.Join(writeErr, closeErr); err != nil {
+			return fmt.Errorf("failed to write to directory %s: %w", dir, err)
+		}
+
+		if err := os.Remove(file.Name()); err != nil {
+			return fmt.Errorf("failed to remove probe file from %s: %w", dir, err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,177 @@
+/**
+ * @fileoverview Fleet health aggregation for simple non-Kubernetes deployments.
+ * Polls a list of peer instances' health endpoints with one probe.Prober each and serves
+ * a combined view with per-instance results and a rolled-up fleet status.
+ */
+
+package fleet
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/health"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/health/probe"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+)
+
+// StatusUnknown marks a peer that has not been probed yet
+const StatusUnknown = "unknown"
+
+// Peer is one instance in the fleet
+type Peer struct {
+	Name string
+	URL  string
+}
+
+// Config configures an Aggregator
+type Config struct {
+	Peers    []Peer
+	Interval time.Duration
+	Timeout  time.Duration
+	// MinHealthy is how many peers must be healthy for the fleet not to be unhealthy; defaults to 1
+	MinHealthy int
+}
+
+// InstanceStatus is the latest probe result for one peer
+type InstanceStatus struct {
+	Name       string            `json:"name"`
+	URL        string            `json:"url"`
+	Status     string            `json:"status"`
+	Checks     map[string]string `json:"checks,omitempty"`
+	LatencyMs  int64             `json:"latency_ms"`
+	LastProbed string            `json:"last_probed,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// View is the combined fleet status
+type View struct {
+	Status    string           `json:"status"`
+	Healthy   int              `json:"healthy"`
+	Degraded  int              `json:"degraded"`
+	Unhealthy int              `json:"unhealthy"`
+	Instances []InstanceStatus `json:"instances"`
+	Timestamp string           `json:"timestamp"`
+}
+
+// Aggregator polls every peer and rolls their statuses up
+type Aggregator struct {
+	config  Config
+	probers []*probe.Prober
+}
+
+/**
+ * @description Creates an aggregator with a prober per peer; call Run to start polling.
+ */
+func New(config Config) *Aggregator {
+	if config.MinHealthy < 1 {
+		config.MinHealthy = 1
+	}
+	aggregator := &Aggregator{config: config}
+	for _, peer := range config.Peers {
+		aggregator.probers = append(aggregator.probers, probe.New(probe.Config{
+			URL:      peer.URL,
+			Timeout:  config.Timeout,
+			Interval: config.Interval,
+		}))
+	}
+	return aggregator
+}
+
+/**
+ * @description Parses a comma-separated peer list; entries are either URLs or name=URL pairs.
+ */
+func ParsePeers(list string) ([]Peer, error) {
+	var peers []Peer
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		peer := Peer{Name: entry, URL: entry}
+		if name, url, ok := strings.Cut(entry, "="); ok {
+			peer = Peer{Name: strings.TrimSpace(name), URL: strings.TrimSpace(url)}
+		}
+		if !strings.HasPrefix(peer.URL, "http://") && !strings.HasPrefix(peer.URL, "https://") {
+			return nil, fmt.Errorf("invalid peer %q: URL must start with http:// or https://", entry)
+		}
+		peers = append(peers, peer)
+	}
+	return peers, nil
+}
+
+/**
+ * @description Polls all peers until ctx is cancelled, returning once every poller has stopped.
+ */
+func (a *Aggregator) Run(ctx context.Context) {
+	var pollers sync.WaitGroup
+	for _, prober := range a.probers {
+		pollers.Add(1)
+		go func() {
+			defer pollers.Done()
+			prober.Run(ctx)
+		}()
+	}
+	pollers.Wait()
+}
+
+/**
+ * @description Returns the current fleet view from the latest probe results.
+ * The fleet is healthy when every peer is healthy, unhealthy when fewer than
+ * MinHealthy peers are healthy, and degraded otherwise.
+ */
+func (a *Aggregator) View() View {
+	view := View{Instances: make([]InstanceStatus, 0, len(a.probers)), Timestamp: time.Now().UTC().Format(time.RFC3339)}
+
+	for i, prober := range a.probers {
+		peer := a.config.Peers[i]
+		instance := InstanceStatus{Name: peer.Name, URL: peer.URL, Status: StatusUnknown}
+		if result, ok := prober.Latest(); ok {
+			instance.Status = result.Status
+			instance.Checks = result.Checks
+			instance.LatencyMs = result.Latency.Milliseconds()
+			instance.LastProbed = result.Timestamp.UTC().Format(time.RFC3339)
+			if result.Err != nil {
+				instance.Error = result.Err.Error()
+			}
+		}
+
+		switch instance.Status {
+		case health.StatusHealthy:
+			view.Healthy++
+		case health.StatusDegraded:
+			view.Degraded++
+		default:
+			view.Unhealthy++
+		}
+		view.Instances = append(view.Instances, instance)
+	}
+	sort.Slice(view.Instances, func(i, j int) bool { return view.Instances[i].Name < view.Instances[j].Name })
+
+	switch {
+	case view.Healthy < a.config.MinHealthy:
+		view.Status = health.StatusUnhealthy
+	case view.Healthy == len(a.probers):
+		view.Status = health.StatusHealthy
+	default:
+		view.Status = health.StatusDegraded
+	}
+	return view
+}
+
+/**
+ * @description Serves the fleet view, answering 503 when the fleet is unhealthy.
+ */
+func (a *Aggregator) Handler(w http.ResponseWriter, r *http.Request) {
+	view := a.View()
+	statusCode := http.StatusOK
+	if view.Status == health.StatusUnhealthy {
+		statusCode = http.StatusServiceUnavailable
+	}
+	jsoncase.Write(w, statusCode, view)
+}
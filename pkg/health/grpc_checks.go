@@ -0,0 +1,121 @@
+/**
+ * @fileoverview gRPC dependency check.
+ * Establishes a real gRPC connection, including the TLS handshake and ALPN negotiation that a
+ * plain TCP check cannot see, and optionally queries the upstream's grpc.health.v1 service.
+ */
+
+package health
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// GRPCCheckOption configures GRPCCheck
+type GRPCCheckOption func(*grpcCheckConfig)
+
+// grpcCheckConfig holds the settings applied by GRPCCheckOptions
+type grpcCheckConfig struct {
+	tlsConfig   *tls.Config
+	checkHealth bool
+	service     string
+	dialOptions []grpc.DialOption
+}
+
+/**
+ * @description Connects over TLS with the given configuration instead of plaintext.
+ */
+func WithGRPCTLS(config *tls.Config) GRPCCheckOption {
+	return func(c *grpcCheckConfig) {
+		c.tlsConfig = config
+	}
+}
+
+/**
+ * @description Calls grpc.health.v1.Health/Check for service after connecting
+ * ("" asks for the server's overall status) and requires SERVING.
+ */
+func WithGRPCHealthService(service string) GRPCCheckOption {
+	return func(c *grpcCheckConfig) {
+		c.checkHealth = true
+		c.service = service
+	}
+}
+
+/**
+ * @description Appends raw dial options, e.g. per-RPC credentials or a custom dialer.
+ */
+func WithGRPCDialOptions(options ...grpc.DialOption) GRPCCheckOption {
+	return func(c *grpcCheckConfig) {
+		c.dialOptions = append(c.dialOptions, options...)
+	}
+}
+
+/**
+ * @description Creates a check that dials target and waits for the connection to become ready.
+ * A TLS or ALPN failure surfaces as a connection that never leaves TRANSIENT_FAILURE.
+ */
+func GRPCCheck(target string, timeout time.Duration, opts ...GRPCCheckOption) CheckFunc {
+	config := &grpcCheckConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	transport := insecure.NewCredentials()
+	if config.tlsConfig != nil {
+		transport = credentials.NewTLS(config.tlsConfig)
+	}
+	dialOptions := append([]grpc.DialOption{grpc.WithTransportCredentials(transport)}, config.dialOptions...)
+
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		conn, err := grpc.NewClient(target, dialOptions...)
+		if err != nil {
+			return fmt.Errorf("invalid gRPC target %s: %w", target, err)
+		}
+		defer conn.Close()
+
+		if err := waitForReady(ctx, conn); err != nil {
+			return fmt.Errorf("gRPC connection to %s failed: %w", target, err)
+		}
+
+		if !config.checkHealth {
+			return nil
+		}
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: config.service})
+		if err != nil {
+			return fmt.Errorf("gRPC health check of %s failed: %w", target, err)
+		}
+		if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+			return fmt.Errorf("gRPC service %q on %s is %s", config.service, target, resp.GetStatus())
+		}
+		return nil
+	}
+}
+
+// waitForReady drives conn to READY, reporting the last state seen when ctx expires
+func waitForReady(ctx context.Context, conn *grpc.ClientConn) error {
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return nil
+		case connectivity.Shutdown:
+			return fmt.Errorf("connection shut down")
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("not ready within timeout (last state %s)", state)
+		}
+	}
+}
@@ -7,19 +7,44 @@
 package health
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
 )
 
 // HealthChecker provides health and readiness check functionality
 type HealthChecker struct {
 	serviceName     string
 	serviceVersion  string
+	gitCommit       string
+	buildDate       string
+	goVersion       string
 	startTime       time.Time
+	clock           Clock
+	logger          Logger
+	tracer          trace.Tracer
+	slowThreshold   time.Duration
+	outbound        *httputil.OutboundRecorder
 	readinessChecks map[string]CheckFunc
 	healthChecks    map[string]CheckFunc
+	shuttingDown    atomic.Bool
+
+	mu          sync.Mutex
+	lastStatus  map[string]string
+	history     map[string][]CheckRecord
+	historySize int
+	listeners   []TransitionListener
+
+	// webhookQueue holds deliveries for the workers started by RunWebhooks
+	webhookQueue chan webhookDelivery
 }
 
 // CheckFunc represents a health check function that returns an error if unhealthy
@@ -33,67 +58,147 @@ type CheckResult struct {
 	Uptime    string            `json:"uptime,omitempty"`
 	Service   string            `json:"service,omitempty"`
 	Version   string            `json:"version,omitempty"`
+	Commit    string            `json:"commit,omitempty"`
+	BuildDate string            `json:"build_date,omitempty"`
+	GoVersion string            `json:"go_version,omitempty"`
 }
 
 // HealthCheckerConfig provides configuration options for the health checker
 type HealthCheckerConfig struct {
 	ServiceName    string
 	ServiceVersion string
+	// GitCommit, BuildDate, and GoVersion identify the deployed build in /health output
+	GitCommit string
+	BuildDate string
+	GoVersion string
+	// Clock overrides the time source; defaults to the system clock
+	Clock Clock
+	// Logger receives check failures, slow checks, and status transitions; defaults to discarding them
+	Logger Logger
+	// SlowCheckThreshold marks checks slower than this as slow; defaults to DefaultSlowCheckThreshold
+	SlowCheckThreshold time.Duration
+	// Tracer wraps each check execution in a span when set
+	Tracer trace.Tracer
+	// HistorySize is the number of executions retained per check; defaults to DefaultHistorySize
+	HistorySize int
+	// Outbound records failed outbound calls, included in diagnoses when set
+	Outbound *httputil.OutboundRecorder
 }
 
 /**
  * @description Creates a new HealthChecker instance with the provided configuration.
- * Initializes check maps and sets the start time for uptime calculations.
+ * Initializes check maps, the clock, and the start time for uptime calculations.
  */
 func NewHealthChecker(config HealthCheckerConfig) *HealthChecker {
+	clock := config.Clock
+	if clock == nil {
+		clock = SystemClock()
+	}
+
+	logger := config.Logger
+	if logger == nil {
+		logger = nopLogger{}
+	}
+
+	slowThreshold := config.SlowCheckThreshold
+	if slowThreshold <= 0 {
+		slowThreshold = DefaultSlowCheckThreshold
+	}
+
+	historySize := config.HistorySize
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+
 	return &HealthChecker{
 		serviceName:     config.ServiceName,
 		serviceVersion:  config.ServiceVersion,
-		startTime:       time.Now(),
+		gitCommit:       config.GitCommit,
+		buildDate:       config.BuildDate,
+		goVersion:       config.GoVersion,
+		startTime:       clock.Now(),
+		clock:           clock,
+		logger:          logger,
+		tracer:          config.Tracer,
+		slowThreshold:   slowThreshold,
+		outbound:        config.Outbound,
 		readinessChecks: make(map[string]CheckFunc),
 		healthChecks:    make(map[string]CheckFunc),
+		lastStatus:      make(map[string]string),
+		history:         make(map[string][]CheckRecord),
+		historySize:     historySize,
+		webhookQueue:    make(chan webhookDelivery, WebhookQueueSize),
 	}
 }
 
 /**
- * @description Adds a readiness check with the given name and check function.
+ * @description Adds a readiness check with the given name, check function, and options.
  * Readiness checks determine if the service is ready to accept traffic.
  */
-func (hc *HealthChecker) AddReadinessCheck(name string, check CheckFunc) {
-	hc.readinessChecks[name] = check
+func (hc *HealthChecker) AddReadinessCheck(name string, check CheckFunc, opts ...CheckOption) {
+	hc.readinessChecks[name] = applyCheckOptions(check, opts)
 }
 
 /**
- * @description Adds a health check with the given name and check function.
+ * @description Adds a health check with the given name, check function, and options.
  * Health checks determine if the service is functioning properly.
  */
-func (hc *HealthChecker) AddHealthCheck(name string, check CheckFunc) {
-	hc.healthChecks[name] = check
+func (hc *HealthChecker) AddHealthCheck(name string, check CheckFunc, opts ...CheckOption) {
+	hc.healthChecks[name] = applyCheckOptions(check, opts)
 }
 
 /**
  * @description HTTP handler for the health endpoint.
- * Returns service health status and executes all registered health checks.
+ * Returns service health status, build metadata, and executes all registered health checks.
  */
 func (hc *HealthChecker) HealthHandler(w http.ResponseWriter, r *http.Request) {
-	result := hc.performChecks(hc.healthChecks)
+	result := hc.performChecks(r.Context(), "health", hc.healthChecks)
 	result.Service = hc.serviceName
 	result.Version = hc.serviceVersion
-	result.Uptime = time.Since(hc.startTime).String()
+	result.Commit = hc.gitCommit
+	result.BuildDate = hc.buildDate
+	result.GoVersion = hc.goVersion
+	result.Uptime = hc.clock.Since(hc.startTime).String()
 
 	hc.writeJSONResponse(w, result, http.StatusOK)
 }
 
+/**
+ * @description Marks the service as shutting down so readiness fails without running checks,
+ * telling load balancers to stop sending new traffic while in-flight requests finish.
+ */
+func (hc *HealthChecker) SetShuttingDown(shuttingDown bool) {
+	hc.shuttingDown.Store(shuttingDown)
+}
+
+/**
+ * @description Reports whether SetShuttingDown marked the service as shutting down.
+ */
+func (hc *HealthChecker) ShuttingDown() bool {
+	return hc.shuttingDown.Load()
+}
+
 /**
  * @description HTTP handler for the readiness endpoint.
- * Returns service readiness status and executes all registered readiness checks.
+ * Returns service readiness status and executes all registered readiness checks,
+ * or reports unavailable without running them once the service is shutting down.
  */
 func (hc *HealthChecker) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
-	result := hc.performChecks(hc.readinessChecks)
+	if hc.shuttingDown.Load() {
+		hc.recordTransition("readiness", "aggregate", StatusUnhealthy, nil)
+		hc.writeJSONResponse(w, CheckResult{
+			Status:    StatusUnhealthy,
+			Checks:    map[string]string{"shutdown": "failed: service is shutting down"},
+			Timestamp: hc.clock.Now().UTC().Format(time.RFC3339),
+		}, http.StatusServiceUnavailable)
+		return
+	}
+
+	result := hc.performChecks(r.Context(), "readiness", hc.readinessChecks)
 
-	// Set appropriate status code based on check results
+	// Set appropriate status code based on check results; degraded still accepts traffic
 	statusCode := http.StatusOK
-	if result.Status != "healthy" {
+	if result.Status == StatusUnhealthy {
 		statusCode = http.StatusServiceUnavailable
 	}
 
@@ -102,13 +207,13 @@ func (hc *HealthChecker) ReadinessHandler(w http.ResponseWriter, r *http.Request
 
 /**
  * @description Performs all checks in the provided map and returns aggregated results.
- * Returns "healthy" status only if all checks pass, "unhealthy" otherwise.
+ * Returns "unhealthy" if any check fails, "degraded" if any check is degraded, and "healthy" otherwise.
  */
-func (hc *HealthChecker) performChecks(checks map[string]CheckFunc) CheckResult {
+func (hc *HealthChecker) performChecks(ctx context.Context, kind string, checks map[string]CheckFunc) CheckResult {
 	result := CheckResult{
-		Status:    "healthy",
+		Status:    StatusHealthy,
 		Checks:    make(map[string]string),
-		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Timestamp: hc.clock.Now().UTC().Format(time.RFC3339),
 	}
 
 	// If no checks are configured, default to healthy
@@ -119,35 +224,39 @@ func (hc *HealthChecker) performChecks(checks map[string]CheckFunc) CheckResult
 
 	// Execute all checks
 	hasFailures := false
+	hasDegraded := false
 	for name, checkFunc := range checks {
-		if err := checkFunc(); err != nil {
+		err := hc.runCheck(ctx, kind, name, checkFunc)
+		switch {
+		case err == nil:
+			result.Checks[name] = "ok"
+		case isPending(err):
+			result.Checks[name] = fmt.Sprintf("ok (%v)", err)
+		case IsDegraded(err):
+			result.Checks[name] = fmt.Sprintf("degraded: %v", err)
+			hasDegraded = true
+		default:
 			result.Checks[name] = fmt.Sprintf("failed: %v", err)
 			hasFailures = true
-		} else {
-			result.Checks[name] = "ok"
 		}
 	}
 
 	if hasFailures {
-		result.Status = "unhealthy"
+		result.Status = StatusUnhealthy
+	} else if hasDegraded {
+		result.Status = StatusDegraded
 	}
+	hc.recordTransition(kind, "aggregate", result.Status, nil)
 
 	return result
 }
 
 /**
  * @description Writes a JSON response with proper headers and error handling.
- * Sets content type and handles JSON marshaling errors gracefully.
+ * Uses the shared marshaling helpers so field casing matches the rest of the API.
  */
 func (hc *HealthChecker) writeJSONResponse(w http.ResponseWriter, result CheckResult, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-
-	if err := json.NewEncoder(w).Encode(result); err != nil {
-		// Fallback to simple error response if JSON encoding fails
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprintf(w, `{"status":"error","message":"failed to encode response"}`)
-	}
+	jsoncase.Write(w, statusCode, result)
 }
 
 /**
@@ -155,7 +264,7 @@ func (hc *HealthChecker) writeJSONResponse(w http.ResponseWriter, result CheckRe
  * Useful for external monitoring and debugging.
  */
 func (hc *HealthChecker) GetUptime() time.Duration {
-	return time.Since(hc.startTime)
+	return hc.clock.Since(hc.startTime)
 }
 
 /**
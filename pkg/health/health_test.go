@@ -0,0 +1,132 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when advanced
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// recordingLogger keeps the messages logged at warn level
+type recordingLogger struct {
+	mu    sync.Mutex
+	warns []string
+}
+
+func (l *recordingLogger) Info(msg string, args ...any)  {}
+func (l *recordingLogger) Error(msg string, args ...any) {}
+
+func (l *recordingLogger) Warn(msg string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.warns = append(l.warns, msg)
+}
+
+func TestUptimeFollowsClock(t *testing.T) {
+	tests := []struct {
+		name    string
+		advance []time.Duration
+		want    time.Duration
+	}{
+		{name: "just started", want: 0},
+		{name: "one step", advance: []time.Duration{90 * time.Second}, want: 90 * time.Second},
+		{name: "several steps", advance: []time.Duration{time.Hour, 30 * time.Minute, 250 * time.Millisecond}, want: 90*time.Minute + 250*time.Millisecond},
+		{name: "days", advance: []time.Duration{36 * time.Hour}, want: 36 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			start := clock.Now()
+			checker := NewHealthChecker(HealthCheckerConfig{ServiceName: "test", Clock: clock})
+			for _, step := range tt.advance {
+				clock.Advance(step)
+			}
+
+			if got := checker.GetStartTime(); !got.Equal(start) {
+				t.Errorf("GetStartTime() = %v, want %v", got, start)
+			}
+			if got := checker.GetUptime(); got != tt.want {
+				t.Errorf("GetUptime() = %v, want %v", got, tt.want)
+			}
+
+			recorder := httptest.NewRecorder()
+			checker.HealthHandler(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+			var result CheckResult
+			if err := json.Unmarshal(recorder.Body.Bytes(), &result); err != nil {
+				t.Fatalf("decoding /health response: %v", err)
+			}
+			if result.Uptime != tt.want.String() {
+				t.Errorf("uptime = %q, want %q", result.Uptime, tt.want.String())
+			}
+			if want := clock.Now().Format(time.RFC3339); result.Timestamp != want {
+				t.Errorf("timestamp = %q, want %q", result.Timestamp, want)
+			}
+		})
+	}
+}
+
+func TestCheckDurationUsesClock(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		wantSlow bool
+	}{
+		{name: "fast", duration: 100 * time.Millisecond},
+		{name: "at threshold", duration: time.Second},
+		{name: "slow", duration: 3 * time.Second, wantSlow: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			logger := &recordingLogger{}
+			checker := NewHealthChecker(HealthCheckerConfig{Clock: clock, Logger: logger, SlowCheckThreshold: time.Second})
+			checker.AddHealthCheck("db", func() error {
+				clock.Advance(tt.duration)
+				return nil
+			})
+
+			checker.HealthHandler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			history := checker.History("health", "db")
+			if len(history) != 1 {
+				t.Fatalf("History() has %d records, want 1", len(history))
+			}
+			if history[0].Duration != tt.duration.String() {
+				t.Errorf("recorded duration = %q, want %q", history[0].Duration, tt.duration.String())
+			}
+			if slow := len(logger.warns) == 1 && logger.warns[0] == "health check slow"; slow != tt.wantSlow {
+				t.Errorf("slow warning logged = %v (warnings %q), want %v", slow, logger.warns, tt.wantSlow)
+			}
+		})
+	}
+}
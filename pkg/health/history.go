@@ -0,0 +1,169 @@
+/**
+ * @fileoverview Recent check history and failure diagnosis for the HealthChecker.
+ * Keeps a bounded record of each check's latest executions so a failing readiness
+ * probe can be explained from a single endpoint without re-running checks, together with
+ * the outbound call errors recorded while those checks were failing.
+ */
+
+package health
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+)
+
+// DefaultHistorySize is the number of executions retained per check
+const DefaultHistorySize = 20
+
+// CheckRecord is a single recorded check execution
+type CheckRecord struct {
+	Timestamp string `json:"timestamp"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	Duration  string `json:"duration"`
+}
+
+// CheckDiagnosis describes a check that is currently not passing
+type CheckDiagnosis struct {
+	Kind    string        `json:"kind"`
+	Name    string        `json:"name"`
+	Status  string        `json:"status"`
+	History []CheckRecord `json:"history"`
+}
+
+// Diagnosis aggregates every non-passing check with its recent history
+type Diagnosis struct {
+	Status    string           `json:"status"`
+	Timestamp string           `json:"timestamp"`
+	Checks    []CheckDiagnosis `json:"checks"`
+	// OutboundErrors are failed outbound calls since the earliest retained failure of a diagnosed check
+	OutboundErrors []httputil.OutboundError `json:"outbound_errors,omitempty"`
+}
+
+/**
+ * @description Appends a check execution to its bounded history.
+ */
+func (hc *HealthChecker) recordHistory(kind, name, status string, err error, duration time.Duration) {
+	record := CheckRecord{
+		Timestamp: hc.clock.Now().UTC().Format(time.RFC3339Nano),
+		Status:    status,
+		Duration:  duration.String(),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	key := kind + "/" + name
+
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	records := append(hc.history[key], record)
+	if len(records) > hc.historySize {
+		records = records[len(records)-hc.historySize:]
+	}
+	hc.history[key] = records
+}
+
+/**
+ * @description Returns a copy of the recorded history for a check, oldest first.
+ */
+func (hc *HealthChecker) History(kind, name string) []CheckRecord {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	records := hc.history[kind+"/"+name]
+	return append([]CheckRecord(nil), records...)
+}
+
+/**
+ * @description Returns a copy of every check's recorded history keyed by "kind/name", oldest first.
+ */
+func (hc *HealthChecker) AllHistory() map[string][]CheckRecord {
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	all := make(map[string][]CheckRecord, len(hc.history))
+	for key, records := range hc.history {
+		all[key] = append([]CheckRecord(nil), records...)
+	}
+	return all
+}
+
+/**
+ * @description Builds a diagnosis of every check whose latest result was not ok, with the
+ * outbound call errors recorded since those checks started failing.
+ * Uses recorded results only, so diagnosing never triggers additional dependency calls.
+ */
+func (hc *HealthChecker) Diagnose() Diagnosis {
+	diagnosis := Diagnosis{
+		Status:    StatusHealthy,
+		Timestamp: hc.clock.Now().UTC().Format(time.RFC3339),
+		Checks:    []CheckDiagnosis{},
+	}
+
+	hc.mu.Lock()
+	defer hc.mu.Unlock()
+
+	var failingSince time.Time
+	for key, status := range hc.lastStatus {
+		kind, name, _ := strings.Cut(key, "/")
+		if name == "aggregate" || status == "ok" {
+			continue
+		}
+
+		if status == "failed" {
+			diagnosis.Status = StatusUnhealthy
+		} else if diagnosis.Status == StatusHealthy {
+			diagnosis.Status = StatusDegraded
+		}
+
+		diagnosis.Checks = append(diagnosis.Checks, CheckDiagnosis{
+			Kind:    kind,
+			Name:    name,
+			Status:  status,
+			History: append([]CheckRecord(nil), hc.history[key]...),
+		})
+		if since := firstFailure(hc.history[key]); !since.IsZero() && (failingSince.IsZero() || since.Before(failingSince)) {
+			failingSince = since
+		}
+	}
+	if hc.outbound != nil && !failingSince.IsZero() {
+		diagnosis.OutboundErrors = hc.outbound.Errors(failingSince)
+	}
+
+	sort.Slice(diagnosis.Checks, func(i, j int) bool {
+		if diagnosis.Checks[i].Kind != diagnosis.Checks[j].Kind {
+			return diagnosis.Checks[i].Kind < diagnosis.Checks[j].Kind
+		}
+		return diagnosis.Checks[i].Name < diagnosis.Checks[j].Name
+	})
+
+	return diagnosis
+}
+
+// firstFailure returns the time of the earliest non-ok record, or zero if there is none
+func firstFailure(records []CheckRecord) time.Time {
+	for _, record := range records {
+		if record.Status == "ok" {
+			continue
+		}
+		if timestamp, err := time.Parse(time.RFC3339Nano, record.Timestamp); err == nil {
+			return timestamp
+		}
+	}
+	return time.Time{}
+}
+
+/**
+ * @description HTTP handler returning the current diagnosis as JSON.
+ * Intended for on-call investigation of readiness failures.
+ */
+func (hc *HealthChecker) DiagnoseHandler(w http.ResponseWriter, r *http.Request) {
+	jsoncase.Write(w, http.StatusOK, hc.Diagnose())
+}
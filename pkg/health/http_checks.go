@@ -0,0 +1,307 @@
+/**
+ * @fileoverview HTTP probing checks.
+ * HTTPCheck verifies an HTTP dependency with configurable method, headers, body, accepted
+ * status ranges, TLS, redirect policy, and response-body matching. HTTPLatencyCheck treats
+ * a slow upstream as a problem in its own right: a dependency that answers correctly but
+ * slowly backs up request handling more than one that fails fast.
+ */
+
+package health
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HTTPLatencyFailFactor is the multiple of the latency threshold after which a probe fails
+	HTTPLatencyFailFactor = 2
+	// httpMaxDrainBytes limits how much of a probed response body is read
+	httpMaxDrainBytes = 1 << 20
+)
+
+// HTTPCheckOption configures HTTPCheck
+type HTTPCheckOption func(*httpCheckConfig)
+
+// statusRange is an inclusive range of acceptable status codes
+type statusRange struct {
+	min, max int
+}
+
+// jsonExpectation requires the value at a JSON path to equal expected
+type jsonExpectation struct {
+	path     string
+	expected string
+}
+
+// httpCheckConfig holds the settings applied by HTTPCheckOptions
+type httpCheckConfig struct {
+	method       string
+	header       http.Header
+	body         []byte
+	statusRanges []statusRange
+	tlsConfig    *tls.Config
+	maxRedirects int
+	contains     []string
+	jsonPaths    []jsonExpectation
+}
+
+/**
+ * @description Sends method instead of GET.
+ */
+func WithHTTPMethod(method string) HTTPCheckOption {
+	return func(c *httpCheckConfig) {
+		c.method = method
+	}
+}
+
+/**
+ * @description Adds a request header, e.g. an Authorization token.
+ */
+func WithHTTPHeader(key, value string) HTTPCheckOption {
+	return func(c *httpCheckConfig) {
+		c.header.Add(key, value)
+	}
+}
+
+/**
+ * @description Sends body with the given Content-Type on every probe.
+ */
+func WithHTTPBody(contentType string, body []byte) HTTPCheckOption {
+	return func(c *httpCheckConfig) {
+		c.body = body
+		c.header.Set("Content-Type", contentType)
+	}
+}
+
+/**
+ * @description Accepts any status from min to max inclusive; may be given several times.
+ * Replaces the exact expected status code.
+ */
+func WithHTTPStatusRange(min, max int) HTTPCheckOption {
+	return func(c *httpCheckConfig) {
+		c.statusRanges = append(c.statusRanges, statusRange{min: min, max: max})
+	}
+}
+
+/**
+ * @description Uses config for HTTPS connections, e.g. a private CA or client certificate.
+ */
+func WithHTTPTLS(config *tls.Config) HTTPCheckOption {
+	return func(c *httpCheckConfig) {
+		c.tlsConfig = config
+	}
+}
+
+/**
+ * @description Follows at most n redirects; 0 evaluates the redirect response itself.
+ * Without this option the client's default of 10 applies.
+ */
+func WithHTTPMaxRedirects(n int) HTTPCheckOption {
+	return func(c *httpCheckConfig) {
+		c.maxRedirects = n
+	}
+}
+
+/**
+ * @description Requires the response body to contain substring.
+ */
+func WithHTTPBodyContains(substring string) HTTPCheckOption {
+	return func(c *httpCheckConfig) {
+		c.contains = append(c.contains, substring)
+	}
+}
+
+/**
+ * @description Requires the JSON response value at path to equal expected.
+ * Paths are dot-separated object keys and array indexes, e.g. "status" or "checks.0.name".
+ */
+func WithHTTPJSONPath(path, expected string) HTTPCheckOption {
+	return func(c *httpCheckConfig) {
+		c.jsonPaths = append(c.jsonPaths, jsonExpectation{path: path, expected: expected})
+	}
+}
+
+/**
+ * @description Creates a check that sends an HTTP request to verify service availability.
+ * Requires expectedStatusCode unless status ranges are given (0 accepts any 2xx), then applies
+ * any body matchers to the first megabyte of the response.
+ */
+func HTTPCheck(url string, timeout time.Duration, expectedStatusCode int, opts ...HTTPCheckOption) CheckFunc {
+	config := &httpCheckConfig{method: http.MethodGet, header: make(http.Header), maxRedirects: -1}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if len(config.statusRanges) == 0 {
+		if expectedStatusCode == 0 {
+			config.statusRanges = []statusRange{{min: 200, max: 299}}
+		} else {
+			config.statusRanges = []statusRange{{min: expectedStatusCode, max: expectedStatusCode}}
+		}
+	}
+
+	client := &http.Client{Timeout: timeout}
+	if config.tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = config.tlsConfig
+		client.Transport = transport
+	}
+	if config.maxRedirects >= 0 {
+		maxRedirects := config.maxRedirects
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) > maxRedirects {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		}
+	}
+
+	return func() error {
+		req, err := http.NewRequest(config.method, url, bytes.NewReader(config.body))
+		if err != nil {
+			return fmt.Errorf("failed to create HTTP request to %s: %w", url, err)
+		}
+		req.Header = config.header.Clone()
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("HTTP request failed to %s: %w", url, err)
+		}
+		defer resp.Body.Close()
+
+		if !config.statusAccepted(resp.StatusCode) {
+			return fmt.Errorf("unexpected status code from %s: got %d, expected %s",
+				url, resp.StatusCode, config.describeStatus())
+		}
+
+		if len(config.contains) == 0 && len(config.jsonPaths) == 0 {
+			return nil
+		}
+		body, err := io.ReadAll(io.LimitReader(resp.Body, httpMaxDrainBytes))
+		if err != nil {
+			return fmt.Errorf("failed to read response from %s: %w", url, err)
+		}
+		return config.matchBody(url, body)
+	}
+}
+
+/**
+ * @description Creates a check that GETs url and times the full response.
+ * Reports degraded when it takes longer than maxLatency, and fails when it takes longer than
+ * HTTPLatencyFailFactor times maxLatency or returns a non-2xx status.
+ */
+func HTTPLatencyCheck(url string, maxLatency time.Duration) CheckFunc {
+	client := &http.Client{Timeout: maxLatency * HTTPLatencyFailFactor}
+	return func() error {
+		start := time.Now()
+		resp, err := client.Get(url)
+		if err != nil {
+			return fmt.Errorf("HTTP request failed to %s: %w", url, err)
+		}
+		io.Copy(io.Discard, io.LimitReader(resp.Body, httpMaxDrainBytes))
+		resp.Body.Close()
+		latency := time.Since(start)
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("unexpected status code from %s: %d", url, resp.StatusCode)
+		}
+		if latency > maxLatency {
+			return Degraded(fmt.Errorf("%s responded in %v, above the %v threshold",
+				url, latency.Round(time.Millisecond), maxLatency))
+		}
+		return nil
+	}
+}
+
+// statusAccepted reports whether code falls in any accepted range
+func (c *httpCheckConfig) statusAccepted(code int) bool {
+	for _, r := range c.statusRanges {
+		if code >= r.min && code <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+// describeStatus formats the accepted ranges for error messages
+func (c *httpCheckConfig) describeStatus() string {
+	parts := make([]string, len(c.statusRanges))
+	for i, r := range c.statusRanges {
+		if r.min == r.max {
+			parts[i] = strconv.Itoa(r.min)
+		} else {
+			parts[i] = fmt.Sprintf("%d-%d", r.min, r.max)
+		}
+	}
+	return strings.Join(parts, " or ")
+}
+
+// matchBody applies the substring and JSON path matchers
+func (c *httpCheckConfig) matchBody(url string, body []byte) error {
+	for _, substring := range c.contains {
+		if !bytes.Contains(body, []byte(substring)) {
+			return fmt.Errorf("response from %s does not contain %q", url, substring)
+		}
+	}
+	if len(c.jsonPaths) == 0 {
+		return nil
+	}
+
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return fmt.Errorf("response from %s is not valid JSON: %w", url, err)
+	}
+	for _, expectation := range c.jsonPaths {
+		value, err := lookupJSONPath(document, expectation.path)
+		if err != nil {
+			return fmt.Errorf("response from %s: %w", url, err)
+		}
+		if value != expectation.expected {
+			return fmt.Errorf("response from %s has %s = %q, expected %q", url, expectation.path, value, expectation.expected)
+		}
+	}
+	return nil
+}
+
+// lookupJSONPath walks a decoded JSON document and renders the value at path as a string
+func lookupJSONPath(document any, path string) (string, error) {
+	current := document
+	for _, segment := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return "", fmt.Errorf("JSON path %s not found", path)
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return "", fmt.Errorf("JSON path %s not found", path)
+			}
+			current = node[index]
+		default:
+			return "", fmt.Errorf("JSON path %s not found", path)
+		}
+	}
+
+	switch value := current.(type) {
+	case string:
+		return value, nil
+	case nil:
+		return "null", nil
+	case map[string]any, []any:
+		return "", errors.New("JSON path " + path + " is not a scalar value")
+	default:
+		encoded, _ := json.Marshal(value)
+		return string(encoded), nil
+	}
+}
@@ -0,0 +1,235 @@
+/**
+ * @fileoverview Kafka broker liveness check using the Kafka wire protocol directly.
+ * Sends a Metadata (v4) request so connectivity is verified at the protocol level,
+ * not just TCP, and optionally confirms that required topics exist with leaders.
+ */
+
+package health
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	kafkaMetadataAPIKey     = 3
+	kafkaMetadataAPIVersion = 4
+	kafkaClientID           = "health-check"
+	kafkaMaxResponseBytes   = 16 << 20
+)
+
+/**
+ * @description Creates a check that verifies at least one Kafka broker answers a metadata request.
+ * When topics are given, they must exist and every partition must have a leader.
+ */
+func KafkaCheck(brokers []string, timeout time.Duration, topics ...string) CheckFunc {
+	return func() error {
+		if len(brokers) == 0 {
+			return errors.New("no kafka brokers configured")
+		}
+
+		var failures []string
+		for _, broker := range brokers {
+			metadata, err := fetchKafkaMetadata(broker, timeout, topics)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", broker, err))
+				continue
+			}
+			return metadata.verifyTopics(topics)
+		}
+
+		return fmt.Errorf("no kafka broker reachable: %s", strings.Join(failures, "; "))
+	}
+}
+
+// kafkaTopicMetadata is the subset of topic metadata needed for the check
+type kafkaTopicMetadata struct {
+	errorCode       int16
+	leaderlessCount int
+}
+
+// kafkaMetadata is the parsed metadata response
+type kafkaMetadata struct {
+	brokerCount int
+	topics      map[string]kafkaTopicMetadata
+}
+
+// verifyTopics reports missing topics and partitions without leaders
+func (m kafkaMetadata) verifyTopics(topics []string) error {
+	if m.brokerCount == 0 {
+		return errors.New("kafka metadata lists no brokers")
+	}
+
+	for _, topic := range topics {
+		metadata, ok := m.topics[topic]
+		switch {
+		case !ok:
+			return fmt.Errorf("kafka topic %s not present in metadata", topic)
+		case metadata.errorCode != 0:
+			return fmt.Errorf("kafka topic %s metadata error code %d", topic, metadata.errorCode)
+		case metadata.leaderlessCount > 0:
+			return fmt.Errorf("kafka topic %s has %d partitions without a leader", topic, metadata.leaderlessCount)
+		}
+	}
+	return nil
+}
+
+// fetchKafkaMetadata sends a Metadata request to a single broker and parses the response
+func fetchKafkaMetadata(broker string, timeout time.Duration, topics []string) (kafkaMetadata, error) {
+	conn, err := net.DialTimeout("tcp", broker, timeout)
+	if err != nil {
+		return kafkaMetadata{}, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	const correlationID = 1
+	if _, err := conn.Write(encodeKafkaMetadataRequest(correlationID, topics)); err != nil {
+		return kafkaMetadata{}, fmt.Errorf("failed to send metadata request: %w", err)
+	}
+
+	var size int32
+	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+		return kafkaMetadata{}, fmt.Errorf("failed to read metadata response: %w", err)
+	}
+	if size < 4 || size > kafkaMaxResponseBytes {
+		return kafkaMetadata{}, fmt.Errorf("invalid metadata response size %d", size)
+	}
+
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(conn, payload); err != nil {
+		return kafkaMetadata{}, fmt.Errorf("failed to read metadata response: %w", err)
+	}
+
+	reader := &kafkaReader{buf: bytes.NewReader(payload)}
+	if reader.int32() != correlationID {
+		return kafkaMetadata{}, errors.New("metadata response correlation id mismatch")
+	}
+	metadata := parseKafkaMetadata(reader)
+	if reader.err != nil {
+		return kafkaMetadata{}, fmt.Errorf("malformed metadata response: %w", reader.err)
+	}
+	return metadata, nil
+}
+
+// encodeKafkaMetadataRequest builds a size-prefixed Metadata v4 request
+func encodeKafkaMetadataRequest(correlationID int32, topics []string) []byte {
+	var body bytes.Buffer
+	binary.Write(&body, binary.BigEndian, int16(kafkaMetadataAPIKey))
+	binary.Write(&body, binary.BigEndian, int16(kafkaMetadataAPIVersion))
+	binary.Write(&body, binary.BigEndian, correlationID)
+	writeKafkaString(&body, kafkaClientID)
+
+	// An empty (non-null) topic array requests broker metadata only
+	binary.Write(&body, binary.BigEndian, int32(len(topics)))
+	for _, topic := range topics {
+		writeKafkaString(&body, topic)
+	}
+	body.WriteByte(0) // allow_auto_topic_creation = false
+
+	var request bytes.Buffer
+	binary.Write(&request, binary.BigEndian, int32(body.Len()))
+	request.Write(body.Bytes())
+	return request.Bytes()
+}
+
+// parseKafkaMetadata decodes a Metadata v4 response body after the correlation id
+func parseKafkaMetadata(r *kafkaReader) kafkaMetadata {
+	metadata := kafkaMetadata{topics: make(map[string]kafkaTopicMetadata)}
+
+	r.int32() // throttle_time_ms
+	metadata.brokerCount = int(r.int32())
+	for i := 0; i < metadata.brokerCount && r.err == nil; i++ {
+		r.int32()          // node_id
+		r.string()         // host
+		r.int32()          // port
+		r.nullableString() // rack
+	}
+	r.nullableString() // cluster_id
+	r.int32()          // controller_id
+
+	topicCount := int(r.int32())
+	for i := 0; i < topicCount && r.err == nil; i++ {
+		topic := kafkaTopicMetadata{errorCode: r.int16()}
+		name := r.string()
+		r.int8() // is_internal
+
+		partitionCount := int(r.int32())
+		for j := 0; j < partitionCount && r.err == nil; j++ {
+			r.int16() // partition error_code
+			r.int32() // partition_index
+			if r.int32() < 0 {
+				topic.leaderlessCount++
+			}
+			r.skipInt32Array() // replica_nodes
+			r.skipInt32Array() // isr_nodes
+		}
+		metadata.topics[name] = topic
+	}
+	return metadata
+}
+
+// writeKafkaString writes an int16 length-prefixed string
+func writeKafkaString(buf *bytes.Buffer, value string) {
+	binary.Write(buf, binary.BigEndian, int16(len(value)))
+	buf.WriteString(value)
+}
+
+// kafkaReader decodes big-endian Kafka primitives, remembering the first error
+type kafkaReader struct {
+	buf *bytes.Reader
+	err error
+}
+
+func (r *kafkaReader) read(value any) {
+	if r.err == nil {
+		r.err = binary.Read(r.buf, binary.BigEndian, value)
+	}
+}
+
+func (r *kafkaReader) int8() int8 {
+	var value int8
+	r.read(&value)
+	return value
+}
+
+func (r *kafkaReader) int16() int16 {
+	var value int16
+	r.read(&value)
+	return value
+}
+
+func (r *kafkaReader) int32() int32 {
+	var value int32
+	r.read(&value)
+	return value
+}
+
+func (r *kafkaReader) nullableString() string {
+	length := r.int16()
+	if length < 0 || r.err != nil {
+		return ""
+	}
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r.buf, value); err != nil && r.err == nil {
+		r.err = err
+	}
+	return string(value)
+}
+
+func (r *kafkaReader) string() string {
+	return r.nullableString()
+}
+
+func (r *kafkaReader) skipInt32Array() {
+	count := r.int32()
+	for i := int32(0); i < count && r.err == nil; i++ {
+		r.int32()
+	}
+}
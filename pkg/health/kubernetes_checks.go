@@ -0,0 +1,86 @@
+/**
+ * @fileoverview Kubernetes API server reachability check.
+ * Uses the pod's in-cluster service account to call the API server's /readyz endpoint,
+ * so controllers surface lost API-server connectivity in their own health.
+ */
+
+package health
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// KubernetesServiceAccountDir holds the in-cluster service account token and CA bundle
+const KubernetesServiceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+/**
+ * @description Creates a check that calls /readyz on the API server with the in-cluster service account.
+ * When critical is false, failures report degraded so API-server outages do not remove the pod from rotation.
+ */
+func KubernetesAPICheck(timeout time.Duration, critical bool) CheckFunc {
+	check := kubernetesAPICheck(timeout)
+	if critical {
+		return check
+	}
+	return func() error {
+		return Degraded(check())
+	}
+}
+
+// kubernetesAPICheck performs the /readyz request, reading the token each time since projected tokens rotate
+func kubernetesAPICheck(timeout time.Duration) CheckFunc {
+	return func() error {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return errors.New("not running in a Kubernetes cluster (KUBERNETES_SERVICE_HOST/PORT unset)")
+		}
+
+		token, err := os.ReadFile(filepath.Join(KubernetesServiceAccountDir, "token"))
+		if err != nil {
+			return fmt.Errorf("failed to read service account token: %w", err)
+		}
+		caBundle, err := os.ReadFile(filepath.Join(KubernetesServiceAccountDir, "ca.crt"))
+		if err != nil {
+			return fmt.Errorf("failed to read service account CA: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caBundle) {
+			return errors.New("service account CA bundle contains no certificates")
+		}
+
+		client := &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12},
+			},
+		}
+		defer client.CloseIdleConnections()
+
+		req, err := http.NewRequest(http.MethodGet, "https://"+net.JoinHostPort(host, port)+"/readyz", nil)
+		if err != nil {
+			return fmt.Errorf("failed to create readyz request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("kubernetes API server unreachable: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return fmt.Errorf("kubernetes API server /readyz returned HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,284 @@
+/**
+ * @fileoverview LDAP / Active Directory bind check using the LDAPv3 wire protocol directly.
+ * Performs an anonymous or simple bind so the directory is verified to accept
+ * authentication, not just TCP connections, before auth-dependent services report ready.
+ */
+
+package health
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"time"
+)
+
+const (
+	ldapDefaultPort     = "389"
+	ldapsDefaultPort    = "636"
+	ldapVersion         = 3
+	ldapMaxMessageBytes = 1 << 20
+
+	// BER tags used by bind and unbind
+	berInteger       = 0x02
+	berOctetString   = 0x04
+	berEnumerated    = 0x0a
+	berSequence      = 0x30
+	ldapBindRequest  = 0x60
+	ldapBindResponse = 0x61
+	ldapUnbind       = 0x42
+	ldapSimpleAuth   = 0x80
+)
+
+/**
+ * @description Creates a check that binds to the directory at ldapURL (ldap:// or ldaps://).
+ * An empty bindDN performs an anonymous bind; otherwise a simple bind with password.
+ * A DN without a password is rejected, since servers treat it as an unauthenticated bind
+ * that always succeeds.
+ */
+func LDAPCheck(ldapURL, bindDN, password string, timeout time.Duration) CheckFunc {
+	return func() error {
+		if bindDN != "" && password == "" {
+			return errors.New("ldap bind DN configured without a password")
+		}
+
+		conn, err := dialLDAP(ldapURL, timeout)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(timeout))
+
+		const messageID = 1
+		if _, err := conn.Write(encodeLDAPBind(messageID, bindDN, password)); err != nil {
+			return fmt.Errorf("failed to send ldap bind request: %w", err)
+		}
+
+		resultCode, diagnostic, err := readLDAPBindResponse(bufio.NewReader(conn), messageID)
+		if err != nil {
+			return err
+		}
+
+		// Unbind politely; the connection is closed either way
+		conn.Write(berTLV(berSequence, append(berTLV(berInteger, []byte{messageID + 1}), ldapUnbind, 0x00)))
+
+		if resultCode != 0 {
+			if diagnostic != "" {
+				return fmt.Errorf("ldap bind failed: %s (result code %d): %s", ldapResultName(resultCode), resultCode, diagnostic)
+			}
+			return fmt.Errorf("ldap bind failed: %s (result code %d)", ldapResultName(resultCode), resultCode)
+		}
+		return nil
+	}
+}
+
+// dialLDAP connects to the host in an ldap:// or ldaps:// URL
+func dialLDAP(ldapURL string, timeout time.Duration) (net.Conn, error) {
+	parsed, err := url.Parse(ldapURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ldap URL: %w", err)
+	}
+
+	port := parsed.Port()
+	switch parsed.Scheme {
+	case "ldap":
+		if port == "" {
+			port = ldapDefaultPort
+		}
+	case "ldaps":
+		if port == "" {
+			port = ldapsDefaultPort
+		}
+	default:
+		return nil, fmt.Errorf("unsupported ldap URL scheme %q (expected ldap or ldaps)", parsed.Scheme)
+	}
+	address := net.JoinHostPort(parsed.Hostname(), port)
+
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	if parsed.Scheme == "ldaps" {
+		conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: parsed.Hostname(), MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ldap server %s unreachable: %w", address, err)
+	}
+	return conn, nil
+}
+
+// encodeLDAPBind builds an LDAPMessage containing a simple BindRequest
+func encodeLDAPBind(messageID byte, bindDN, password string) []byte {
+	bind := berTLV(berInteger, []byte{ldapVersion})
+	bind = append(bind, berTLV(berOctetString, []byte(bindDN))...)
+	bind = append(bind, berTLV(ldapSimpleAuth, []byte(password))...)
+
+	message := berTLV(berInteger, []byte{messageID})
+	message = append(message, berTLV(ldapBindRequest, bind)...)
+	return berTLV(berSequence, message)
+}
+
+// readLDAPBindResponse reads one LDAPMessage and returns the BindResponse result code and diagnostic message
+func readLDAPBindResponse(reader *bufio.Reader, messageID byte) (int, string, error) {
+	tag, message, err := readBER(reader)
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to read ldap bind response: %w", err)
+	}
+	if tag != berSequence {
+		return 0, "", fmt.Errorf("unexpected ldap message tag 0x%02x", tag)
+	}
+
+	fields, err := splitBER(message)
+	if err != nil || len(fields) < 2 {
+		return 0, "", errors.New("malformed ldap message")
+	}
+	if fields[0].tag != berInteger || len(fields[0].value) != 1 || fields[0].value[0] != messageID {
+		return 0, "", errors.New("ldap response does not match bind request")
+	}
+	if fields[1].tag != ldapBindResponse {
+		return 0, "", fmt.Errorf("unexpected ldap operation 0x%02x in bind response", fields[1].tag)
+	}
+
+	result, err := splitBER(fields[1].value)
+	if err != nil || len(result) < 3 || result[0].tag != berEnumerated {
+		return 0, "", errors.New("malformed ldap bind response")
+	}
+	code := 0
+	for _, b := range result[0].value {
+		code = code<<8 | int(b)
+	}
+	return code, string(result[2].value), nil
+}
+
+// berElement is one decoded tag-length-value element
+type berElement struct {
+	tag   byte
+	value []byte
+}
+
+// berTLV encodes a single-byte tag, definite length, and value
+func berTLV(tag byte, value []byte) []byte {
+	encoded := []byte{tag}
+	switch length := len(value); {
+	case length < 0x80:
+		encoded = append(encoded, byte(length))
+	case length <= 0xff:
+		encoded = append(encoded, 0x81, byte(length))
+	default:
+		encoded = append(encoded, 0x82, byte(length>>8), byte(length))
+	}
+	return append(encoded, value...)
+}
+
+// readBER reads one element from reader
+func readBER(reader *bufio.Reader) (byte, []byte, error) {
+	tag, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, err := readBERLength(reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	value := make([]byte, length)
+	if _, err := io.ReadFull(reader, value); err != nil {
+		return 0, nil, err
+	}
+	return tag, value, nil
+}
+
+// readBERLength decodes a definite short- or long-form length
+func readBERLength(reader io.ByteReader) (int, error) {
+	first, err := reader.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	if first < 0x80 {
+		return int(first), nil
+	}
+	octets := int(first & 0x7f)
+	if octets == 0 || octets > 4 {
+		return 0, fmt.Errorf("unsupported BER length encoding 0x%02x", first)
+	}
+	length := 0
+	for i := 0; i < octets; i++ {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length = length<<8 | int(b)
+	}
+	if length > ldapMaxMessageBytes {
+		return 0, fmt.Errorf("ldap message of %d bytes exceeds limit", length)
+	}
+	return length, nil
+}
+
+// splitBER decodes the consecutive elements in a constructed value
+func splitBER(data []byte) ([]berElement, error) {
+	var elements []berElement
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, errors.New("truncated BER element")
+		}
+		reader := &byteSliceReader{data: data[1:]}
+		length, err := readBERLength(reader)
+		if err != nil {
+			return nil, err
+		}
+		start := 1 + reader.offset
+		if start+length > len(data) {
+			return nil, errors.New("truncated BER element")
+		}
+		elements = append(elements, berElement{tag: data[0], value: data[start : start+length]})
+		data = data[start+length:]
+	}
+	return elements, nil
+}
+
+// byteSliceReader is an io.ByteReader that tracks how many bytes were consumed
+type byteSliceReader struct {
+	data   []byte
+	offset int
+}
+
+func (r *byteSliceReader) ReadByte() (byte, error) {
+	if r.offset >= len(r.data) {
+		return 0, io.ErrUnexpectedEOF
+	}
+	b := r.data[r.offset]
+	r.offset++
+	return b, nil
+}
+
+// ldapResultName names common LDAP result codes
+func ldapResultName(code int) string {
+	switch code {
+	case 1:
+		return "operationsError"
+	case 2:
+		return "protocolError"
+	case 7:
+		return "authMethodNotSupported"
+	case 8:
+		return "strongerAuthRequired"
+	case 48:
+		return "inappropriateAuthentication"
+	case 49:
+		return "invalidCredentials"
+	case 50:
+		return "insufficientAccessRights"
+	case 51:
+		return "busy"
+	case 52:
+		return "unavailable"
+	case 53:
+		return "unwillingToPerform"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,43 @@
+/**
+ * @fileoverview Memcached liveness check using the text protocol directly.
+ * Sends "version" so caching tiers can gate readiness without a client library.
+ */
+
+package health
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+/**
+ * @description Creates a check that sends "version" to the memcached server at address (host:port)
+ * and requires a VERSION reply within timeout.
+ */
+func MemcachedCheck(address string, timeout time.Duration) CheckFunc {
+	return func() error {
+		conn, err := net.DialTimeout("tcp", address, timeout)
+		if err != nil {
+			return fmt.Errorf("memcached %s unreachable: %w", address, err)
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(timeout))
+
+		if _, err := conn.Write([]byte("version\r\n")); err != nil {
+			return fmt.Errorf("failed to send memcached version command: %w", err)
+		}
+
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read memcached version reply: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "VERSION ") {
+			return fmt.Errorf("unexpected memcached reply %q", line)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,325 @@
+/**
+ * @fileoverview MongoDB health checks.
+ * MongoCheck wraps an application's existing client through a small interface; MongoURICheck
+ * needs no driver and sends the "hello" command over the wire protocol to each seed host,
+ * optionally requiring a reachable replica-set primary.
+ */
+
+package health
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	mongoDefaultPort     = "27017"
+	mongoOpMsg           = 2013
+	mongoMaxMessageBytes = 48 << 20
+)
+
+// MongoClient is implemented by a thin adapter over the application's driver client,
+// e.g. calling client.Ping(ctx, readpref.Primary()) when primary is true
+type MongoClient interface {
+	Ping(ctx context.Context, primary bool) error
+}
+
+/**
+ * @description Creates a check that pings MongoDB through the application's client.
+ * The timeout bounds server selection; requirePrimary only accepts a writable primary.
+ */
+func MongoCheck(client MongoClient, timeout time.Duration, requirePrimary bool) CheckFunc {
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if err := client.Ping(ctx, requirePrimary); err != nil {
+			if requirePrimary {
+				return fmt.Errorf("mongodb primary not available: %w", err)
+			}
+			return fmt.Errorf("mongodb ping failed: %w", err)
+		}
+		return nil
+	}
+}
+
+/**
+ * @description Creates a driverless check from a mongodb:// URI that sends "hello" to each seed host.
+ * Passes when any host answers, or when requirePrimary is set, when a host reports itself writable primary.
+ * Honors tls=true/ssl=true in the URI; SRV (mongodb+srv) URIs are resolved via DNS.
+ */
+func MongoURICheck(uri string, timeout time.Duration, requirePrimary bool) CheckFunc {
+	return func() error {
+		hosts, useTLS, err := parseMongoURI(uri)
+		if err != nil {
+			return err
+		}
+
+		var failures []string
+		answered, setName := false, ""
+		for _, host := range hosts {
+			hello, err := mongoHello(host, useTLS, timeout)
+			if err != nil {
+				failures = append(failures, fmt.Sprintf("%s: %v", host, err))
+				continue
+			}
+			answered, setName = true, hello.setName
+			if !requirePrimary || hello.writablePrimary {
+				return nil
+			}
+		}
+
+		if answered {
+			return fmt.Errorf("mongodb replica set %q reachable but no writable primary found among seed hosts", setName)
+		}
+		return fmt.Errorf("no mongodb host reachable: %s", strings.Join(failures, "; "))
+	}
+}
+
+// parseMongoURI extracts seed hosts and the TLS flag from a connection string
+func parseMongoURI(uri string) ([]string, bool, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid mongodb uri: %w", err)
+	}
+
+	query := parsed.Query()
+	useTLS := query.Get("tls") == "true" || query.Get("ssl") == "true"
+
+	switch parsed.Scheme {
+	case "mongodb":
+	case "mongodb+srv":
+		_, records, err := net.LookupSRV("mongodb", "tcp", parsed.Hostname())
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to resolve mongodb srv record: %w", err)
+		}
+		hosts := make([]string, 0, len(records))
+		for _, record := range records {
+			hosts = append(hosts, net.JoinHostPort(strings.TrimSuffix(record.Target, "."), fmt.Sprint(record.Port)))
+		}
+		// SRV connection strings default to TLS unless explicitly disabled
+		return hosts, query.Get("tls") != "false" && query.Get("ssl") != "false", nil
+	default:
+		return nil, false, fmt.Errorf("unsupported mongodb uri scheme %q", parsed.Scheme)
+	}
+
+	var hosts []string
+	for _, host := range strings.Split(parsed.Host, ",") {
+		if host == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, mongoDefaultPort)
+		}
+		hosts = append(hosts, host)
+	}
+	if len(hosts) == 0 {
+		return nil, false, errors.New("mongodb uri contains no hosts")
+	}
+	return hosts, useTLS, nil
+}
+
+// mongoHelloResult is the subset of the hello reply used by the check
+type mongoHelloResult struct {
+	writablePrimary bool
+	setName         string
+}
+
+// mongoHello sends {hello: 1, $db: "admin"} as an OP_MSG and parses the reply
+func mongoHello(host string, useTLS bool, timeout time.Duration) (mongoHelloResult, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	var conn net.Conn
+	var err error
+	if useTLS {
+		hostname, _, _ := net.SplitHostPort(host)
+		conn, err = tls.DialWithDialer(dialer, "tcp", host, &tls.Config{ServerName: hostname, MinVersion: tls.VersionTLS12})
+	} else {
+		conn, err = dialer.Dial("tcp", host)
+	}
+	if err != nil {
+		return mongoHelloResult{}, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	if _, err := conn.Write(encodeMongoHello(1)); err != nil {
+		return mongoHelloResult{}, fmt.Errorf("failed to send hello: %w", err)
+	}
+
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return mongoHelloResult{}, fmt.Errorf("failed to read hello reply: %w", err)
+	}
+	length := int(binary.LittleEndian.Uint32(header[0:4]))
+	if length < 21 || length > mongoMaxMessageBytes || binary.LittleEndian.Uint32(header[12:16]) != mongoOpMsg {
+		return mongoHelloResult{}, errors.New("unexpected hello reply framing")
+	}
+
+	body := make([]byte, length-16)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return mongoHelloResult{}, fmt.Errorf("failed to read hello reply: %w", err)
+	}
+	// body: flagBits (4 bytes), section kind 0 (1 byte), BSON document
+	if body[4] != 0 {
+		return mongoHelloResult{}, errors.New("unexpected hello reply section")
+	}
+
+	fields, err := decodeBSONTopLevel(body[5:])
+	if err != nil {
+		return mongoHelloResult{}, fmt.Errorf("malformed hello reply: %w", err)
+	}
+	if ok, _ := fields["ok"].(float64); ok != 1 {
+		message, _ := fields["errmsg"].(string)
+		return mongoHelloResult{}, fmt.Errorf("hello command failed: %s", message)
+	}
+
+	result := mongoHelloResult{}
+	result.writablePrimary, _ = fields["isWritablePrimary"].(bool)
+	result.setName, _ = fields["setName"].(string)
+	return result, nil
+}
+
+// encodeMongoHello builds an OP_MSG containing {hello: 1, $db: "admin"}
+func encodeMongoHello(requestID int32) []byte {
+	var document bytes.Buffer
+	document.Write([]byte{0, 0, 0, 0})
+	document.WriteByte(0x10) // int32
+	document.WriteString("hello\x00")
+	binary.Write(&document, binary.LittleEndian, int32(1))
+	document.WriteByte(0x02) // string
+	document.WriteString("$db\x00")
+	binary.Write(&document, binary.LittleEndian, int32(len("admin")+1))
+	document.WriteString("admin\x00")
+	document.WriteByte(0)
+	documentBytes := document.Bytes()
+	binary.LittleEndian.PutUint32(documentBytes, uint32(len(documentBytes)))
+
+	var message bytes.Buffer
+	binary.Write(&message, binary.LittleEndian, int32(16+4+1+len(documentBytes)))
+	binary.Write(&message, binary.LittleEndian, requestID)
+	binary.Write(&message, binary.LittleEndian, int32(0))
+	binary.Write(&message, binary.LittleEndian, int32(mongoOpMsg))
+	binary.Write(&message, binary.LittleEndian, uint32(0)) // flagBits
+	message.WriteByte(0)                                   // section kind 0
+	message.Write(documentBytes)
+	return message.Bytes()
+}
+
+// decodeBSONTopLevel returns the scalar top-level fields of a BSON document;
+// numbers are returned as float64, and nested values are skipped
+func decodeBSONTopLevel(document []byte) (map[string]any, error) {
+	if len(document) < 5 {
+		return nil, errors.New("document too short")
+	}
+	size := int(binary.LittleEndian.Uint32(document))
+	if size > len(document) || size < 5 {
+		return nil, errors.New("invalid document size")
+	}
+
+	fields := make(map[string]any)
+	data := document[4 : size-1]
+	for len(data) > 0 {
+		elementType := data[0]
+		nameEnd := bytes.IndexByte(data[1:], 0)
+		if nameEnd < 0 {
+			return nil, errors.New("unterminated field name")
+		}
+		name := string(data[1 : 1+nameEnd])
+		data = data[2+nameEnd:]
+
+		value, consumed, err := decodeBSONValue(elementType, data)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", name, err)
+		}
+		if value != nil {
+			fields[name] = value
+		}
+		data = data[consumed:]
+	}
+	return fields, nil
+}
+
+// decodeBSONValue decodes or skips one value, returning the bytes consumed
+func decodeBSONValue(elementType byte, data []byte) (any, int, error) {
+	need := func(n int) error {
+		if n < 0 || n > len(data) {
+			return errors.New("truncated value")
+		}
+		return nil
+	}
+	lengthPrefixed := func(extra int) (int, error) {
+		if err := need(4); err != nil {
+			return 0, err
+		}
+		n := int(int32(binary.LittleEndian.Uint32(data))) + extra
+		return n, need(n)
+	}
+
+	switch elementType {
+	case 0x01: // double
+		if err := need(8); err != nil {
+			return nil, 0, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(data)), 8, nil
+	case 0x02, 0x0D, 0x0E: // string, javascript, symbol
+		n, err := lengthPrefixed(4)
+		if err != nil || n < 5 {
+			return nil, 0, errors.New("invalid string")
+		}
+		return string(data[4 : n-1]), n, nil
+	case 0x03, 0x04, 0x0F: // document, array, code with scope
+		n, err := lengthPrefixed(0)
+		return nil, n, err
+	case 0x05: // binary
+		n, err := lengthPrefixed(5)
+		return nil, n, err
+	case 0x07: // object id
+		return nil, 12, need(12)
+	case 0x08: // boolean
+		if err := need(1); err != nil {
+			return nil, 0, err
+		}
+		return data[0] == 1, 1, nil
+	case 0x09, 0x11: // datetime, timestamp
+		return nil, 8, need(8)
+	case 0x0A, 0x06, 0xFF, 0x7F: // null, undefined, min key, max key
+		return nil, 0, nil
+	case 0x10: // int32
+		if err := need(4); err != nil {
+			return nil, 0, err
+		}
+		return float64(int32(binary.LittleEndian.Uint32(data))), 4, nil
+	case 0x12: // int64
+		if err := need(8); err != nil {
+			return nil, 0, err
+		}
+		return float64(int64(binary.LittleEndian.Uint64(data))), 8, nil
+	case 0x13: // decimal128
+		return nil, 16, need(16)
+	case 0x0B: // regex: two cstrings
+		first := bytes.IndexByte(data, 0)
+		if first < 0 {
+			return nil, 0, errors.New("invalid regex")
+		}
+		second := bytes.IndexByte(data[first+1:], 0)
+		if second < 0 {
+			return nil, 0, errors.New("invalid regex")
+		}
+		return nil, first + second + 2, nil
+	case 0x0C: // db pointer
+		n, err := lengthPrefixed(4 + 12)
+		return nil, n, err
+	default:
+		return nil, 0, fmt.Errorf("unsupported bson type 0x%02x", elementType)
+	}
+}
@@ -0,0 +1,102 @@
+/**
+ * @fileoverview Clock drift check against an NTP server.
+ * Sends a single SNTPv4 client request and computes the local clock offset from the
+ * server's receive and transmit timestamps, so drift that would break token signing
+ * and validation is caught before authentication silently fails.
+ */
+
+package health
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	// NTPTimeout bounds a single NTP exchange
+	NTPTimeout = 5 * time.Second
+	// ntpPacketSize is the length of an NTP header without extensions
+	ntpPacketSize = 48
+	// ntpEpochOffset is the number of seconds between 1900-01-01 and 1970-01-01
+	ntpEpochOffset = 2208988800
+)
+
+/**
+ * @description Creates a check that fails when the local clock differs from ntpServer by more than maxDrift.
+ * ntpServer may omit the port, in which case 123 is used.
+ */
+func ClockDriftCheck(ntpServer string, maxDrift time.Duration) CheckFunc {
+	address := ntpServer
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		address = net.JoinHostPort(address, "123")
+	}
+
+	return func() error {
+		offset, err := queryNTPOffset(address, NTPTimeout)
+		if err != nil {
+			return fmt.Errorf("NTP query to %s failed: %w", ntpServer, err)
+		}
+		if offset.Abs() > maxDrift {
+			return fmt.Errorf("clock drift %v against %s exceeds %v", offset.Round(time.Millisecond), ntpServer, maxDrift)
+		}
+		return nil
+	}
+}
+
+// queryNTPOffset performs one SNTP exchange and returns the server time minus the local time
+func queryNTPOffset(address string, timeout time.Duration) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", address, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	request := make([]byte, ntpPacketSize)
+	request[0] = 0x23 // LI 0, version 4, mode 3 (client)
+	originate := time.Now()
+	binary.BigEndian.PutUint64(request[40:], toNTPTime(originate))
+	if _, err := conn.Write(request); err != nil {
+		return 0, err
+	}
+
+	response := make([]byte, ntpPacketSize)
+	n, err := conn.Read(response)
+	destination := time.Now()
+	if err != nil {
+		return 0, err
+	}
+	if n < ntpPacketSize {
+		return 0, fmt.Errorf("short NTP response of %d bytes", n)
+	}
+
+	if mode := response[0] & 0x07; mode != 4 {
+		return 0, fmt.Errorf("unexpected NTP mode %d", mode)
+	}
+	if stratum := response[1]; stratum == 0 {
+		return 0, fmt.Errorf("server sent kiss-of-death %q", response[12:16])
+	}
+	if binary.BigEndian.Uint64(response[24:]) != binary.BigEndian.Uint64(request[40:]) {
+		return 0, fmt.Errorf("NTP response does not match request")
+	}
+
+	received := fromNTPTime(binary.BigEndian.Uint64(response[32:]))
+	transmitted := fromNTPTime(binary.BigEndian.Uint64(response[40:]))
+	return (received.Sub(originate) + transmitted.Sub(destination)) / 2, nil
+}
+
+// toNTPTime converts t to a 64-bit NTP timestamp (32.32 fixed point seconds since 1900)
+func toNTPTime(t time.Time) uint64 {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return seconds<<32 | fraction
+}
+
+// fromNTPTime converts a 64-bit NTP timestamp to a time.Time
+func fromNTPTime(ntp uint64) time.Time {
+	seconds := int64(ntp>>32) - ntpEpochOffset
+	nanos := (ntp & 0xffffffff) * uint64(time.Second) >> 32
+	return time.Unix(seconds, int64(nanos))
+}
@@ -0,0 +1,166 @@
+/**
+ * @fileoverview S3-compatible object storage reachability check.
+ * Issues a HEAD request against a bucket signed with AWS Signature Version 4, so storage
+ * dependencies on AWS S3, MinIO, R2, or similar can gate readiness without an SDK.
+ */
+
+package health
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty request body
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// ObjectStoreConfig describes the bucket to probe and how to authenticate
+type ObjectStoreConfig struct {
+	// Endpoint is the service URL, e.g. https://s3.us-east-1.amazonaws.com or http://minio:9000
+	Endpoint string
+	Bucket   string
+	// Region is used for request signing; defaults to us-east-1
+	Region string
+	// AccessKeyID and SecretAccessKey sign the request; both empty sends an anonymous request
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// VirtualHosted addresses the bucket as a subdomain instead of a path segment
+	VirtualHosted bool
+	Timeout       time.Duration
+}
+
+/**
+ * @description Creates a check that HEADs the configured bucket.
+ * Reports missing buckets, access denial, and region mismatches as distinct failures.
+ */
+func ObjectStoreCheck(config ObjectStoreConfig) CheckFunc {
+	client := &http.Client{
+		Timeout: config.Timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	return func() error {
+		target, err := objectStoreBucketURL(config)
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequest(http.MethodHead, target.String(), nil)
+		if err != nil {
+			return fmt.Errorf("failed to create object store request: %w", err)
+		}
+		if config.AccessKeyID != "" || config.SecretAccessKey != "" {
+			signSigV4(req, config, time.Now().UTC())
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("object store request to %s failed: %w", target.Host, err)
+		}
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return nil
+		case http.StatusNotFound:
+			return fmt.Errorf("bucket %s does not exist", config.Bucket)
+		case http.StatusForbidden:
+			return fmt.Errorf("access denied to bucket %s", config.Bucket)
+		case http.StatusMovedPermanently, http.StatusBadRequest:
+			if region := resp.Header.Get("X-Amz-Bucket-Region"); region != "" && region != config.Region {
+				return fmt.Errorf("bucket %s is in region %s, not %s", config.Bucket, region, config.Region)
+			}
+		}
+		return fmt.Errorf("unexpected status %d from bucket %s", resp.StatusCode, config.Bucket)
+	}
+}
+
+// objectStoreBucketURL builds the bucket URL in path-style or virtual-hosted form
+func objectStoreBucketURL(config ObjectStoreConfig) (*url.URL, error) {
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid object store endpoint %q", config.Endpoint)
+	}
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("object store bucket is required")
+	}
+
+	if config.VirtualHosted {
+		endpoint.Host = config.Bucket + "." + endpoint.Host
+		endpoint.Path = "/"
+	} else {
+		endpoint.Path = "/" + url.PathEscape(config.Bucket)
+	}
+	return endpoint, nil
+}
+
+// signSigV4 adds AWS Signature Version 4 headers for an empty-body request
+func signSigV4(req *http.Request, config ObjectStoreConfig, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+	if config.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", config.SessionToken)
+	}
+
+	signedHeaders := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + emptyPayloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	if config.SessionToken != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+		canonicalHeaders += "x-amz-security-token:" + config.SessionToken + "\n"
+	}
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		canonicalHeaders,
+		strings.Join(signedHeaders, ";"),
+		emptyPayloadHash,
+	}, "\n")
+
+	scope := shortDate + "/" + config.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := hmacSHA256([]byte("AWS4"+config.SecretAccessKey), shortDate)
+	signingKey = hmacSHA256(signingKey, config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		config.AccessKeyID, scope, strings.Join(signedHeaders, ";"), signature))
+}
+
+// hmacSHA256 returns HMAC-SHA256(key, data)
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// sha256Hex returns the hex-encoded SHA-256 of data
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,112 @@
+/**
+ * @fileoverview Per-check registration options for the HealthChecker.
+ * Options adjust how a check's raw results feed the aggregate status, such as
+ * requiring several consecutive failures before a check is considered failing.
+ */
+
+package health
+
+import (
+	"fmt"
+	"sync"
+)
+
+// CheckOption customizes a check at registration time
+type CheckOption func(*checkOptions)
+
+// checkOptions holds the settings collected from CheckOptions
+type checkOptions struct {
+	failureThreshold  int
+	recoveryThreshold int
+}
+
+/**
+ * @description Requires n consecutive failures before the check reports failed.
+ * Failures below the threshold leave the check reporting ok, so a transient failure does
+ * not change the aggregate status; the failure count is shown as detail only.
+ */
+func WithFailureThreshold(n int) CheckOption {
+	return func(opts *checkOptions) {
+		opts.failureThreshold = n
+	}
+}
+
+/**
+ * @description Requires m consecutive successes before a failing check reports ok again.
+ * Prevents a flapping dependency from repeatedly adding and removing the service from rotation.
+ */
+func WithRecoveryThreshold(m int) CheckOption {
+	return func(opts *checkOptions) {
+		opts.recoveryThreshold = m
+	}
+}
+
+/**
+ * @description Applies registration options to a check, returning the check to store.
+ * Returns the check unchanged when no option alters its behavior.
+ */
+func applyCheckOptions(check CheckFunc, options []CheckOption) CheckFunc {
+	opts := checkOptions{failureThreshold: 1, recoveryThreshold: 1}
+	for _, option := range options {
+		option(&opts)
+	}
+
+	if opts.failureThreshold <= 1 && opts.recoveryThreshold <= 1 {
+		return check
+	}
+
+	state := &thresholdState{
+		failureThreshold:  max(opts.failureThreshold, 1),
+		recoveryThreshold: max(opts.recoveryThreshold, 1),
+	}
+	return state.wrap(check)
+}
+
+// thresholdState tracks consecutive results for a single check
+type thresholdState struct {
+	failureThreshold  int
+	recoveryThreshold int
+
+	mu        sync.Mutex
+	failing   bool
+	failures  int
+	successes int
+	lastErr   error
+}
+
+/**
+ * @description Wraps check so its reported result only changes after the configured
+ * number of consecutive failures or successes.
+ */
+func (s *thresholdState) wrap(check CheckFunc) CheckFunc {
+	return func() error {
+		err := check()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if err != nil {
+			s.failures++
+			s.successes = 0
+			s.lastErr = err
+			if !s.failing && s.failures >= s.failureThreshold {
+				s.failing = true
+			}
+			if s.failing || IsDegraded(err) {
+				return err
+			}
+			return &pendingError{err: fmt.Errorf("%d/%d consecutive failures: %w", s.failures, s.failureThreshold, err)}
+		}
+
+		s.successes++
+		s.failures = 0
+		if s.failing && s.successes >= s.recoveryThreshold {
+			s.failing = false
+		}
+		if s.failing {
+			return fmt.Errorf("recovering after %v (%d/%d consecutive successes)",
+				s.lastErr, s.successes, s.recoveryThreshold)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,301 @@
+/**
+ * @fileoverview Client-side health probe for polling another service's health endpoint.
+ * Understands this repository's health JSON and the IETF application/health+json format,
+ * exposes typed results, backs off while the upstream is failing, notifies transition
+ * callbacks, and can be registered with a HealthChecker as an upstream check.
+ */
+
+package probe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/health"
+)
+
+const (
+	// DefaultTimeout bounds a single probe request
+	DefaultTimeout = 5 * time.Second
+	// DefaultInterval is the polling interval while the upstream is healthy
+	DefaultInterval = 10 * time.Second
+	// DefaultMaxBackoff caps the polling interval while the upstream is failing
+	DefaultMaxBackoff = 2 * time.Minute
+	// maxResponseBytes limits how much of a health response is read
+	maxResponseBytes = 1 << 20
+)
+
+// Response formats recognized by the probe
+const (
+	FormatNative = "native"
+	FormatIETF   = "ietf"
+)
+
+// Result is the typed outcome of a single probe
+type Result struct {
+	// Status is health.StatusHealthy, health.StatusDegraded, or health.StatusUnhealthy
+	Status string
+	// Checks maps upstream check or component names to their normalized status
+	Checks     map[string]string
+	Format     string
+	StatusCode int
+	Latency    time.Duration
+	Timestamp  time.Time
+	// Err is set when the request failed or the response could not be understood
+	Err error
+}
+
+// TransitionFunc is called when the probed status changes
+type TransitionFunc func(previous, current Result)
+
+// Config configures a Prober
+type Config struct {
+	URL          string
+	Timeout      time.Duration
+	Interval     time.Duration
+	MaxBackoff   time.Duration
+	Client       *http.Client
+	OnTransition TransitionFunc
+}
+
+// Prober polls a single upstream health endpoint
+type Prober struct {
+	config Config
+	client *http.Client
+
+	mu     sync.RWMutex
+	latest Result
+	probed bool
+}
+
+/**
+ * @description Creates a Prober, applying defaults for unset timeouts and intervals.
+ */
+func New(config Config) *Prober {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultTimeout
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.MaxBackoff < config.Interval {
+		config.MaxBackoff = max(DefaultMaxBackoff, config.Interval)
+	}
+
+	client := config.Client
+	if client == nil {
+		client = &http.Client{Timeout: config.Timeout}
+	}
+
+	return &Prober{config: config, client: client}
+}
+
+/**
+ * @description Performs one probe, records it as the latest result, and fires the transition callback.
+ */
+func (p *Prober) Probe(ctx context.Context) Result {
+	result := p.fetch(ctx)
+
+	p.mu.Lock()
+	previous, hadPrevious := p.latest, p.probed
+	p.latest, p.probed = result, true
+	p.mu.Unlock()
+
+	if hadPrevious && previous.Status != result.Status && p.config.OnTransition != nil {
+		p.config.OnTransition(previous, result)
+	}
+	return result
+}
+
+/**
+ * @description Polls until ctx is cancelled, doubling the interval after each
+ * non-healthy result up to MaxBackoff and resetting it once the upstream recovers.
+ */
+func (p *Prober) Run(ctx context.Context) {
+	interval := p.config.Interval
+
+	for {
+		result := p.Probe(ctx)
+		if result.Status == health.StatusHealthy {
+			interval = p.config.Interval
+		} else {
+			interval = min(interval*2, p.config.MaxBackoff)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+/**
+ * @description Returns the most recent result and whether any probe has completed.
+ */
+func (p *Prober) Latest() (Result, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.latest, p.probed
+}
+
+/**
+ * @description Returns a CheckFunc reporting the upstream's status for registration with a HealthChecker.
+ * Uses the latest polled result when Run is active, probing on demand otherwise.
+ */
+func (p *Prober) UpstreamHealthCheck() health.CheckFunc {
+	return func() error {
+		result, ok := p.Latest()
+		if !ok {
+			ctx, cancel := context.WithTimeout(context.Background(), p.config.Timeout)
+			defer cancel()
+			result = p.Probe(ctx)
+		}
+
+		switch {
+		case result.Err != nil:
+			return fmt.Errorf("upstream %s: %w", p.config.URL, result.Err)
+		case result.Status == health.StatusDegraded:
+			return health.Degraded(fmt.Errorf("upstream %s is degraded", p.config.URL))
+		case result.Status != health.StatusHealthy:
+			return fmt.Errorf("upstream %s is %s", p.config.URL, result.Status)
+		}
+		return nil
+	}
+}
+
+// fetch performs the HTTP request and parses the response
+func (p *Prober) fetch(ctx context.Context) Result {
+	start := time.Now()
+	result := Result{Status: health.StatusUnhealthy, Timestamp: start}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.config.URL, nil)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to create probe request: %w", err)
+		return result
+	}
+	req.Header.Set("Accept", "application/health+json, application/json")
+
+	resp, err := p.client.Do(req)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = fmt.Errorf("probe request failed: %w", err)
+		return result
+	}
+	defer resp.Body.Close()
+	result.StatusCode = resp.StatusCode
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		result.Err = fmt.Errorf("failed to read probe response: %w", err)
+		return result
+	}
+
+	if err := parse(body, &result); err != nil {
+		result.Err = fmt.Errorf("failed to parse probe response (HTTP %d): %w", resp.StatusCode, err)
+		result.Status = health.StatusUnhealthy
+	}
+	return result
+}
+
+// healthDocument covers both the native and IETF response shapes
+type healthDocument struct {
+	Status string                     `json:"status"`
+	Checks map[string]json.RawMessage `json:"checks"`
+}
+
+// ietfCheck is a single component entry in an IETF health response
+type ietfCheck struct {
+	Status string `json:"status"`
+}
+
+// parse decodes a health document into result, detecting its format
+func parse(body []byte, result *Result) error {
+	var document healthDocument
+	if err := json.Unmarshal(body, &document); err != nil {
+		return err
+	}
+	if document.Status == "" {
+		return errors.New("response has no status field")
+	}
+
+	result.Format = FormatNative
+	result.Status = normalizeStatus(document.Status)
+	result.Checks = make(map[string]string, len(document.Checks))
+
+	for name, raw := range document.Checks {
+		var native string
+		if err := json.Unmarshal(raw, &native); err == nil {
+			result.Checks[name] = normalizeCheckValue(native)
+			continue
+		}
+
+		var components []ietfCheck
+		if err := json.Unmarshal(raw, &components); err != nil {
+			return fmt.Errorf("check %q has unrecognized format: %w", name, err)
+		}
+		result.Format = FormatIETF
+		result.Checks[name] = worstStatus(components)
+	}
+
+	if isIETFStatus(document.Status) {
+		result.Format = FormatIETF
+	}
+	return nil
+}
+
+// normalizeStatus maps native and IETF status values onto health package statuses
+func normalizeStatus(status string) string {
+	switch strings.ToLower(status) {
+	case health.StatusHealthy, "pass", "ok", "up":
+		return health.StatusHealthy
+	case health.StatusDegraded, "warn":
+		return health.StatusDegraded
+	default:
+		return health.StatusUnhealthy
+	}
+}
+
+// normalizeCheckValue maps native per-check values ("ok", "degraded: ...", "failed: ...") to statuses
+func normalizeCheckValue(value string) string {
+	switch {
+	case value == "ok":
+		return health.StatusHealthy
+	case strings.HasPrefix(value, health.StatusDegraded):
+		return health.StatusDegraded
+	default:
+		return health.StatusUnhealthy
+	}
+}
+
+// worstStatus returns the most severe normalized status among IETF component entries
+func worstStatus(components []ietfCheck) string {
+	worst := health.StatusHealthy
+	for _, component := range components {
+		switch normalizeStatus(component.Status) {
+		case health.StatusUnhealthy:
+			return health.StatusUnhealthy
+		case health.StatusDegraded:
+			worst = health.StatusDegraded
+		}
+	}
+	return worst
+}
+
+// isIETFStatus reports whether status uses the IETF vocabulary
+func isIETFStatus(status string) bool {
+	switch strings.ToLower(status) {
+	case "pass", "warn", "fail":
+		return true
+	}
+	return false
+}
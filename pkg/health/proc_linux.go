@@ -0,0 +1,51 @@
+/**
+ * @fileoverview Process memory and system load from procfs on Linux.
+ */
+
+package health
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readRSS parses VmRSS from /proc/self/status
+func readRSS() (uint64, error) {
+	data, err := os.ReadFile("/proc/self/status")
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		value, ok := strings.CutPrefix(line, "VmRSS:")
+		if !ok {
+			continue
+		}
+		kb, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected VmRSS value %q", value)
+		}
+		return kb * 1024, nil
+	}
+	return 0, fmt.Errorf("VmRSS not found in /proc/self/status")
+}
+
+// readLoadAverage parses the 1- and 5-minute load averages from /proc/loadavg
+func readLoadAverage() (load1, load5 float64, err error) {
+	data, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0, 0, err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) < 2 {
+		return 0, 0, fmt.Errorf("unexpected /proc/loadavg contents %q", data)
+	}
+	if load1, err = strconv.ParseFloat(fields[0], 64); err != nil {
+		return 0, 0, fmt.Errorf("unexpected 1-minute load %q", fields[0])
+	}
+	if load5, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return 0, 0, fmt.Errorf("unexpected 5-minute load %q", fields[1])
+	}
+	return load1, load5, nil
+}
@@ -0,0 +1,19 @@
+//go:build !linux
+
+/**
+ * @fileoverview Process memory and system load fallbacks for platforms without procfs.
+ */
+
+package health
+
+import "errors"
+
+// readRSS is unsupported on this platform; use HeapCheck instead
+func readRSS() (uint64, error) {
+	return 0, errors.New("resident memory is only available on Linux")
+}
+
+// readLoadAverage is unsupported on this platform; use CPUUsageCheck instead
+func readLoadAverage() (float64, float64, error) {
+	return 0, 0, errors.New("load average is only available on Linux")
+}
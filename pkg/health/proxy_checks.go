@@ -0,0 +1,91 @@
+/**
+ * @fileoverview Outbound proxy connectivity check.
+ * Opens an HTTP CONNECT tunnel through the proxy to a test target, so proxy
+ * authentication failures and blocked destinations surface in readiness rather
+ * than as opaque errors on the first outbound call.
+ */
+
+package health
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ProxyTimeout bounds connecting to the proxy and establishing the tunnel
+const ProxyTimeout = 10 * time.Second
+
+/**
+ * @description Creates a check that issues CONNECT testTarget ("host:port") through the proxy at
+ * proxyURL (http:// or https://, with optional user:password credentials) and requires 200.
+ */
+func ProxyCheck(proxyURL, testTarget string) CheckFunc {
+	return func() error {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return fmt.Errorf("invalid proxy URL: %w", err)
+		}
+
+		address := proxy.Host
+		if proxy.Port() == "" {
+			switch proxy.Scheme {
+			case "http":
+				address = net.JoinHostPort(proxy.Hostname(), "80")
+			case "https":
+				address = net.JoinHostPort(proxy.Hostname(), "443")
+			}
+		}
+
+		dialer := &net.Dialer{Timeout: ProxyTimeout}
+		var conn net.Conn
+		switch proxy.Scheme {
+		case "http":
+			conn, err = dialer.Dial("tcp", address)
+		case "https":
+			conn, err = tls.DialWithDialer(dialer, "tcp", address, &tls.Config{ServerName: proxy.Hostname(), MinVersion: tls.VersionTLS12})
+		default:
+			return fmt.Errorf("unsupported proxy scheme %q (expected http or https)", proxy.Scheme)
+		}
+		if err != nil {
+			return fmt.Errorf("proxy %s unreachable: %w", proxy.Redacted(), err)
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(ProxyTimeout))
+
+		req := &http.Request{
+			Method: http.MethodConnect,
+			URL:    &url.URL{Opaque: testTarget},
+			Host:   testTarget,
+			Header: make(http.Header),
+		}
+		if proxy.User != nil {
+			password, _ := proxy.User.Password()
+			credentials := base64.StdEncoding.EncodeToString([]byte(proxy.User.Username() + ":" + password))
+			req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+		}
+		if err := req.Write(conn); err != nil {
+			return fmt.Errorf("failed to send CONNECT to proxy: %w", err)
+		}
+
+		resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+		if err != nil {
+			return fmt.Errorf("failed to read proxy CONNECT response: %w", err)
+		}
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			return nil
+		case http.StatusProxyAuthRequired:
+			return fmt.Errorf("proxy %s rejected authentication (HTTP 407)", proxy.Redacted())
+		default:
+			return fmt.Errorf("proxy %s refused CONNECT to %s: %s", proxy.Redacted(), testTarget, resp.Status)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+/**
+ * @fileoverview Health checks for the process's own resource usage.
+ * Report degraded while usage is above a threshold so a leaking or overloaded instance
+ * is visible, and can be drained, before the kernel or orchestrator kills it.
+ */
+
+package health
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+/**
+ * @description Creates a check reporting degraded when the process's resident set size exceeds maxRSSBytes.
+ * RSS includes memory outside the Go heap (cgo, stacks, mapped files), which is what the OOM killer sees.
+ */
+func MemoryCheck(maxRSSBytes uint64) CheckFunc {
+	return func() error {
+		rss, err := readRSS()
+		if err != nil {
+			return fmt.Errorf("failed to read process memory usage: %w", err)
+		}
+		if rss > maxRSSBytes {
+			return Degraded(fmt.Errorf("resident memory %s exceeds %s", formatBytes(rss), formatBytes(maxRSSBytes)))
+		}
+		return nil
+	}
+}
+
+/**
+ * @description Creates a check reporting degraded when the Go heap in use exceeds maxHeapBytes.
+ * Works on every platform; uses runtime.ReadMemStats, which briefly stops the world.
+ */
+func HeapCheck(maxHeapBytes uint64) CheckFunc {
+	return func() error {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if stats.HeapInuse > maxHeapBytes {
+			return Degraded(fmt.Errorf("heap in use %s exceeds %s", formatBytes(stats.HeapInuse), formatBytes(maxHeapBytes)))
+		}
+		return nil
+	}
+}
+
+// GoroutineLimitError reports a goroutine count above its threshold
+type GoroutineLimitError struct {
+	Count int
+	Max   int
+}
+
+func (e *GoroutineLimitError) Error() string {
+	return fmt.Sprintf("goroutine count %d exceeds %d", e.Count, e.Max)
+}
+
+/**
+ * @description Creates a check that fails when the number of goroutines exceeds max.
+ * The returned *GoroutineLimitError carries the current count for logs and alerts.
+ */
+func GoroutineCheck(max int) CheckFunc {
+	return func() error {
+		if count := runtime.NumGoroutine(); count > max {
+			return &GoroutineLimitError{Count: count, Max: max}
+		}
+		return nil
+	}
+}
+
+/**
+ * @description Creates a check reporting degraded when the 1- or 5-minute load average per CPU
+ * exceeds its threshold (Linux only). A zero threshold disables that window.
+ */
+func LoadAverageCheck(maxLoad1PerCPU, maxLoad5PerCPU float64) CheckFunc {
+	return func() error {
+		load1, load5, err := readLoadAverage()
+		if err != nil {
+			return fmt.Errorf("failed to read load average: %w", err)
+		}
+
+		cpus := float64(runtime.NumCPU())
+		if maxLoad1PerCPU > 0 && load1/cpus > maxLoad1PerCPU {
+			return Degraded(fmt.Errorf("1-minute load %.2f across %d CPUs exceeds %.2f per CPU", load1, int(cpus), maxLoad1PerCPU))
+		}
+		if maxLoad5PerCPU > 0 && load5/cpus > maxLoad5PerCPU {
+			return Degraded(fmt.Errorf("5-minute load %.2f across %d CPUs exceeds %.2f per CPU", load5, int(cpus), maxLoad5PerCPU))
+		}
+		return nil
+	}
+}
+
+/**
+ * @description Creates a check reporting degraded when this process's CPU utilization since the
+ * previous run exceeds maxPercent of the available CPUs. The first run only takes a baseline.
+ */
+func CPUUsageCheck(maxPercent float64) CheckFunc {
+	var (
+		mu       sync.Mutex
+		lastCPU  time.Duration
+		lastWall time.Time
+	)
+
+	return func() error {
+		cpu, err := processCPUTime()
+		if err != nil {
+			return fmt.Errorf("failed to read process CPU time: %w", err)
+		}
+		now := time.Now()
+
+		mu.Lock()
+		previousCPU, previousWall := lastCPU, lastWall
+		lastCPU, lastWall = cpu, now
+		mu.Unlock()
+
+		elapsed := now.Sub(previousWall)
+		if previousWall.IsZero() || elapsed <= 0 {
+			return nil
+		}
+		percent := float64(cpu-previousCPU) / float64(elapsed) / float64(runtime.GOMAXPROCS(0)) * 100
+		if percent > maxPercent {
+			return Degraded(fmt.Errorf("CPU utilization %.1f%% over the last %v exceeds %.1f%%", percent, elapsed.Round(time.Millisecond), maxPercent))
+		}
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+/**
+ * @fileoverview Data freshness check.
+ * Verifies that a cache, index, or feed has been refreshed recently, a common
+ * readiness condition for services that serve derived data.
+ */
+
+package health
+
+import (
+	"fmt"
+	"time"
+)
+
+/**
+ * @description Creates a check that fails when lastUpdated reports a time more than maxAge ago,
+ * or when it has never been updated (zero time).
+ */
+func StalenessCheck(name string, lastUpdated func() (time.Time, error), maxAge time.Duration) CheckFunc {
+	return func() error {
+		updated, err := lastUpdated()
+		if err != nil {
+			return fmt.Errorf("failed to determine when %s was last updated: %w", name, err)
+		}
+		if updated.IsZero() {
+			return fmt.Errorf("%s has never been updated", name)
+		}
+		if age := time.Since(updated); age > maxAge {
+			return fmt.Errorf("%s is stale: last updated %s ago at %s (max age %s)",
+				name, age.Round(time.Second), updated.UTC().Format(time.RFC3339), maxAge)
+		}
+		return nil
+	}
+}
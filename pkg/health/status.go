@@ -0,0 +1,69 @@
+/**
+ * @fileoverview Health status values and the degraded-check error convention.
+ * A check signals partial impairment by returning an error wrapped with Degraded,
+ * which reports "degraded" without removing the service from rotation. Failures still
+ * below a check's failure threshold are marked pending and reported as ok, with the
+ * failure shown as detail only.
+ */
+
+package health
+
+import "errors"
+
+// Aggregate and per-check status values reported in health responses
+const (
+	StatusHealthy   = "healthy"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+)
+
+// degradedError marks a check failure as degraded rather than unhealthy
+type degradedError struct {
+	err error
+}
+
+func (e *degradedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *degradedError) Unwrap() error {
+	return e.err
+}
+
+/**
+ * @description Wraps err so the check reports degraded instead of failed.
+ * Degraded checks lower the aggregate status but keep readiness returning 200.
+ */
+func Degraded(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &degradedError{err: err}
+}
+
+/**
+ * @description Reports whether err was produced by Degraded anywhere in its chain.
+ */
+func IsDegraded(err error) bool {
+	var degraded *degradedError
+	return errors.As(err, &degraded)
+}
+
+// pendingError marks a failure not yet counted against its check's failure threshold
+type pendingError struct {
+	err error
+}
+
+func (e *pendingError) Error() string {
+	return e.err.Error()
+}
+
+func (e *pendingError) Unwrap() error {
+	return e.err
+}
+
+// isPending reports whether err is a failure below its check's failure threshold
+func isPending(err error) bool {
+	var pending *pendingError
+	return errors.As(err, &pending)
+}
@@ -0,0 +1,51 @@
+/**
+ * @fileoverview Generic threshold check for numeric gauges.
+ * Maps a value such as queue backlog, consumer lag, or pending jobs onto
+ * healthy, degraded, or unhealthy so every team applies the same rules.
+ */
+
+package health
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+/**
+ * @description Creates a check that reads a gauge and reports degraded at or beyond warn and failed
+ * at or beyond fail. When fail is below warn, lower values are worse (e.g. free workers or
+ * throughput). An error reading the gauge, or a NaN value, fails the check.
+ */
+func ThresholdCheck(name string, gauge func() (float64, error), warn, fail float64) CheckFunc {
+	lowerIsWorse := fail < warn
+	breaches := func(value, limit float64) bool {
+		if lowerIsWorse {
+			return value <= limit
+		}
+		return value >= limit
+	}
+
+	return func() error {
+		value, err := gauge()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		if math.IsNaN(value) {
+			return fmt.Errorf("%s is not a number", name)
+		}
+
+		switch {
+		case breaches(value, fail):
+			return fmt.Errorf("%s is %s, at or beyond the failure threshold %s", name, formatGauge(value), formatGauge(fail))
+		case breaches(value, warn):
+			return Degraded(fmt.Errorf("%s is %s, at or beyond the warning threshold %s", name, formatGauge(value), formatGauge(warn)))
+		}
+		return nil
+	}
+}
+
+// formatGauge renders a gauge value without trailing zeros
+func formatGauge(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,98 @@
+/**
+ * @fileoverview Health checks for TLS material such as trust anchors and certificates.
+ * Surfaces expiring or expired certificates and misconfigured key pairs or chains before
+ * they break handshakes.
+ */
+
+package health
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+/**
+ * @description Creates a check over the certificates returned by certs, such as a trust store's anchors.
+ * Reports degraded when any certificate expires within warnWithin and fails once any has expired.
+ */
+func CertificateExpiryCheck(name string, certs func() []*x509.Certificate, warnWithin time.Duration) CheckFunc {
+	return func() error {
+		current := certs()
+		if len(current) == 0 {
+			return fmt.Errorf("%s contains no certificates", name)
+		}
+
+		now := time.Now()
+		var expired, expiring []string
+		for _, cert := range current {
+			switch {
+			case now.After(cert.NotAfter):
+				expired = append(expired, describeCertificate(cert))
+			case cert.NotAfter.Sub(now) < warnWithin:
+				expiring = append(expiring, describeCertificate(cert))
+			}
+		}
+
+		if len(expired) > 0 {
+			return fmt.Errorf("%s has expired certificates: %s", name, strings.Join(expired, ", "))
+		}
+		if len(expiring) > 0 {
+			return Degraded(errors.New(name + " has certificates expiring soon: " + strings.Join(expiring, ", ")))
+		}
+		return nil
+	}
+}
+
+/**
+ * @description Creates a check that loads the PEM cert/key pair, verifying the key matches the
+ * certificate, and validates the certificate and any intermediates in certFile against the
+ * CA bundle. Run it once at startup to catch bad TLS material before the first handshake.
+ */
+func CertificateChainCheck(certFile, keyFile, caBundleFile string) CheckFunc {
+	return func() error {
+		pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("invalid key pair %s / %s: %w", certFile, keyFile, err)
+		}
+
+		caBundle, err := os.ReadFile(caBundleFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(caBundle) {
+			return fmt.Errorf("CA bundle %s contains no certificates", caBundleFile)
+		}
+
+		chain := make([]*x509.Certificate, len(pair.Certificate))
+		for i, der := range pair.Certificate {
+			if chain[i], err = x509.ParseCertificate(der); err != nil {
+				return fmt.Errorf("failed to parse certificate %d in %s: %w", i, certFile, err)
+			}
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range chain[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		_, err = chain[0].Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if err != nil {
+			return fmt.Errorf("certificate %s does not chain to %s: %w", describeCertificate(chain[0]), caBundleFile, err)
+		}
+		return nil
+	}
+}
+
+// describeCertificate formats a certificate's subject and expiry for error messages
+func describeCertificate(cert *x509.Certificate) string {
+	return fmt.Sprintf("%q (expires %s)", cert.Subject.CommonName, cert.NotAfter.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,106 @@
+/**
+ * @fileoverview Vault seal-status check.
+ * Reads /v1/sys/health so secrets-dependent services only report ready against a Vault
+ * node that is initialized, unsealed, and able to serve reads.
+ */
+
+package health
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// VaultCheckOption configures VaultCheck
+type VaultCheckOption func(*vaultCheckConfig)
+
+// vaultCheckConfig holds the settings applied by VaultCheckOptions
+type vaultCheckConfig struct {
+	tlsConfig     *tls.Config
+	allowStandby  bool
+	requireActive bool
+}
+
+// vaultHealthResponse is the subset of /v1/sys/health used by the check
+type vaultHealthResponse struct {
+	Initialized        bool `json:"initialized"`
+	Sealed             bool `json:"sealed"`
+	Standby            bool `json:"standby"`
+	PerformanceStandby bool `json:"performance_standby"`
+}
+
+/**
+ * @description Uses config for https Vault addresses, e.g. a private CA.
+ */
+func WithVaultTLS(config *tls.Config) VaultCheckOption {
+	return func(c *vaultCheckConfig) {
+		c.tlsConfig = config
+	}
+}
+
+/**
+ * @description Accepts a standby node that forwards requests to the active node.
+ */
+func WithVaultStandbyAllowed() VaultCheckOption {
+	return func(c *vaultCheckConfig) {
+		c.allowStandby = true
+	}
+}
+
+/**
+ * @description Accepts only the active node, rejecting performance standbys as well.
+ */
+func WithVaultActiveRequired() VaultCheckOption {
+	return func(c *vaultCheckConfig) {
+		c.requireActive = true
+	}
+}
+
+/**
+ * @description Creates a check that reads /v1/sys/health from the Vault server at addr.
+ * Fails when Vault is uninitialized or sealed, and by default when the node is a standby
+ * that cannot serve reads itself; performance standbys, which serve reads, pass.
+ */
+func VaultCheck(addr string, timeout time.Duration, opts ...VaultCheckOption) CheckFunc {
+	config := &vaultCheckConfig{}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = config.tlsConfig
+	client := &http.Client{Timeout: timeout, Transport: transport}
+	endpoint := strings.TrimSuffix(addr, "/") + "/v1/sys/health"
+
+	return func() error {
+		// Vault encodes node state in the status code; every state still returns a JSON body
+		resp, err := client.Get(endpoint)
+		if err != nil {
+			return fmt.Errorf("vault unreachable: %w", err)
+		}
+		defer resp.Body.Close()
+
+		var status vaultHealthResponse
+		if err := json.NewDecoder(io.LimitReader(resp.Body, httpMaxDrainBytes)).Decode(&status); err != nil {
+			return fmt.Errorf("invalid vault health response (HTTP %d): %w", resp.StatusCode, err)
+		}
+
+		switch {
+		case !status.Initialized:
+			return errors.New("vault is not initialized")
+		case status.Sealed:
+			return errors.New("vault is sealed")
+		case status.PerformanceStandby && config.requireActive:
+			return errors.New("vault node is a performance standby, active node required")
+		case status.Standby && !status.PerformanceStandby && !config.allowStandby:
+			return errors.New("vault node is a standby and cannot serve reads")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,203 @@
+/**
+ * @fileoverview Vector store checks for RAG services.
+ * Each check verifies connectivity and that the collection, index, or table the service
+ * queries actually exists, so readiness reflects whether retrieval can work.
+ */
+
+package health
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// pineconeAPIVersion is the control-plane API version sent to Pinecone
+const pineconeAPIVersion = "2025-01"
+
+/**
+ * @description Creates a check that requires the Qdrant collection at baseURL to exist and be green or yellow.
+ * apiKey may be empty for unauthenticated deployments.
+ */
+func QdrantCheck(baseURL, collection, apiKey string, timeout time.Duration) CheckFunc {
+	client := &http.Client{Timeout: timeout}
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/collections/" + url.PathEscape(collection)
+
+	return func() error {
+		var body struct {
+			Result struct {
+				Status string `json:"status"`
+			} `json:"result"`
+		}
+		if err := getVectorStore(client, "qdrant", endpoint, map[string]string{"api-key": apiKey}, collection, &body); err != nil {
+			return err
+		}
+
+		switch body.Result.Status {
+		case "green":
+			return nil
+		case "yellow":
+			return Degraded(fmt.Errorf("qdrant collection %s is optimizing (yellow)", collection))
+		default:
+			return fmt.Errorf("qdrant collection %s status is %q", collection, body.Result.Status)
+		}
+	}
+}
+
+/**
+ * @description Returns a DimensionFunc reporting the vector size of the Qdrant collection at
+ * baseURL, for use with EmbeddingDimensionCheck. A collection with named vectors must have
+ * exactly one. apiKey may be empty for unauthenticated deployments.
+ */
+func QdrantCollectionDimensions(baseURL, collection, apiKey string, timeout time.Duration) DimensionFunc {
+	client := &http.Client{Timeout: timeout}
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/collections/" + url.PathEscape(collection)
+
+	return func() (int, error) {
+		var body struct {
+			Result struct {
+				Config struct {
+					Params struct {
+						Vectors json.RawMessage `json:"vectors"`
+					} `json:"params"`
+				} `json:"config"`
+			} `json:"result"`
+		}
+		if err := getVectorStore(client, "qdrant", endpoint, map[string]string{"api-key": apiKey}, collection, &body); err != nil {
+			return 0, err
+		}
+
+		// Vectors are either one unnamed {"size": n} or a map of named vector parameters
+		var unnamed struct {
+			Size int `json:"size"`
+		}
+		if err := json.Unmarshal(body.Result.Config.Params.Vectors, &unnamed); err == nil && unnamed.Size > 0 {
+			return unnamed.Size, nil
+		}
+		var named map[string]struct {
+			Size int `json:"size"`
+		}
+		if err := json.Unmarshal(body.Result.Config.Params.Vectors, &named); err != nil || len(named) != 1 {
+			return 0, fmt.Errorf("qdrant collection %s does not have a single vector configuration", collection)
+		}
+		for _, params := range named {
+			return params.Size, nil
+		}
+		return 0, nil
+	}
+}
+
+/**
+ * @description Creates a check that requires the Pinecone index to exist and report ready.
+ */
+func PineconeCheck(index, apiKey string, timeout time.Duration) CheckFunc {
+	client := &http.Client{Timeout: timeout}
+	endpoint := "https://api.pinecone.io/indexes/" + url.PathEscape(index)
+	headers := map[string]string{"Api-Key": apiKey, "X-Pinecone-API-Version": pineconeAPIVersion}
+
+	return func() error {
+		var body struct {
+			Status struct {
+				Ready bool   `json:"ready"`
+				State string `json:"state"`
+			} `json:"status"`
+		}
+		if err := getVectorStore(client, "pinecone", endpoint, headers, index, &body); err != nil {
+			return err
+		}
+		if !body.Status.Ready {
+			return fmt.Errorf("pinecone index %s is not ready (state %s)", index, body.Status.State)
+		}
+		return nil
+	}
+}
+
+/**
+ * @description Creates a check that requires the Weaviate class (collection) at baseURL to exist.
+ * apiKey may be empty for anonymous access.
+ */
+func WeaviateCheck(baseURL, class, apiKey string, timeout time.Duration) CheckFunc {
+	client := &http.Client{Timeout: timeout}
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/v1/schema/" + url.PathEscape(class)
+	headers := map[string]string{}
+	if apiKey != "" {
+		headers["Authorization"] = "Bearer " + apiKey
+	}
+
+	return func() error {
+		var body struct {
+			Class string `json:"class"`
+		}
+		return getVectorStore(client, "weaviate", endpoint, headers, class, &body)
+	}
+}
+
+/**
+ * @description Creates a check that requires the pgvector extension to be installed and table
+ * (optionally schema-qualified) to exist in the database.
+ */
+func PgvectorCheck(db *sql.DB, table string, timeout time.Duration) CheckFunc {
+	return func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		var version string
+		err := db.QueryRowContext(ctx, "SELECT extversion FROM pg_extension WHERE extname = 'vector'").Scan(&version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("pgvector extension is not installed")
+		}
+		if err != nil {
+			return fmt.Errorf("pgvector query failed: %w", err)
+		}
+
+		var exists bool
+		if err := db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists); err != nil {
+			return fmt.Errorf("pgvector table lookup failed: %w", err)
+		}
+		if !exists {
+			return fmt.Errorf("pgvector table %s does not exist", table)
+		}
+		return nil
+	}
+}
+
+// getVectorStore GETs endpoint and decodes the JSON response into out, mapping 404 to a missing collection
+func getVectorStore(client *http.Client, store, endpoint string, headers map[string]string, collection string, out any) error {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("invalid %s URL: %w", store, err)
+	}
+	for key, value := range headers {
+		if value != "" {
+			req.Header.Set(key, value)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s unreachable: %w", store, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return fmt.Errorf("%s collection %s does not exist", store, collection)
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%s rejected credentials (HTTP %d)", store, resp.StatusCode)
+	default:
+		return fmt.Errorf("%s returned HTTP %d", store, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, httpMaxDrainBytes)).Decode(out); err != nil {
+		return fmt.Errorf("invalid %s response: %w", store, err)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+/**
+ * @fileoverview Webhook delivery of health check status transitions.
+ * POSTs a JSON TransitionEvent to configured URLs with retries and optional
+ * HMAC-SHA256 signing so alerting tools can consume transitions without polling.
+ * Deliveries wait in a bounded queue served by a fixed pool of workers, so a flapping
+ * check cannot pile up goroutines, and retries stop when the workers' context ends.
+ */
+
+package health
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+)
+
+const (
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body
+	WebhookSignatureHeader = "X-Health-Signature"
+	// DefaultWebhookTimeout bounds each delivery attempt
+	DefaultWebhookTimeout = 5 * time.Second
+	// DefaultWebhookMaxRetries is the number of retries after the first failed attempt
+	DefaultWebhookMaxRetries = 3
+	// DefaultWebhookRetryDelay is the initial delay between attempts, doubled after each retry
+	DefaultWebhookRetryDelay = time.Second
+	// WebhookQueueSize is the number of deliveries that may wait for a worker; more are dropped
+	WebhookQueueSize = 100
+	// WebhookWorkers is the number of deliveries made concurrently
+	WebhookWorkers = 4
+)
+
+// WebhookConfig configures delivery to a single webhook endpoint
+type WebhookConfig struct {
+	URL string
+	// Secret signs payloads with HMAC-SHA256 when non-empty
+	Secret     string
+	Timeout    time.Duration
+	MaxRetries int
+	RetryDelay time.Duration
+	// Transport sends deliveries; nil uses http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+// webhookDelivery is one event waiting to be sent to one webhook
+type webhookDelivery struct {
+	client *http.Client
+	config WebhookConfig
+	event  TransitionEvent
+}
+
+/**
+ * @description Registers a webhook that receives every status transition.
+ * Deliveries are queued for RunWebhooks; dropped deliveries and exhausted retries are
+ * reported through the checker's Logger.
+ */
+func (hc *HealthChecker) AddWebhook(config WebhookConfig) {
+	hc.AddTransitionListener(newWebhookListener(config, hc.webhookQueue, hc.logger))
+}
+
+/**
+ * @description Delivers queued webhook events with WebhookWorkers workers until ctx is
+ * cancelled, which also abandons in-flight retries. Returns once every worker has stopped.
+ */
+func (hc *HealthChecker) RunWebhooks(ctx context.Context) {
+	var workers sync.WaitGroup
+	for i := 0; i < WebhookWorkers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case delivery := <-hc.webhookQueue:
+					if err := deliverWebhook(ctx, delivery.client, delivery.config, delivery.event); err != nil {
+						hc.logger.Error("health webhook delivery failed", "url", delivery.config.URL, "check", delivery.event.Check, "error", err)
+					}
+				}
+			}
+		}()
+	}
+	workers.Wait()
+}
+
+/**
+ * @description Builds a transition listener that queues events for the configured webhook.
+ */
+func newWebhookListener(config WebhookConfig, queue chan<- webhookDelivery, logger Logger) TransitionListener {
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultWebhookTimeout
+	}
+	if config.MaxRetries < 0 {
+		config.MaxRetries = 0
+	} else if config.MaxRetries == 0 {
+		config.MaxRetries = DefaultWebhookMaxRetries
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = DefaultWebhookRetryDelay
+	}
+
+	client := &http.Client{
+		Timeout:   config.Timeout,
+		Transport: config.Transport,
+	}
+
+	return func(event TransitionEvent) {
+		select {
+		case queue <- webhookDelivery{client: client, config: config, event: event}:
+		default:
+			logger.Warn("health webhook queue full, dropping delivery", "url", config.URL, "check", event.Check)
+		}
+	}
+}
+
+/**
+ * @description Delivers a single event, retrying with exponential backoff on errors and non-2xx
+ * responses until the retries are exhausted or ctx is cancelled.
+ */
+func deliverWebhook(ctx context.Context, client *http.Client, config WebhookConfig, event TransitionEvent) error {
+	payload, err := jsoncase.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	delay := config.RetryDelay
+	var lastErr error
+
+	for attempt := 0; attempt <= config.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("abandoned after %d attempts: %w", attempt, lastErr)
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		if lastErr = postWebhook(ctx, client, config, payload); lastErr == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", config.MaxRetries+1, lastErr)
+}
+
+// postWebhook performs one signed POST of the payload
+func postWebhook(ctx context.Context, client *http.Client, config WebhookConfig, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, config.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if config.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(config.Secret, payload))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request to %s failed: %w", config.URL, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook %s returned status %d", config.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+/**
+ * @description Returns the hex-encoded HMAC-SHA256 of payload using secret.
+ * Receivers recompute this to verify the X-Health-Signature header.
+ */
+func SignWebhookPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,39 @@
+/**
+ * @fileoverview Shared bearer-token protection for operator-only endpoints.
+ */
+
+package httputil
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+)
+
+/**
+ * @description Reports whether r carries "Authorization: Bearer <token>"; an empty token never matches.
+ * Compares in constant time.
+ */
+func HasBearerToken(r *http.Request, token string) bool {
+	presented, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1
+}
+
+/**
+ * @description Wraps next so requests without the bearer token receive 401.
+ */
+func RequireBearerToken(token string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !HasBearerToken(r, token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="admin"`)
+			apierror.Write(w, r, http.StatusUnauthorized, "a valid bearer token is required")
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,143 @@
+/**
+ * @fileoverview Client identification shared by logging and per-client limits.
+ * Forwarding headers are only believed when the immediate peer is a trusted proxy, and
+ * are then walked from the nearest hop outwards until an untrusted address is found, so
+ * clients cannot spoof their address by sending X-Forwarded-For themselves.
+ */
+
+package httputil
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"sync/atomic"
+)
+
+// trustedProxies holds the CIDRs whose forwarding headers are believed
+var trustedProxies atomic.Pointer[[]netip.Prefix]
+
+/**
+ * @description Parses CIDRs or bare IP addresses into prefixes.
+ */
+func ParseTrustedProxies(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+			}
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", value, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+/**
+ * @description Sets the proxies whose X-Forwarded-For, Forwarded, and X-Real-IP headers
+ * ClientIP believes; with none, the peer address is always used.
+ */
+func SetTrustedProxies(prefixes []netip.Prefix) {
+	trustedProxies.Store(&prefixes)
+}
+
+/**
+ * @description Returns the client's address: the peer address, or when the peer is a trusted
+ * proxy, the nearest untrusted address in Forwarded, X-Forwarded-For, or X-Real-IP.
+ */
+func ClientIP(r *http.Request) string {
+	peer := peerIP(r)
+	if !isTrusted(peer) {
+		return peer
+	}
+
+	hops := forwardedFor(r.Header)
+	if len(hops) == 0 {
+		if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); realIP != "" {
+			return realIP
+		}
+		return peer
+	}
+
+	// Proxies append, so the nearest hop is last; the first untrusted one is the client
+	for i := len(hops) - 1; i >= 0; i-- {
+		if !isTrusted(hops[i]) {
+			return hops[i]
+		}
+	}
+	return hops[0]
+}
+
+// peerIP returns the host part of the request's remote address
+func peerIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// isTrusted reports whether ip falls in a trusted proxy range
+func isTrusted(ip string) bool {
+	prefixes := trustedProxies.Load()
+	if prefixes == nil || len(*prefixes) == 0 {
+		return false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range *prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedFor returns the client chain from the Forwarded header, or X-Forwarded-For without one
+func forwardedFor(header http.Header) []string {
+	var hops []string
+	for _, value := range header.Values("Forwarded") {
+		for _, element := range strings.Split(value, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, node, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if ok && strings.EqualFold(key, "for") {
+					hops = append(hops, forwardedNode(node))
+				}
+			}
+		}
+	}
+	if len(hops) > 0 {
+		return hops
+	}
+
+	for _, value := range header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, hop)
+			}
+		}
+	}
+	return hops
+}
+
+// forwardedNode strips quotes, brackets, and any port from a Forwarded "for" node
+func forwardedNode(node string) string {
+	node = strings.Trim(strings.TrimSpace(node), `"`)
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		return host
+	}
+	return strings.Trim(node, "[]")
+}
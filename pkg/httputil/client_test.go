@@ -0,0 +1,137 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "bare addresses", values: []string{"10.0.0.1", " ::1 "}, want: []string{"10.0.0.1/32", "::1/128"}},
+		{name: "mapped address", values: []string{"::ffff:10.0.0.1"}, want: []string{"10.0.0.1/32"}},
+		{name: "masked CIDR", values: []string{"10.1.2.3/8"}, want: []string{"10.0.0.0/8"}},
+		{name: "invalid address", values: []string{"proxy.internal"}, wantErr: true},
+		{name: "invalid CIDR", values: []string{"10.0.0.0/33"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefixes, err := ParseTrustedProxies(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTrustedProxies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(prefixes) != len(tt.want) {
+				t.Fatalf("ParseTrustedProxies() = %v, want %v", prefixes, tt.want)
+			}
+			for i, prefix := range prefixes {
+				if prefix.String() != tt.want[i] {
+					t.Errorf("prefix %d = %s, want %s", i, prefix, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		header     map[string][]string
+		want       string
+	}{
+		{name: "peer without proxies", remoteAddr: "203.0.113.7:4000", want: "203.0.113.7"},
+		{name: "peer without port", remoteAddr: "203.0.113.7", want: "203.0.113.7"},
+		{
+			name:       "spoofed header from untrusted peer",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "203.0.113.7:4000",
+			header:     map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "forwarded for through trusted proxy",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:4000",
+			header:     map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "nearest untrusted hop wins over spoofed prefix",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:4000",
+			header:     map[string][]string{"X-Forwarded-For": {"192.0.2.9, 198.51.100.1, 10.0.0.3"}},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "repeated forwarded for headers",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:4000",
+			header:     map[string][]string{"X-Forwarded-For": {"198.51.100.1", "10.0.0.3"}},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "all hops trusted",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:4000",
+			header:     map[string][]string{"X-Forwarded-For": {"10.0.0.4, 10.0.0.3"}},
+			want:       "10.0.0.4",
+		},
+		{
+			name:       "forwarded header preferred",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:4000",
+			header: map[string][]string{
+				"Forwarded":       {`for=198.51.100.1;proto=https, for="[2001:db8::1]:443"`},
+				"X-Forwarded-For": {"192.0.2.9"},
+			},
+			want: "2001:db8::1",
+		},
+		{
+			name:       "real IP without forwarding chain",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.2:4000",
+			header:     map[string][]string{"X-Real-Ip": {" 198.51.100.1 "}},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "trusted peer without headers",
+			trusted:    []string{"10.0.0.2"},
+			remoteAddr: "10.0.0.2:4000",
+			want:       "10.0.0.2",
+		},
+		{
+			name:       "mapped peer address",
+			trusted:    []string{"10.0.0.0/8"},
+			remoteAddr: "[::ffff:10.0.0.2]:4000",
+			header:     map[string][]string{"X-Forwarded-For": {"198.51.100.1"}},
+			want:       "198.51.100.1",
+		},
+	}
+
+	t.Cleanup(func() { SetTrustedProxies(nil) })
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefixes, err := ParseTrustedProxies(tt.trusted)
+			if err != nil {
+				t.Fatalf("ParseTrustedProxies() error = %v", err)
+			}
+			SetTrustedProxies(prefixes)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for name, values := range tt.header {
+				req.Header[name] = values
+			}
+			if got := ClientIP(req); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+/**
+ * @fileoverview Conditional GET support for API responses.
+ * Responses written through WriteConditional carry an ETag computed from the body and,
+ * when the caller knows it, a Last-Modified time. Clients that send the ETag back in
+ * If-None-Match, or the time in If-Modified-Since, get an empty 304 when nothing changed,
+ * so polling costs a round trip instead of a full body.
+ */
+
+package httputil
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+)
+
+/**
+ * @description Returns a strong entity tag for body, quoted as sent in the ETag header.
+ */
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+}
+
+/**
+ * @description Reports whether a GET or HEAD request's validators show the client already has
+ * the representation identified by etag and lastModified. If-None-Match takes precedence;
+ * If-Modified-Since is only consulted without it, and a zero lastModified never matches.
+ */
+func NotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if header := r.Header.Get("If-None-Match"); header != "" {
+		return etag != "" && matchesETag(header, etag)
+	}
+	if header := r.Header.Get("If-Modified-Since"); header != "" && !lastModified.IsZero() {
+		since, err := http.ParseTime(header)
+		// HTTP dates have second precision
+		return err == nil && !lastModified.Truncate(time.Second).After(since)
+	}
+	return false
+}
+
+/**
+ * @description Writes body with status, adding ETag and Last-Modified headers to successful
+ * GET and HEAD responses and answering 304 without a body when NotModified. lastModified
+ * may be zero when unknown. Headers such as Content-Type must be set beforehand.
+ */
+func WriteConditional(w http.ResponseWriter, r *http.Request, status int, body []byte, lastModified time.Time) error {
+	if status == http.StatusOK && (r.Method == http.MethodGet || r.Method == http.MethodHead) {
+		header := w.Header()
+		etag := header.Get("ETag")
+		if etag == "" {
+			etag = ETag(body)
+			header.Set("ETag", etag)
+		}
+		if !lastModified.IsZero() {
+			header.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+		}
+		if NotModified(r, etag, lastModified) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+	}
+
+	w.WriteHeader(status)
+	_, err := w.Write(body)
+	return err
+}
+
+// matchesETag applies the weak comparison If-None-Match requires to a list of entity tags
+func matchesETag(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
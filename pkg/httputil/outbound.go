@@ -0,0 +1,118 @@
+/**
+ * @fileoverview Instrumented transport for outbound HTTP calls.
+ * Wrapping a client's transport records every failed call (transport errors and 5xx
+ * responses) in a bounded window, so health diagnosis can show the dependency errors
+ * that coincided with a failing check, and counts them per host as a metric.
+ */
+
+package httputil
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+// DefaultOutboundErrorHistory is the number of failed outbound calls an OutboundRecorder keeps
+const DefaultOutboundErrorHistory = 100
+
+var outboundErrors = metrics.NewCounterVec("http_client_errors_total",
+	"Outbound HTTP calls that failed or returned a 5xx status, by host.", "host")
+
+// OutboundError is one failed outbound call
+type OutboundError struct {
+	Timestamp time.Time `json:"timestamp"`
+	Method    string    `json:"method"`
+	Host      string    `json:"host"`
+	Path      string    `json:"path"`
+	// Status is the response status for 5xx responses and zero for transport errors
+	Status   int    `json:"status,omitempty"`
+	Error    string `json:"error"`
+	Duration string `json:"duration"`
+}
+
+// OutboundRecorder keeps the most recent failed outbound calls
+type OutboundRecorder struct {
+	mu     sync.Mutex
+	size   int
+	errors []OutboundError
+	now    func() time.Time
+}
+
+/**
+ * @description Creates a recorder keeping the last size failed calls; non-positive sizes
+ * use DefaultOutboundErrorHistory.
+ */
+func NewOutboundRecorder(size int) *OutboundRecorder {
+	if size <= 0 {
+		size = DefaultOutboundErrorHistory
+	}
+	return &OutboundRecorder{size: size, now: time.Now}
+}
+
+/**
+ * @description Wraps next, or http.DefaultTransport when nil, so failed calls are recorded.
+ * Responses and errors are passed through unchanged.
+ */
+func (o *OutboundRecorder) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		start := o.now()
+		resp, err := next.RoundTrip(req)
+		switch {
+		case err != nil:
+			o.record(req, start, 0, err.Error())
+		case resp.StatusCode >= http.StatusInternalServerError:
+			o.record(req, start, resp.StatusCode, resp.Status)
+		}
+		return resp, err
+	})
+}
+
+/**
+ * @description Returns the recorded failures at or after since, oldest first.
+ */
+func (o *OutboundRecorder) Errors(since time.Time) []OutboundError {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var recent []OutboundError
+	for _, failure := range o.errors {
+		if !failure.Timestamp.Before(since) {
+			recent = append(recent, failure)
+		}
+	}
+	return recent
+}
+
+// record appends a failed call, dropping the oldest once the recorder is full
+func (o *OutboundRecorder) record(req *http.Request, start time.Time, status int, message string) {
+	outboundErrors.With(req.URL.Host).Add(1)
+	failure := OutboundError{
+		Timestamp: start.UTC(),
+		Method:    req.Method,
+		Host:      req.URL.Host,
+		Path:      req.URL.Path,
+		Status:    status,
+		Error:     message,
+		Duration:  o.now().Sub(start).String(),
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.errors = append(o.errors, failure)
+	if len(o.errors) > o.size {
+		o.errors = o.errors[len(o.errors)-o.size:]
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,157 @@
+/**
+ * @fileoverview Response writer wrapper that makes partial writes and client aborts observable.
+ * Records the status, byte count, and serialization time, suppresses duplicate WriteHeader
+ * calls, stops writing after the first failed write, and counts aborted responses for monitoring.
+ */
+
+package httputil
+
+import (
+	"errors"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+// abortedResponses counts responses whose body could not be fully written
+var abortedResponses = metrics.NewCounter("http_aborted_responses_total", "Responses whose body could not be fully written.")
+
+// ResponseWriter wraps an http.ResponseWriter and tracks the response outcome
+type ResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	bytes       int64
+	writeErr    error
+	serialize   time.Duration
+}
+
+/**
+ * @description Wraps w, returning it unchanged if it is already a *ResponseWriter.
+ */
+func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	if wrapped, ok := w.(*ResponseWriter); ok {
+		return wrapped
+	}
+	return &ResponseWriter{ResponseWriter: w, status: http.StatusOK}
+}
+
+/**
+ * @description Sends the status code once; later calls are ignored instead of logging superfluous-call warnings.
+ */
+func (rw *ResponseWriter) WriteHeader(statusCode int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.status = statusCode
+	rw.ResponseWriter.WriteHeader(statusCode)
+}
+
+/**
+ * @description Writes body bytes, recording the first write error and refusing further writes after it.
+ */
+func (rw *ResponseWriter) Write(b []byte) (int, error) {
+	if rw.writeErr != nil {
+		return 0, rw.writeErr
+	}
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+
+	n, err := rw.ResponseWriter.Write(b)
+	rw.bytes += int64(n)
+	if err != nil {
+		rw.writeErr = err
+		abortedResponses.Add(1)
+	}
+	return n, err
+}
+
+/**
+ * @description Flushes buffered data to the client when the underlying writer supports it,
+ * unwrapping middleware writers that do not implement http.Flusher themselves.
+ */
+func (rw *ResponseWriter) Flush() {
+	rw.FlushError()
+}
+
+/**
+ * @description Flushes like Flush and returns the error, so http.ResponseController reports
+ * http.ErrNotSupported when no writer below can flush.
+ */
+func (rw *ResponseWriter) FlushError() error {
+	return http.NewResponseController(rw.ResponseWriter).Flush()
+}
+
+/**
+ * @description Returns the wrapped writer so http.ResponseController can reach optional interfaces.
+ */
+func (rw *ResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+/**
+ * @description Returns the status code sent, or 200 if no header has been written.
+ */
+func (rw *ResponseWriter) Status() int {
+	return rw.status
+}
+
+/**
+ * @description Reports whether WriteHeader has been called.
+ */
+func (rw *ResponseWriter) WroteHeader() bool {
+	return rw.wroteHeader
+}
+
+/**
+ * @description Returns the number of body bytes successfully written.
+ */
+func (rw *ResponseWriter) BytesWritten() int64 {
+	return rw.bytes
+}
+
+/**
+ * @description Returns the first write error, if any.
+ */
+func (rw *ResponseWriter) Err() error {
+	return rw.writeErr
+}
+
+/**
+ * @description Reports whether the response was cut short by a write error.
+ */
+func (rw *ResponseWriter) Aborted() bool {
+	return rw.writeErr != nil
+}
+
+/**
+ * @description Adds time spent encoding the response body; called by encoders such as jsoncase.Write.
+ */
+func (rw *ResponseWriter) ObserveSerialization(d time.Duration) {
+	rw.serialize += d
+}
+
+/**
+ * @description Returns the total time spent encoding the response body.
+ */
+func (rw *ResponseWriter) SerializationTime() time.Duration {
+	return rw.serialize
+}
+
+/**
+ * @description Reports whether err indicates the client went away (broken pipe or connection reset).
+ */
+func IsClientDisconnect(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)
+}
+
+/**
+ * @description Returns the number of aborted responses recorded since startup.
+ */
+func AbortedResponses() int64 {
+	return abortedResponses.Value()
+}
@@ -0,0 +1,134 @@
+/**
+ * @fileoverview Server-Sent Events response writer.
+ * Frames events with id, event, retry, and multi-line data fields, flushes after every
+ * write so events reach the client immediately, and reports client disconnects through
+ * the request context so streaming handlers stop producing output nobody will read.
+ */
+
+package httputil
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrInvalidSSEField is returned when an event's id or type contains a line break
+var ErrInvalidSSEField = errors.New("httputil: SSE id and event must not contain line breaks")
+
+// SSEEvent is one Server-Sent Event; empty fields are omitted
+type SSEEvent struct {
+	ID    string
+	Event string
+	// Data is sent as one data line per line of text
+	Data string
+	// Retry tells the client how long to wait before reconnecting
+	Retry time.Duration
+}
+
+// SSEWriter writes Server-Sent Events to a response
+type SSEWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	ctx        context.Context
+}
+
+/**
+ * @description Starts an event stream: clears the server's write deadline, sends the SSE
+ * headers and a 200 status, and flushes them. Returns an error if the response cannot be
+ * flushed, in which case nothing has been written.
+ */
+func NewSSEWriter(w http.ResponseWriter, r *http.Request) (*SSEWriter, error) {
+	controller := http.NewResponseController(w)
+	// Streams outlive the server's write timeout; clear it for this response
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return nil, fmt.Errorf("failed to clear write deadline: %w", err)
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := controller.Flush(); err != nil {
+		return nil, fmt.Errorf("streaming unsupported: %w", err)
+	}
+	return &SSEWriter{w: w, controller: controller, ctx: r.Context()}, nil
+}
+
+/**
+ * @description Writes and flushes one event. Writing still works after the request context
+ * is cancelled by the server, so a final event can explain why the stream is ending.
+ */
+func (s *SSEWriter) Send(event SSEEvent) error {
+	if strings.ContainsAny(event.ID, "\r\n\x00") || strings.ContainsAny(event.Event, "\r\n") {
+		return ErrInvalidSSEField
+	}
+
+	var b strings.Builder
+	if event.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", event.ID)
+	}
+	if event.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", event.Event)
+	}
+	if event.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", event.Retry.Milliseconds())
+	}
+	for _, line := range strings.Split(strings.ReplaceAll(event.Data, "\r\n", "\n"), "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+
+	if _, err := s.w.Write([]byte(b.String())); err != nil {
+		return err
+	}
+	return s.Flush()
+}
+
+/**
+ * @description Sends v encoded as JSON in the data field of an event.
+ */
+func (s *SSEWriter) SendJSON(id, event string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	return s.Send(SSEEvent{ID: id, Event: event, Data: string(data)})
+}
+
+/**
+ * @description Writes and flushes a comment line, which clients ignore; useful as a heartbeat.
+ */
+func (s *SSEWriter) Comment(text string) error {
+	if _, err := fmt.Fprintf(s.w, ": %s\n\n", strings.ReplaceAll(text, "\n", " ")); err != nil {
+		return err
+	}
+	return s.Flush()
+}
+
+/**
+ * @description Flushes buffered output to the client.
+ */
+func (s *SSEWriter) Flush() error {
+	return s.controller.Flush()
+}
+
+/**
+ * @description Returns a channel closed when the client disconnects or the request is cancelled.
+ * Streaming handlers select on it so they stop producing events nobody will read.
+ */
+func (s *SSEWriter) Done() <-chan struct{} {
+	return s.ctx.Done()
+}
+
+/**
+ * @description Reports why Done was closed, or nil while the client is connected.
+ */
+func (s *SSEWriter) Err() error {
+	return s.ctx.Err()
+}
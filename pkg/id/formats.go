@@ -0,0 +1,94 @@
+/**
+ * @fileoverview Encoders for the supported identifier formats.
+ */
+
+package id
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"time"
+)
+
+const (
+	// crockfordAlphabet is the ULID base32 alphabet, which omits I, L, O, and U
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	// base62Alphabet orders digits before letters so encoded KSUIDs sort like their bytes
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// ksuidEpoch is the KSUID timestamp origin (2014-05-13T16:53:20Z)
+	ksuidEpoch = 1400000000
+	// ksuidLength is the fixed length of an encoded KSUID
+	ksuidLength = 27
+)
+
+/**
+ * @description Builds a version 7 UUID: 48-bit Unix milliseconds followed by random bits.
+ */
+func newUUIDv7(now time.Time) string {
+	var b [16]byte
+	randomBytes(b[6:])
+	putMillis(b[:6], now)
+	b[6] = (b[6] & 0x0f) | 0x70 // version 7
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 9562 variant
+
+	var out [36]byte
+	hex.Encode(out[0:8], b[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], b[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], b[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], b[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], b[10:])
+	return string(out[:])
+}
+
+/**
+ * @description Builds a ULID: 48-bit Unix milliseconds and 80 random bits in Crockford base32.
+ */
+func newULID(now time.Time) string {
+	var b [16]byte
+	putMillis(b[:6], now)
+	randomBytes(b[6:])
+
+	// 128 bits encode to 26 characters; the first carries only the top 3 bits
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+/**
+ * @description Builds a KSUID: 32-bit seconds since the KSUID epoch and 128 random bits in base62.
+ */
+func newKSUID(now time.Time) string {
+	var b [20]byte
+	binary.BigEndian.PutUint32(b[:4], uint32(now.Unix()-ksuidEpoch))
+	randomBytes(b[4:])
+
+	n := new(big.Int).SetBytes(b[:])
+	base := big.NewInt(62)
+	mod := new(big.Int)
+	out := make([]byte, ksuidLength)
+	for i := ksuidLength - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		out[i] = base62Alphabet[mod.Int64()]
+	}
+	return string(out)
+}
+
+// putMillis writes the Unix millisecond timestamp into a 6-byte big-endian field
+func putMillis(dst []byte, now time.Time) {
+	ms := uint64(now.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		dst[i] = byte(ms)
+		ms >>= 8
+	}
+}
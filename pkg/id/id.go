@@ -0,0 +1,94 @@
+/**
+ * @fileoverview Pluggable identifier generation shared by request, job, and document IDs.
+ * All supported formats are time-ordered so new IDs append to the end of database indexes
+ * and sort chronologically in logs. The process-wide format is chosen once at startup.
+ */
+
+package id
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Format selects the identifier encoding
+type Format string
+
+// Supported identifier formats
+const (
+	// FormatUUIDv7 emits RFC 9562 version 7 UUIDs (millisecond timestamp, 36 chars)
+	FormatUUIDv7 Format = "uuidv7"
+	// FormatULID emits ULIDs (millisecond timestamp, 26 Crockford base32 chars)
+	FormatULID Format = "ulid"
+	// FormatKSUID emits KSUIDs (second timestamp, 27 base62 chars)
+	FormatKSUID Format = "ksuid"
+)
+
+// defaultFormat is the process-wide format used by New
+var defaultFormat atomic.Value
+
+func init() {
+	defaultFormat.Store(FormatUUIDv7)
+}
+
+/**
+ * @description Parses a format name, accepting an empty string as UUIDv7.
+ */
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case "", FormatUUIDv7, "uuid7", "uuid":
+		return FormatUUIDv7, nil
+	case FormatULID:
+		return FormatULID, nil
+	case FormatKSUID:
+		return FormatKSUID, nil
+	default:
+		return "", fmt.Errorf("unknown ID format %q (expected uuidv7, ulid, or ksuid)", name)
+	}
+}
+
+/**
+ * @description Sets the process-wide format; call once during startup.
+ */
+func SetDefault(format Format) {
+	defaultFormat.Store(format)
+}
+
+/**
+ * @description Returns the process-wide format.
+ */
+func Default() Format {
+	return defaultFormat.Load().(Format)
+}
+
+/**
+ * @description Returns a new identifier in the process-wide format.
+ */
+func New() string {
+	return NewFormat(Default())
+}
+
+/**
+ * @description Returns a new identifier in the given format, falling back to UUIDv7 for unknown formats.
+ */
+func NewFormat(format Format) string {
+	now := time.Now()
+	switch format {
+	case FormatULID:
+		return newULID(now)
+	case FormatKSUID:
+		return newKSUID(now)
+	default:
+		return newUUIDv7(now)
+	}
+}
+
+// randomBytes fills b from the system CSPRNG, which never fails on supported platforms
+func randomBytes(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("id: crypto/rand failed: %v", err))
+	}
+}
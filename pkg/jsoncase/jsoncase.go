@@ -0,0 +1,436 @@
+/**
+ * @fileoverview Shared JSON marshaling with a configurable field-name casing.
+ * Every API and health response is encoded through these helpers so clients get one
+ * consistent contract: snake_case, camelCase, or the compatibility style that keeps
+ * the field names declared in struct tags. Only struct field names are rewritten;
+ * map keys are data (check names, headers) and are always preserved. Request bodies
+ * decoded through Decode accept the same casing the responses use.
+ */
+
+package jsoncase
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode"
+)
+
+// Style selects how struct field names are cased in JSON output
+type Style string
+
+// Supported casing styles
+const (
+	// StyleCompat keeps the names from struct tags, preserving the historical mixed casing
+	StyleCompat Style = "compat"
+	// StyleSnake emits snake_case field names
+	StyleSnake Style = "snake"
+	// StyleCamel emits camelCase field names
+	StyleCamel Style = "camel"
+)
+
+// serializationObserver is implemented by response writers that record encoding time
+type serializationObserver interface {
+	ObserveSerialization(time.Duration)
+}
+
+// defaultStyle is the process-wide style used by Marshal and Write
+var defaultStyle atomic.Value
+
+func init() {
+	defaultStyle.Store(StyleCompat)
+}
+
+/**
+ * @description Parses a style name, accepting an empty string as the compatibility style.
+ */
+func ParseStyle(name string) (Style, error) {
+	switch Style(strings.ToLower(strings.TrimSpace(name))) {
+	case "", StyleCompat:
+		return StyleCompat, nil
+	case StyleSnake, "snake_case":
+		return StyleSnake, nil
+	case StyleCamel, "camelcase":
+		return StyleCamel, nil
+	default:
+		return "", fmt.Errorf("unknown JSON field casing %q (expected compat, snake, or camel)", name)
+	}
+}
+
+/**
+ * @description Sets the process-wide style; call once during startup.
+ */
+func SetDefault(style Style) {
+	defaultStyle.Store(style)
+}
+
+/**
+ * @description Returns the process-wide style.
+ */
+func Default() Style {
+	return defaultStyle.Load().(Style)
+}
+
+/**
+ * @description Marshals v using the process-wide style.
+ */
+func Marshal(v any) ([]byte, error) {
+	return MarshalStyle(v, Default())
+}
+
+/**
+ * @description Marshals v, renaming struct fields according to style.
+ */
+func MarshalStyle(v any, style Style) ([]byte, error) {
+	if style == StyleCompat {
+		return json.Marshal(v)
+	}
+	return json.Marshal(convert(reflect.ValueOf(v), style))
+}
+
+/**
+ * @description Writes v as a JSON response using the process-wide style.
+ * Encodes before writing the header so an encoding failure still produces a single 500,
+ * and reports encoding time to writers that record it.
+ */
+func Write(w http.ResponseWriter, status int, v any) error {
+	return WriteAs(w, status, "application/json", v)
+}
+
+/**
+ * @description Writes v like Write but with the given Content-Type, such as a JSON-based
+ * media type like application/problem+json.
+ */
+func WriteAs(w http.ResponseWriter, status int, contentType string, v any) error {
+	start := time.Now()
+	body, err := Marshal(v)
+	if observer, ok := w.(serializationObserver); ok {
+		observer.ObserveSerialization(time.Since(start))
+	}
+	if err != nil {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, `{"type":"about:blank","title":"Internal Server Error","status":500,"detail":"failed to encode response"}`)
+		return err
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	_, err = w.Write(append(body, '\n'))
+	return err
+}
+
+/**
+ * @description Decodes the next JSON value from r into v, a pointer, accepting struct field
+ * names in the process-wide style.
+ */
+func Decode(r io.Reader, v any) error {
+	return DecodeStyle(r, v, Default())
+}
+
+/**
+ * @description Decodes the next JSON value from r into v, renaming struct fields written in
+ * style back to the names in their tags. Names that match no field are passed through, so
+ * field names in their tag casing are still accepted.
+ */
+func DecodeStyle(r io.Reader, v any, style Style) error {
+	decoder := json.NewDecoder(r)
+	if style == StyleCompat {
+		return decoder.Decode(v)
+	}
+	target := reflect.TypeOf(v)
+	if target == nil || target.Kind() != reflect.Pointer {
+		return &json.InvalidUnmarshalError{Type: target}
+	}
+
+	var decoded any
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return err
+	}
+	renamed, err := json.Marshal(rename(decoded, target.Elem(), style))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(renamed, v)
+}
+
+// orderedObject preserves struct field order when re-encoding
+type orderedObject struct {
+	keys   []string
+	values []any
+}
+
+func (o orderedObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedKey, _ := json.Marshal(key)
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		encodedValue, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(encodedValue)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+var (
+	marshalerType     = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// convert walks v and returns an equivalent value whose struct fields are renamed
+func convert(v reflect.Value, style Style) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Type().Implements(marshalerType) || v.Type().Implements(textMarshalerType) {
+		return v.Interface()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return convert(v.Elem(), style)
+	case reflect.Struct:
+		object := orderedObject{}
+		appendFields(&object, v, style)
+		return object
+	case reflect.Map:
+		if v.IsNil() || v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		converted := make(map[string]any, v.Len())
+		for _, key := range v.MapKeys() {
+			converted[key.String()] = convert(v.MapIndex(key), style)
+		}
+		return converted
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && (v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8) {
+			return v.Interface()
+		}
+		converted := make([]any, v.Len())
+		for i := range converted {
+			converted[i] = convert(v.Index(i), style)
+		}
+		return converted
+	default:
+		return v.Interface()
+	}
+}
+
+var (
+	unmarshalerType     = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// decodedField is a struct field's tag name and type, keyed by its name in a style
+type decodedField struct {
+	name      string
+	fieldType reflect.Type
+}
+
+// rename walks a decoded JSON value and renames the object keys of struct type t from style
+// to their tag names; values decoded by custom unmarshalers are left untouched
+func rename(value any, t reflect.Type, style Style) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) || reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return value
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		fields := make(map[string]decodedField)
+		collectFields(fields, t, style)
+		renamed := make(map[string]any, len(object))
+		for key, item := range object {
+			if field, ok := fields[key]; ok {
+				renamed[field.name] = rename(item, field.fieldType, style)
+			} else {
+				renamed[key] = item
+			}
+		}
+		return renamed
+	case reflect.Map:
+		object, ok := value.(map[string]any)
+		if !ok || t.Key().Kind() != reflect.String {
+			return value
+		}
+		renamed := make(map[string]any, len(object))
+		for key, item := range object {
+			renamed[key] = rename(item, t.Elem(), style)
+		}
+		return renamed
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		renamed := make([]any, len(items))
+		for i, item := range items {
+			renamed[i] = rename(item, t.Elem(), style)
+		}
+		return renamed
+	default:
+		return value
+	}
+}
+
+// collectFields maps the styled names of struct type t's fields, flattening embedded structs
+// the way appendFields does
+func collectFields(fields map[string]decodedField, t reflect.Type, style Style) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		name, _, skip := parseTag(field)
+		if skip {
+			continue
+		}
+
+		fieldType := field.Type
+		if field.Anonymous && name == "" {
+			if fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if fieldType.Kind() == reflect.Struct {
+				collectFields(fields, fieldType, style)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[ConvertName(name, style)] = decodedField{name: name, fieldType: field.Type}
+	}
+}
+
+// appendFields adds the exported fields of struct v to object, flattening embedded structs
+func appendFields(object *orderedObject, v reflect.Value, style Style) {
+	structType := v.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+
+		name, omitEmpty, skip := parseTag(field)
+		if skip {
+			continue
+		}
+
+		value := v.Field(i)
+		if field.Anonymous && name == "" {
+			if value.Kind() == reflect.Pointer {
+				if value.IsNil() {
+					continue
+				}
+				value = value.Elem()
+			}
+			if value.Kind() == reflect.Struct {
+				appendFields(object, value, style)
+				continue
+			}
+		}
+		if omitEmpty && value.IsZero() {
+			continue
+		}
+		if omitEmpty && (value.Kind() == reflect.Map || value.Kind() == reflect.Slice) && value.Len() == 0 {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		object.keys = append(object.keys, ConvertName(name, style))
+		object.values = append(object.values, convert(value, style))
+	}
+}
+
+// parseTag returns the JSON name from a field's tag and whether it is omitempty or skipped
+func parseTag(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, options, _ := strings.Cut(tag, ",")
+	return name, strings.Contains(","+options+",", ",omitempty,"), false
+}
+
+/**
+ * @description Converts a field name such as "build_date", "BuildDate", or "HTTPStatus" to the style.
+ */
+func ConvertName(name string, style Style) string {
+	if style == StyleCompat {
+		return name
+	}
+
+	words := splitWords(name)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+
+	if style == StyleSnake {
+		return strings.Join(words, "_")
+	}
+
+	for i := 1; i < len(words); i++ {
+		if words[i] != "" {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// splitWords splits on underscores, hyphens, and case boundaries, keeping acronyms together
+func splitWords(name string) []string {
+	var words []string
+	runes := []rune(name)
+	start := 0
+
+	flush := func(end int) {
+		if end > start {
+			words = append(words, string(runes[start:end]))
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case r == '_' || r == '-':
+			flush(i)
+			start = i + 1
+		case i > start && unicode.IsUpper(r):
+			previousLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if previousLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				flush(i)
+				start = i
+			}
+		}
+	}
+	flush(len(runes))
+	return words
+}
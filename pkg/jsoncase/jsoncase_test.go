@@ -0,0 +1,244 @@
+package jsoncase
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type embedded struct {
+	TraceID string `json:"trace_id"`
+}
+
+type sample struct {
+	embedded
+	BuildDate  string            `json:"build_date"`
+	HTTPStatus int               `json:"HTTPStatus"`
+	Checks     map[string]string `json:"checks,omitempty"`
+	Items      []item            `json:"items,omitempty"`
+	Started    time.Time         `json:"started_at"`
+	Secret     string            `json:"-"`
+	Untagged   bool
+}
+
+type item struct {
+	ItemName string `json:"itemName"`
+}
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Style
+		wantErr bool
+	}{
+		{name: "", want: StyleCompat},
+		{name: "compat", want: StyleCompat},
+		{name: " Snake ", want: StyleSnake},
+		{name: "snake_case", want: StyleSnake},
+		{name: "camel", want: StyleCamel},
+		{name: "camelCase", want: StyleCamel},
+		{name: "kebab", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseStyle(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseStyle(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseStyle(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestConvertName(t *testing.T) {
+	tests := []struct {
+		name      string
+		wantSnake string
+		wantCamel string
+	}{
+		{name: "build_date", wantSnake: "build_date", wantCamel: "buildDate"},
+		{name: "BuildDate", wantSnake: "build_date", wantCamel: "buildDate"},
+		{name: "buildDate", wantSnake: "build_date", wantCamel: "buildDate"},
+		{name: "HTTPStatus", wantSnake: "http_status", wantCamel: "httpStatus"},
+		{name: "requestID", wantSnake: "request_id", wantCamel: "requestId"},
+		{name: "p99-latency", wantSnake: "p99_latency", wantCamel: "p99Latency"},
+		{name: "version2Name", wantSnake: "version2_name", wantCamel: "version2Name"},
+		{name: "status", wantSnake: "status", wantCamel: "status"},
+	}
+
+	for _, tt := range tests {
+		if got := ConvertName(tt.name, StyleSnake); got != tt.wantSnake {
+			t.Errorf("ConvertName(%q, snake) = %q, want %q", tt.name, got, tt.wantSnake)
+		}
+		if got := ConvertName(tt.name, StyleCamel); got != tt.wantCamel {
+			t.Errorf("ConvertName(%q, camel) = %q, want %q", tt.name, got, tt.wantCamel)
+		}
+		if got := ConvertName(tt.name, StyleCompat); got != tt.name {
+			t.Errorf("ConvertName(%q, compat) = %q, want it unchanged", tt.name, got)
+		}
+	}
+}
+
+func TestMarshalStyle(t *testing.T) {
+	value := sample{
+		embedded:   embedded{TraceID: "t1"},
+		BuildDate:  "2024-03-01",
+		HTTPStatus: 200,
+		Checks:     map[string]string{"db_primary": "ok"},
+		Items:      []item{{ItemName: "a"}},
+		Started:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Secret:     "hidden",
+		Untagged:   true,
+	}
+
+	tests := []struct {
+		style Style
+		want  string
+	}{
+		{
+			style: StyleCompat,
+			want:  `{"trace_id":"t1","build_date":"2024-03-01","HTTPStatus":200,"checks":{"db_primary":"ok"},"items":[{"itemName":"a"}],"started_at":"2024-03-01T12:00:00Z","Untagged":true}`,
+		},
+		{
+			style: StyleSnake,
+			want:  `{"trace_id":"t1","build_date":"2024-03-01","http_status":200,"checks":{"db_primary":"ok"},"items":[{"item_name":"a"}],"started_at":"2024-03-01T12:00:00Z","untagged":true}`,
+		},
+		{
+			style: StyleCamel,
+			want:  `{"traceId":"t1","buildDate":"2024-03-01","httpStatus":200,"checks":{"db_primary":"ok"},"items":[{"itemName":"a"}],"startedAt":"2024-03-01T12:00:00Z","untagged":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := MarshalStyle(value, tt.style)
+		if err != nil {
+			t.Fatalf("MarshalStyle(%s) error = %v", tt.style, err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("MarshalStyle(%s) =\n%s\nwant\n%s", tt.style, got, tt.want)
+		}
+	}
+}
+
+func TestMarshalStyleOmitsEmpty(t *testing.T) {
+	got, err := MarshalStyle(&sample{Checks: map[string]string{}}, StyleCamel)
+	if err != nil {
+		t.Fatalf("MarshalStyle() error = %v", err)
+	}
+	for _, name := range []string{"checks", "items"} {
+		if strings.Contains(string(got), `"`+name+`"`) {
+			t.Errorf("MarshalStyle() = %s, want %s omitted", got, name)
+		}
+	}
+}
+
+func TestDecodeStyle(t *testing.T) {
+	want := sample{
+		embedded:   embedded{TraceID: "t1"},
+		BuildDate:  "2024-03-01",
+		HTTPStatus: 200,
+		Checks:     map[string]string{"db_primary": "ok"},
+		Items:      []item{{ItemName: "a"}},
+		Started:    time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		Untagged:   true,
+	}
+
+	tests := []struct {
+		name  string
+		style Style
+		body  string
+	}{
+		{
+			name:  "compat",
+			style: StyleCompat,
+			body:  `{"trace_id":"t1","build_date":"2024-03-01","HTTPStatus":200,"checks":{"db_primary":"ok"},"items":[{"itemName":"a"}],"started_at":"2024-03-01T12:00:00Z","Untagged":true}`,
+		},
+		{
+			name:  "snake",
+			style: StyleSnake,
+			body:  `{"trace_id":"t1","build_date":"2024-03-01","http_status":200,"checks":{"db_primary":"ok"},"items":[{"item_name":"a"}],"started_at":"2024-03-01T12:00:00Z","untagged":true}`,
+		},
+		{
+			name:  "camel",
+			style: StyleCamel,
+			body:  `{"traceId":"t1","buildDate":"2024-03-01","httpStatus":200,"checks":{"db_primary":"ok"},"items":[{"itemName":"a"}],"startedAt":"2024-03-01T12:00:00Z","untagged":true}`,
+		},
+		{
+			name:  "camel accepts tag names",
+			style: StyleCamel,
+			body:  `{"trace_id":"t1","build_date":"2024-03-01","HTTPStatus":200,"checks":{"db_primary":"ok"},"items":[{"itemName":"a"}],"started_at":"2024-03-01T12:00:00Z","Untagged":true}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got sample
+			if err := DecodeStyle(strings.NewReader(tt.body), &got, tt.style); err != nil {
+				t.Fatalf("DecodeStyle() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("DecodeStyle() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeStyleErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		target any
+	}{
+		{name: "non-pointer target", body: `{}`, target: sample{}},
+		{name: "nil target", body: `{}`, target: nil},
+		{name: "malformed JSON", body: `{"buildDate":`, target: &sample{}},
+		{name: "wrong type", body: `{"httpStatus":"ok"}`, target: &sample{}},
+	}
+
+	for _, tt := range tests {
+		if err := DecodeStyle(strings.NewReader(tt.body), tt.target, StyleCamel); err == nil {
+			t.Errorf("%s: DecodeStyle() error = nil, want an error", tt.name)
+		}
+	}
+}
+
+func TestWriteUsesDefaultStyle(t *testing.T) {
+	t.Cleanup(func() { SetDefault(StyleCompat) })
+	SetDefault(StyleCamel)
+
+	recorder := httptest.NewRecorder()
+	if err := Write(recorder, http.StatusCreated, item{ItemName: "a"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusCreated)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+	if got := recorder.Body.String(); got != "{\"itemName\":\"a\"}\n" {
+		t.Errorf("body = %q", got)
+	}
+
+	var decoded item
+	if err := Decode(strings.NewReader(`{"itemName":"b"}`), &decoded); err != nil || decoded.ItemName != "b" {
+		t.Errorf("Decode() = %+v, %v; want itemName b", decoded, err)
+	}
+}
+
+func TestWriteEncodingFailure(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	if err := Write(recorder, http.StatusOK, map[string]any{"bad": make(chan int)}); err == nil {
+		t.Fatal("Write() error = nil, want the encoding error")
+	}
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusInternalServerError)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "application/problem+json" {
+		t.Errorf("Content-Type = %q, want application/problem+json", got)
+	}
+}
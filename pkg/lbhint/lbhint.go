@@ -0,0 +1,87 @@
+/**
+ * @fileoverview Load-balancer hint headers.
+ * Annotates every response with this replica's current saturation and drain state so
+ * smart clients and L7 proxies can steer traffic toward less loaded replicas and away
+ * from ones that are shutting down.
+ */
+
+package lbhint
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+)
+
+// Hint headers added to responses
+const (
+	// InFlightHeader is the number of requests being served or queued, including this one
+	InFlightHeader = "X-Server-In-Flight"
+	// LoadHeader is in-flight requests as a fraction of capacity; values above 1 mean requests are queueing
+	LoadHeader = "X-Server-Load"
+	// DrainHeader is "true" while the replica is draining and should receive no new traffic
+	DrainHeader = "X-Drain"
+)
+
+// Hints tracks in-flight requests and drain state for hint headers
+type Hints struct {
+	capacity int
+	inFlight atomic.Int64
+	draining atomic.Bool
+}
+
+/**
+ * @description Creates hints for a replica that serves capacity concurrent requests;
+ * a non-positive capacity omits the X-Server-Load header.
+ */
+func New(capacity int) *Hints {
+	return &Hints{capacity: capacity}
+}
+
+/**
+ * @description Marks the replica as draining (or no longer draining).
+ */
+func (h *Hints) SetDraining(draining bool) {
+	h.draining.Store(draining)
+}
+
+/**
+ * @description Reports whether the replica is draining.
+ */
+func (h *Hints) Draining() bool {
+	return h.draining.Load()
+}
+
+/**
+ * @description Returns in-flight requests as a fraction of capacity, or 0 when capacity is unknown.
+ */
+func (h *Hints) Load() float64 {
+	if h.capacity <= 0 {
+		return 0
+	}
+	return float64(h.inFlight.Load()) / float64(h.capacity)
+}
+
+/**
+ * @description Wraps next so responses carry the hint headers. Install outside any
+ * concurrency limiter so queued requests count toward the load. While draining, responses
+ * also ask the client to close the connection so keep-alive traffic moves elsewhere.
+ */
+func (h *Hints) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inFlight := h.inFlight.Add(1)
+		defer h.inFlight.Add(-1)
+
+		header := w.Header()
+		header.Set(InFlightHeader, strconv.FormatInt(inFlight, 10))
+		if h.capacity > 0 {
+			header.Set(LoadHeader, strconv.FormatFloat(float64(inFlight)/float64(h.capacity), 'f', 2, 64))
+		}
+		if h.Draining() {
+			header.Set(DrainHeader, "true")
+			header.Set("Connection", "close")
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
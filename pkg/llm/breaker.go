@@ -0,0 +1,97 @@
+/**
+ * @fileoverview Circuit breaker for LLM providers.
+ * Consecutive retryable failures open the breaker so calls stop reaching a provider that
+ * is down; after a cooldown a single probe call is let through, closing the breaker when
+ * it succeeds and reopening it for another cooldown when it fails.
+ */
+
+package llm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+// DefaultBreakerCooldown is how long an open breaker rejects calls before probing the provider
+const DefaultBreakerCooldown = 30 * time.Second
+
+// breakerOpens counts transitions of provider breakers to open
+var breakerOpens = metrics.NewCounterVec("llm_breaker_opens_total", "Times a provider's circuit breaker opened, by provider.", "provider")
+
+// Breaker tracks consecutive failures of one provider
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+/**
+ * @description Creates a breaker for the named provider that opens after threshold consecutive
+ * failures; a non-positive cooldown uses DefaultBreakerCooldown.
+ */
+func NewBreaker(name string, threshold int, cooldown time.Duration) *Breaker {
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &Breaker{name: name, threshold: max(threshold, 1), cooldown: cooldown, now: time.Now}
+}
+
+/**
+ * @description Reports whether a call may proceed: always while closed, and for a single
+ * probe once an open breaker's cooldown has passed.
+ */
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+/**
+ * @description Records a successful call, closing the breaker.
+ */
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures, b.probing = 0, false
+}
+
+/**
+ * @description Records a failed call, opening the breaker at the threshold and restarting the
+ * cooldown when a probe fails.
+ */
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.failures >= b.threshold {
+		if b.failures == b.threshold {
+			breakerOpens.With(b.name).Add(1)
+		}
+		b.openedAt = b.now()
+	}
+}
+
+/**
+ * @description Reports whether the breaker is open, including while a probe is in flight.
+ */
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.failures >= b.threshold
+}
@@ -0,0 +1,198 @@
+/**
+ * @fileoverview Degraded-mode responses for providers behind an open circuit breaker.
+ * FallbackProvider wraps a provider with a Breaker. While the breaker is open, completions
+ * are answered from the first available fallback, in order: the last answer cached for an
+ * identical request, the same request on an alternate model, or a canned message. Fallback
+ * responses name the fallback used, so endpoints can flag them as degraded.
+ */
+
+package llm
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+// Fallbacks, in the order they are tried
+const (
+	FallbackCached  = "cached"
+	FallbackModel   = "alternate_model"
+	FallbackMessage = "canned_message"
+)
+
+// DefaultFallbackCacheSize is the number of answers kept for FallbackCached
+const DefaultFallbackCacheSize = 100
+
+// degradedResponses counts completions answered by a fallback
+var degradedResponses = metrics.NewCounterVec("llm_degraded_responses_total",
+	"Completions answered by a fallback while the provider's circuit breaker was open, by fallback.", "fallback")
+
+// FallbackConfig selects the fallbacks used while the breaker is open
+type FallbackConfig struct {
+	Breaker *Breaker
+	// Cache replays the last successful answer to an identical request
+	Cache     bool
+	CacheSize int
+	// Model is tried on the same provider, bypassing the breaker, e.g. a cheaper model
+	Model string
+	// Message is answered when no other fallback succeeds
+	Message string
+}
+
+// FallbackProvider answers from fallbacks while its provider's breaker is open
+type FallbackProvider struct {
+	provider Provider
+	config   FallbackConfig
+
+	mu     sync.Mutex
+	cached map[string]Response
+	order  []string
+}
+
+/**
+ * @description Wraps provider with config's breaker and fallbacks.
+ */
+func NewFallbackProvider(provider Provider, config FallbackConfig) *FallbackProvider {
+	if config.CacheSize <= 0 {
+		config.CacheSize = DefaultFallbackCacheSize
+	}
+	return &FallbackProvider{provider: provider, config: config, cached: make(map[string]Response)}
+}
+
+/**
+ * @description Returns the wrapped provider's name.
+ */
+func (f *FallbackProvider) Name() string {
+	return f.provider.Name()
+}
+
+/**
+ * @description Completes req with the provider while the breaker allows it, falling back
+ * once the breaker is open, including when this call's failure opened it.
+ */
+func (f *FallbackProvider) Complete(ctx context.Context, req Request) (Response, error) {
+	if !f.config.Breaker.Allow() {
+		return f.fallback(ctx, req, nil)
+	}
+	response, err := f.provider.Complete(ctx, req)
+	f.observe(err)
+	if err == nil {
+		f.remember(req, response)
+		return response, nil
+	}
+	if !IsRetryable(err) || !f.config.Breaker.Open() {
+		return response, err
+	}
+	return f.fallback(ctx, req, err)
+}
+
+/**
+ * @description Streams req from the provider while the breaker allows it. While it is open,
+ * or when the stream fails before its first chunk and opens it, the fallback answer is
+ * emitted as a single chunk naming the fallback.
+ */
+func (f *FallbackProvider) Stream(ctx context.Context, req Request, emit func(Chunk) error) error {
+	var cause error
+	if f.config.Breaker.Allow() {
+		emitted := false
+		cause = f.provider.Stream(ctx, req, func(chunk Chunk) error {
+			emitted = true
+			return emit(chunk)
+		})
+		f.observe(cause)
+		if cause == nil || emitted || !IsRetryable(cause) || !f.config.Breaker.Open() {
+			return cause
+		}
+	}
+
+	response, err := f.fallback(ctx, req, cause)
+	if err != nil {
+		return err
+	}
+	return emit(Chunk{Delta: response.Content, FinishReason: response.FinishReason, Fallback: response.Fallback})
+}
+
+// observe feeds a call's outcome to the breaker; only retryable errors count as failures,
+// since any other outcome shows the provider is reachable
+func (f *FallbackProvider) observe(err error) {
+	if IsRetryable(err) {
+		f.config.Breaker.Failure()
+	} else {
+		f.config.Breaker.Success()
+	}
+}
+
+// fallback answers req from the first available fallback, or returns an unavailable error
+// wrapping cause when none applies
+func (f *FallbackProvider) fallback(ctx context.Context, req Request, cause error) (Response, error) {
+	if f.config.Cache {
+		if response, ok := f.lookup(req); ok {
+			return degraded(response, FallbackCached), nil
+		}
+	}
+	if f.config.Model != "" && f.config.Model != req.Model {
+		alternate := req
+		alternate.Model = f.config.Model
+		if response, err := f.provider.Complete(ctx, alternate); err == nil {
+			degradedResponses.With(FallbackModel).Add(1)
+			response.Fallback = FallbackModel
+			return response, nil
+		}
+	}
+	if f.config.Message != "" {
+		return degraded(Response{Model: req.Model, Content: f.config.Message, FinishReason: "stop"}, FallbackMessage), nil
+	}
+
+	message := "circuit breaker is open"
+	if cause != nil {
+		message += ": " + cause.Error()
+	}
+	return Response{}, &ProviderError{Provider: f.provider.Name(), Kind: ErrorUnavailable, Message: message}
+}
+
+// degraded marks a response produced without calling the provider, which consumed no tokens
+func degraded(response Response, fallback string) Response {
+	degradedResponses.With(fallback).Add(1)
+	response.Fallback = fallback
+	response.Usage = Usage{}
+	return response
+}
+
+// remember caches the answer to req, evicting the oldest once the cache is full
+func (f *FallbackProvider) remember(req Request, response Response) {
+	if !f.config.Cache {
+		return
+	}
+	key := requestKey(req)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, exists := f.cached[key]; !exists {
+		f.order = append(f.order, key)
+	}
+	f.cached[key] = response
+	if len(f.order) > f.config.CacheSize {
+		delete(f.cached, f.order[0])
+		f.order = f.order[1:]
+	}
+}
+
+// lookup returns the cached answer to req
+func (f *FallbackProvider) lookup(req Request) (Response, bool) {
+	key := requestKey(req)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	response, ok := f.cached[key]
+	return response, ok
+}
+
+// requestKey identifies identical requests by a hash of their encoding
+func requestKey(req Request) string {
+	encoded, _ := json.Marshal(req)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,151 @@
+/**
+ * @fileoverview Provider-agnostic interface for large language model completions.
+ * Providers register a factory under a name so the provider used by the API can be
+ * selected from configuration, with typed errors that callers can retry on.
+ */
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Message roles
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is one turn in a conversation
+type Message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Request asks a provider for a completion
+type Request struct {
+	Model       string    `json:"model"`
+	Messages    []Message `json:"messages"`
+	MaxTokens   int       `json:"max_tokens,omitempty"`
+	Temperature float64   `json:"temperature,omitempty"`
+}
+
+// Usage reports token consumption
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+// Response is a complete (non-streamed) completion
+type Response struct {
+	Model        string `json:"model"`
+	Content      string `json:"content"`
+	FinishReason string `json:"finish_reason"`
+	Usage        Usage  `json:"usage"`
+	// Fallback names the degraded-mode fallback that produced the response, if any
+	Fallback string `json:"fallback,omitempty"`
+}
+
+// Chunk is one increment of a streamed completion; FinishReason is set on the last chunk
+type Chunk struct {
+	Delta        string `json:"delta"`
+	FinishReason string `json:"finish_reason,omitempty"`
+	// Fallback names the degraded-mode fallback that produced the chunk, if any
+	Fallback string `json:"fallback,omitempty"`
+}
+
+// Provider generates completions
+type Provider interface {
+	// Name returns the registered provider name
+	Name() string
+	// Complete returns the full completion for req
+	Complete(ctx context.Context, req Request) (Response, error)
+	// Stream delivers the completion incrementally; returning an error from emit stops the stream
+	Stream(ctx context.Context, req Request, emit func(Chunk) error) error
+}
+
+// Error kinds reported by providers
+const (
+	ErrorRateLimited = "rate_limited"
+	ErrorUnavailable = "unavailable"
+	ErrorTimeout     = "timeout"
+	ErrorInvalid     = "invalid_request"
+)
+
+// ProviderError is a typed provider failure
+type ProviderError struct {
+	Provider string
+	Kind     string
+	Message  string
+}
+
+func (e *ProviderError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Provider, e.Kind, e.Message)
+}
+
+/**
+ * @description Reports whether the request may succeed if retried later.
+ */
+func (e *ProviderError) Retryable() bool {
+	return e.Kind == ErrorRateLimited || e.Kind == ErrorUnavailable || e.Kind == ErrorTimeout
+}
+
+/**
+ * @description Reports whether err is a retryable *ProviderError.
+ */
+func IsRetryable(err error) bool {
+	var providerErr *ProviderError
+	return errors.As(err, &providerErr) && providerErr.Retryable()
+}
+
+// Factory builds a provider from string options taken from configuration
+type Factory func(options map[string]string) (Provider, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+/**
+ * @description Registers a provider factory under name; panics on duplicates.
+ */
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic(fmt.Sprintf("llm: provider %q registered twice", name))
+	}
+	registry[name] = factory
+}
+
+/**
+ * @description Creates the named provider with options.
+ */
+func New(name string, options map[string]string) (Provider, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown LLM provider %q (available: %v)", name, Providers())
+	}
+	return factory(options)
+}
+
+/**
+ * @description Returns the registered provider names, sorted.
+ */
+func Providers() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
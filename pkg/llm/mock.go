@@ -0,0 +1,187 @@
+/**
+ * @fileoverview Built-in mock provider for development and tests.
+ * Returns deterministic or scripted completions with simulated streaming and failure
+ * modes, so the API works end-to-end without external API keys.
+ */
+
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MockProviderName is the registered name of the mock provider
+const MockProviderName = "mock"
+
+// Mock modes
+const (
+	// MockModeEcho answers with a deterministic reply derived from the last user message
+	MockModeEcho = "echo"
+	// MockModeScripted answers with Responses in order, cycling when exhausted
+	MockModeScripted = "scripted"
+)
+
+// MockProvider implements Provider without network access
+type MockProvider struct {
+	// Mode is MockModeEcho (default) or MockModeScripted
+	Mode      string
+	Responses []string
+	// ChunkDelay is the pause between streamed words
+	ChunkDelay time.Duration
+	// FailWith makes every call fail with this error kind (e.g. ErrorRateLimited)
+	FailWith string
+	// FailAfterChunks makes Stream fail with ErrorUnavailable after this many chunks; zero disables it
+	FailAfterChunks int
+
+	mu   sync.Mutex
+	next int
+}
+
+func init() {
+	Register(MockProviderName, NewMockProvider)
+}
+
+/**
+ * @description Builds a mock provider from options: mode (echo|scripted), responses
+ * ("|"-separated), chunk_delay_ms, fail_with, and fail_after_chunks.
+ */
+func NewMockProvider(options map[string]string) (Provider, error) {
+	mock := &MockProvider{Mode: options["mode"], FailWith: options["fail_with"]}
+	if mock.Mode == "" {
+		mock.Mode = MockModeEcho
+	}
+	if mock.Mode != MockModeEcho && mock.Mode != MockModeScripted {
+		return nil, fmt.Errorf("mock provider: unknown mode %q (expected echo or scripted)", mock.Mode)
+	}
+	if responses := options["responses"]; responses != "" {
+		mock.Responses = strings.Split(responses, "|")
+	}
+	if mock.Mode == MockModeScripted && len(mock.Responses) == 0 {
+		return nil, fmt.Errorf("mock provider: scripted mode requires responses")
+	}
+	if delay := options["chunk_delay_ms"]; delay != "" {
+		ms, err := strconv.Atoi(delay)
+		if err != nil || ms < 0 {
+			return nil, fmt.Errorf("mock provider: invalid chunk_delay_ms %q", delay)
+		}
+		mock.ChunkDelay = time.Duration(ms) * time.Millisecond
+	}
+	if failAfter := options["fail_after_chunks"]; failAfter != "" {
+		n, err := strconv.Atoi(failAfter)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("mock provider: invalid fail_after_chunks %q", failAfter)
+		}
+		mock.FailAfterChunks = n
+	}
+	return mock, nil
+}
+
+/**
+ * @description Returns "mock".
+ */
+func (m *MockProvider) Name() string {
+	return MockProviderName
+}
+
+/**
+ * @description Returns the mock completion, or the configured failure.
+ */
+func (m *MockProvider) Complete(ctx context.Context, req Request) (Response, error) {
+	if err := m.failure(); err != nil {
+		return Response{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return Response{}, &ProviderError{Provider: MockProviderName, Kind: ErrorTimeout, Message: err.Error()}
+	}
+
+	content := m.reply(req)
+	return Response{
+		Model:        m.model(req),
+		Content:      content,
+		FinishReason: "stop",
+		Usage:        Usage{PromptTokens: promptTokens(req), CompletionTokens: len(strings.Fields(content))},
+	}, nil
+}
+
+/**
+ * @description Streams the mock completion word by word, honouring ChunkDelay and FailAfterChunks.
+ */
+func (m *MockProvider) Stream(ctx context.Context, req Request, emit func(Chunk) error) error {
+	if err := m.failure(); err != nil {
+		return err
+	}
+
+	words := strings.Fields(m.reply(req))
+	for i, word := range words {
+		if m.FailAfterChunks > 0 && i == m.FailAfterChunks {
+			return &ProviderError{Provider: MockProviderName, Kind: ErrorUnavailable, Message: "simulated mid-stream failure"}
+		}
+		if m.ChunkDelay > 0 && i > 0 {
+			select {
+			case <-ctx.Done():
+				return &ProviderError{Provider: MockProviderName, Kind: ErrorTimeout, Message: ctx.Err().Error()}
+			case <-time.After(m.ChunkDelay):
+			}
+		}
+
+		chunk := Chunk{Delta: word}
+		if i > 0 {
+			chunk.Delta = " " + word
+		}
+		if i == len(words)-1 {
+			chunk.FinishReason = "stop"
+		}
+		if err := emit(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reply chooses the completion text for req
+func (m *MockProvider) reply(req Request) string {
+	if m.Mode == MockModeScripted {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		reply := m.Responses[m.next%len(m.Responses)]
+		m.next++
+		return reply
+	}
+
+	for i := len(req.Messages) - 1; i >= 0; i-- {
+		if req.Messages[i].Role == RoleUser {
+			return "Mock response to: " + req.Messages[i].Content
+		}
+	}
+	return "Mock response."
+}
+
+// failure returns the configured simulated error, if any
+func (m *MockProvider) failure() error {
+	if m.FailWith == "" {
+		return nil
+	}
+	return &ProviderError{Provider: MockProviderName, Kind: m.FailWith, Message: "simulated failure"}
+}
+
+// model echoes the requested model name, defaulting to "mock"
+func (m *MockProvider) model(req Request) string {
+	if req.Model != "" {
+		return req.Model
+	}
+	return MockProviderName
+}
+
+// promptTokens approximates prompt size as a word count
+func promptTokens(req Request) int {
+	count := 0
+	for _, message := range req.Messages {
+		count += len(strings.Fields(message.Content))
+	}
+	return count
+}
@@ -0,0 +1,88 @@
+/**
+ * @fileoverview Model registry with per-model pricing tables.
+ * Prices are in USD per million prompt and completion tokens, so the estimated cost of a
+ * completion can be computed from the usage reported by its provider.
+ */
+
+package llm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Pricing is a model's price in USD per million tokens
+type Pricing struct {
+	Prompt     float64 `json:"prompt_per_million"`
+	Completion float64 `json:"completion_per_million"`
+}
+
+/**
+ * @description Returns the estimated cost in USD of usage at these prices.
+ */
+func (p Pricing) Cost(usage Usage) float64 {
+	return (float64(usage.PromptTokens)*p.Prompt + float64(usage.CompletionTokens)*p.Completion) / 1e6
+}
+
+// ModelRegistry holds the pricing of known models; it is read-only once created
+type ModelRegistry struct {
+	prices map[string]Pricing
+}
+
+/**
+ * @description Creates a registry with the given pricing per model name.
+ */
+func NewModelRegistry(prices map[string]Pricing) *ModelRegistry {
+	registry := &ModelRegistry{prices: make(map[string]Pricing, len(prices))}
+	for model, pricing := range prices {
+		registry.prices[model] = pricing
+	}
+	return registry
+}
+
+/**
+ * @description Returns the pricing of model; ok is false for models without a price.
+ */
+func (r *ModelRegistry) Pricing(model string) (pricing Pricing, ok bool) {
+	pricing, ok = r.prices[model]
+	return pricing, ok
+}
+
+/**
+ * @description Parses "model=prompt:completion" entries in USD per million tokens, e.g.
+ * "gpt-4o-mini=0.15:0.60". Each model may appear only once.
+ */
+func ParsePricing(entries []string) (map[string]Pricing, error) {
+	prices := make(map[string]Pricing, len(entries))
+	for _, entry := range entries {
+		model, rates, ok := strings.Cut(entry, "=")
+		prompt, completion, hasBoth := strings.Cut(rates, ":")
+		if !ok || model == "" || !hasBoth {
+			return nil, fmt.Errorf("invalid model pricing %q: expected model=prompt:completion", entry)
+		}
+		if _, exists := prices[model]; exists {
+			return nil, fmt.Errorf("model %s is priced more than once", model)
+		}
+		var pricing Pricing
+		var err error
+		if pricing.Prompt, err = parsePrice(prompt); err != nil {
+			return nil, fmt.Errorf("invalid prompt price in %q: %w", entry, err)
+		}
+		if pricing.Completion, err = parsePrice(completion); err != nil {
+			return nil, fmt.Errorf("invalid completion price in %q: %w", entry, err)
+		}
+		prices[model] = pricing
+	}
+	return prices, nil
+}
+
+// parsePrice parses a finite, non-negative price
+func parsePrice(value string) (float64, error) {
+	price, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || price < 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		return 0, fmt.Errorf("%q must be a non-negative number", value)
+	}
+	return price, nil
+}
@@ -0,0 +1,157 @@
+/**
+ * @fileoverview Per-request usage records with estimated cost.
+ * Each completion is recorded with its caller key, model, tokens, and cost estimated from
+ * the model registry's pricing. Totals are kept per key and model for the usage API, the
+ * most recent records are kept for inspection, and tokens and cost are exported as metrics.
+ */
+
+package llm
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+// DefaultUsageHistory is the number of recent usage records a UsageTracker keeps
+const DefaultUsageHistory = 100
+
+// Token and cost counters by model; cost is counted in millionths of a USD
+var (
+	promptTokenCount     = metrics.NewCounterVec("llm_prompt_tokens_total", "Prompt tokens consumed by completions, by model.", "model")
+	completionTokenCount = metrics.NewCounterVec("llm_completion_tokens_total", "Completion tokens generated, by model.", "model")
+	estimatedCostCount   = metrics.NewCounterVec("llm_estimated_cost_microusd_total",
+		"Estimated completion cost in millionths of a USD, by model; unpriced models count zero.", "model")
+)
+
+// UsageRecord is the usage of one completion
+type UsageRecord struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Key identifies the caller, e.g. a hash of its credentials
+	Key              string  `json:"key"`
+	Provider         string  `json:"provider"`
+	Model            string  `json:"model"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	// Priced is false when the model has no pricing, leaving CostUSD zero
+	Priced bool `json:"priced"`
+}
+
+// UsageTotal aggregates the usage of one caller key with one model
+type UsageTotal struct {
+	Key              string  `json:"key"`
+	Model            string  `json:"model"`
+	Requests         int64   `json:"requests"`
+	PromptTokens     int64   `json:"prompt_tokens"`
+	CompletionTokens int64   `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+}
+
+// UsageReport is served by the usage API
+type UsageReport struct {
+	Totals       []UsageTotal  `json:"totals"`
+	TotalCostUSD float64       `json:"total_cost_usd"`
+	Recent       []UsageRecord `json:"recent"`
+}
+
+// usageKey groups totals by caller key and model
+type usageKey struct {
+	key   string
+	model string
+}
+
+// UsageTracker records completions and their estimated cost
+type UsageTracker struct {
+	models *ModelRegistry
+	size   int
+
+	mu     sync.Mutex
+	totals map[usageKey]*UsageTotal
+	recent []UsageRecord
+}
+
+/**
+ * @description Creates a tracker pricing completions from models and keeping the last size
+ * records; non-positive sizes use DefaultUsageHistory.
+ */
+func NewUsageTracker(models *ModelRegistry, size int) *UsageTracker {
+	if size <= 0 {
+		size = DefaultUsageHistory
+	}
+	return &UsageTracker{models: models, size: size, totals: make(map[usageKey]*UsageTotal)}
+}
+
+/**
+ * @description Records a completion for key and returns its usage record with the estimated cost.
+ */
+func (t *UsageTracker) Record(key, provider, model string, usage Usage) UsageRecord {
+	record := UsageRecord{
+		Timestamp:        time.Now().UTC(),
+		Key:              key,
+		Provider:         provider,
+		Model:            model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+	}
+	if pricing, ok := t.models.Pricing(model); ok {
+		record.CostUSD, record.Priced = pricing.Cost(usage), true
+	}
+	promptTokenCount.With(model).Add(int64(usage.PromptTokens))
+	completionTokenCount.With(model).Add(int64(usage.CompletionTokens))
+	estimatedCostCount.With(model).Add(int64(math.Round(record.CostUSD * 1e6)))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	total, ok := t.totals[usageKey{key, model}]
+	if !ok {
+		total = &UsageTotal{Key: key, Model: model}
+		t.totals[usageKey{key, model}] = total
+	}
+	total.Requests++
+	total.PromptTokens += int64(usage.PromptTokens)
+	total.CompletionTokens += int64(usage.CompletionTokens)
+	total.CostUSD += record.CostUSD
+	t.recent = append(t.recent, record)
+	if len(t.recent) > t.size {
+		t.recent = t.recent[len(t.recent)-t.size:]
+	}
+	return record
+}
+
+/**
+ * @description Returns the totals per key and model, sorted by key then model, with the
+ * overall cost and the recent records, oldest first.
+ */
+func (t *UsageTracker) Report() UsageReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	report := UsageReport{
+		Totals: make([]UsageTotal, 0, len(t.totals)),
+		Recent: append([]UsageRecord{}, t.recent...),
+	}
+	for _, total := range t.totals {
+		report.Totals = append(report.Totals, *total)
+		report.TotalCostUSD += total.CostUSD
+	}
+	sort.Slice(report.Totals, func(i, j int) bool {
+		if report.Totals[i].Key != report.Totals[j].Key {
+			return report.Totals[i].Key < report.Totals[j].Key
+		}
+		return report.Totals[i].Model < report.Totals[j].Model
+	})
+	return report
+}
+
+/**
+ * @description Serves the usage report as JSON.
+ */
+func (t *UsageTracker) Handler(w http.ResponseWriter, r *http.Request) {
+	jsoncase.Write(w, http.StatusOK, t.Report())
+}
@@ -0,0 +1,63 @@
+/**
+ * @fileoverview Gauges for values that go up and down, such as in-flight requests.
+ */
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"sync/atomic"
+)
+
+// Gauge is a value that can increase and decrease
+type Gauge struct {
+	name  string
+	help  string
+	value atomic.Int64
+}
+
+/**
+ * @description Creates a gauge registered on the Default registry.
+ */
+func NewGauge(name, help string) *Gauge {
+	return Default.NewGauge(name, help)
+}
+
+/**
+ * @description Creates a gauge registered on r.
+ */
+func (r *Registry) NewGauge(name, help string) *Gauge {
+	gauge := &Gauge{name: name, help: help}
+	r.register(gauge)
+	return gauge
+}
+
+/**
+ * @description Changes the gauge by delta, which may be negative.
+ */
+func (g *Gauge) Add(delta int64) {
+	g.value.Add(delta)
+}
+
+/**
+ * @description Sets the gauge to value.
+ */
+func (g *Gauge) Set(value int64) {
+	g.value.Store(value)
+}
+
+/**
+ * @description Returns the current value.
+ */
+func (g *Gauge) Value() int64 {
+	return g.value.Load()
+}
+
+func (g *Gauge) describe() (string, string, string) {
+	return g.name, g.help, "gauge"
+}
+
+func (g *Gauge) writeSamples(w *bufio.Writer) {
+	fmt.Fprintf(w, "%s %d\n", g.name, g.value.Load())
+}
@@ -0,0 +1,120 @@
+/**
+ * @fileoverview Labelled histograms with fixed cumulative buckets.
+ */
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+var (
+	// DurationBuckets suit request and serialization latencies in seconds
+	DurationBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	// SizeBuckets suit payload sizes in bytes, from 256 B to 16 MiB
+	SizeBuckets = []float64{256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216}
+)
+
+// HistogramVec is a family of histograms partitioned by one label
+type HistogramVec struct {
+	name    string
+	help    string
+	label   string
+	buckets []float64
+
+	mu     sync.RWMutex
+	series map[string]*Histogram
+}
+
+// Histogram counts observations into cumulative buckets
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	sum     float64
+	count   uint64
+}
+
+/**
+ * @description Creates a histogram family registered on the Default registry.
+ */
+func NewHistogramVec(name, help, label string, buckets []float64) *HistogramVec {
+	return Default.NewHistogramVec(name, help, label, buckets)
+}
+
+/**
+ * @description Creates a histogram family registered on r; buckets must be sorted ascending.
+ */
+func (r *Registry) NewHistogramVec(name, help, label string, buckets []float64) *HistogramVec {
+	vec := &HistogramVec{name: name, help: help, label: label, buckets: buckets, series: make(map[string]*Histogram)}
+	r.register(vec)
+	return vec
+}
+
+/**
+ * @description Returns the histogram for a label value, creating it on first use.
+ */
+func (v *HistogramVec) With(labelValue string) *Histogram {
+	v.mu.RLock()
+	histogram, ok := v.series[labelValue]
+	v.mu.RUnlock()
+	if ok {
+		return histogram
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if histogram, ok = v.series[labelValue]; !ok {
+		histogram = &Histogram{buckets: v.buckets, counts: make([]uint64, len(v.buckets))}
+		v.series[labelValue] = histogram
+	}
+	return histogram
+}
+
+/**
+ * @description Records one observation.
+ */
+func (h *Histogram) Observe(value float64) {
+	i := sort.SearchFloat64s(h.buckets, value)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if i < len(h.counts) {
+		h.counts[i]++
+	}
+	h.sum += value
+	h.count++
+}
+
+func (v *HistogramVec) describe() (string, string, string) {
+	return v.name, v.help, "histogram"
+}
+
+func (v *HistogramVec) writeSamples(w *bufio.Writer) {
+	v.mu.RLock()
+	labels := make([]string, 0, len(v.series))
+	for label := range v.series {
+		labels = append(labels, label)
+	}
+	v.mu.RUnlock()
+	sort.Strings(labels)
+
+	for _, labelValue := range labels {
+		histogram := v.With(labelValue)
+		label := fmt.Sprintf(`%s="%s"`, v.label, escapeLabel(labelValue))
+
+		histogram.mu.Lock()
+		var cumulative uint64
+		for i, bound := range histogram.buckets {
+			cumulative += histogram.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", v.name, label, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", v.name, label, histogram.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", v.name, label, strconv.FormatFloat(histogram.sum, 'g', -1, 64))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", v.name, label, histogram.count)
+		histogram.mu.Unlock()
+	}
+}
@@ -0,0 +1,196 @@
+/**
+ * @fileoverview Minimal in-process metrics registry with Prometheus text exposition.
+ * Provides counters, gauges, and labelled histograms without pulling in a client library;
+ * every metric registers on a Registry (usually Default) and is served at /metrics.
+ */
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// collector is implemented by every metric type
+type collector interface {
+	describe() (name, help, kind string)
+	writeSamples(w *bufio.Writer)
+}
+
+// Registry holds named metrics for exposition
+type Registry struct {
+	mu         sync.RWMutex
+	collectors map[string]collector
+}
+
+// Default is the process-wide registry served by Handler
+var Default = NewRegistry()
+
+/**
+ * @description Creates an empty registry.
+ */
+func NewRegistry() *Registry {
+	return &Registry{collectors: make(map[string]collector)}
+}
+
+// register adds c, panicking on duplicate names like expvar.Publish
+func (r *Registry) register(c collector) {
+	name, _, _ := c.describe()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.collectors[name]; exists {
+		panic(fmt.Sprintf("metrics: duplicate metric %q", name))
+	}
+	r.collectors[name] = c
+}
+
+/**
+ * @description Writes every metric in the Prometheus text exposition format, sorted by name.
+ */
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.RLock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	collectors := make(map[string]collector, len(r.collectors))
+	for name, c := range r.collectors {
+		collectors[name] = c
+	}
+	r.mu.RUnlock()
+	sort.Strings(names)
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	out := bufio.NewWriter(w)
+	for _, name := range names {
+		_, help, kind := collectors[name].describe()
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+		collectors[name].writeSamples(out)
+	}
+	out.Flush()
+}
+
+/**
+ * @description Serves the Default registry.
+ */
+func Handler(w http.ResponseWriter, r *http.Request) {
+	Default.ServeHTTP(w, r)
+}
+
+// Counter is a monotonically increasing value
+type Counter struct {
+	name  string
+	help  string
+	value atomic.Int64
+}
+
+/**
+ * @description Creates a counter registered on the Default registry.
+ */
+func NewCounter(name, help string) *Counter {
+	return Default.NewCounter(name, help)
+}
+
+/**
+ * @description Creates a counter registered on r.
+ */
+func (r *Registry) NewCounter(name, help string) *Counter {
+	counter := &Counter{name: name, help: help}
+	r.register(counter)
+	return counter
+}
+
+/**
+ * @description Increases the counter by delta, which must not be negative.
+ */
+func (c *Counter) Add(delta int64) {
+	c.value.Add(delta)
+}
+
+/**
+ * @description Returns the current count.
+ */
+func (c *Counter) Value() int64 {
+	return c.value.Load()
+}
+
+func (c *Counter) describe() (string, string, string) {
+	return c.name, c.help, "counter"
+}
+
+func (c *Counter) writeSamples(w *bufio.Writer) {
+	fmt.Fprintf(w, "%s %d\n", c.name, c.value.Load())
+}
+
+// escapeLabel escapes a label value for the text format
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// CounterVec is a family of counters partitioned by one label
+type CounterVec struct {
+	name  string
+	help  string
+	label string
+
+	mu     sync.RWMutex
+	series map[string]*Counter
+}
+
+/**
+ * @description Creates a counter family registered on the Default registry.
+ */
+func NewCounterVec(name, help, label string) *CounterVec {
+	return Default.NewCounterVec(name, help, label)
+}
+
+/**
+ * @description Creates a counter family registered on r.
+ */
+func (r *Registry) NewCounterVec(name, help, label string) *CounterVec {
+	vec := &CounterVec{name: name, help: help, label: label, series: make(map[string]*Counter)}
+	r.register(vec)
+	return vec
+}
+
+/**
+ * @description Returns the counter for a label value, creating it on first use.
+ */
+func (v *CounterVec) With(labelValue string) *Counter {
+	v.mu.RLock()
+	counter, ok := v.series[labelValue]
+	v.mu.RUnlock()
+	if ok {
+		return counter
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if counter, ok = v.series[labelValue]; !ok {
+		counter = &Counter{name: v.name, help: v.help}
+		v.series[labelValue] = counter
+	}
+	return counter
+}
+
+func (v *CounterVec) describe() (string, string, string) {
+	return v.name, v.help, "counter"
+}
+
+func (v *CounterVec) writeSamples(w *bufio.Writer) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	labels := make([]string, 0, len(v.series))
+	for label := range v.series {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, labelValue := range labels {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", v.name, v.label, escapeLabel(labelValue), v.series[labelValue].Value())
+	}
+}
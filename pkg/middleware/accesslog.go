@@ -0,0 +1,196 @@
+/**
+ * @fileoverview Access logging.
+ * AccessLog writes one line per finished request with its method, path, status, bytes,
+ * latency, request ID, and user agent, as JSON or in Common Log Format. Paths can be
+ * excluded so probe traffic such as /health does not drown out real requests. Responses
+ * cut short by write errors are additionally reported through the standard logger.
+ */
+
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+)
+
+// Access log formats
+const (
+	AccessLogJSON   = "json"
+	AccessLogCommon = "common"
+)
+
+// commonLogTime is the timestamp layout used by Common Log Format
+const commonLogTime = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogEntry is one finished request
+type AccessLogEntry struct {
+	Time       time.Time `json:"time"`
+	ClientIP   string    `json:"client_ip"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Route      string    `json:"route,omitempty"`
+	Protocol   string    `json:"protocol"`
+	Status     int       `json:"status"`
+	Bytes      int64     `json:"bytes"`
+	DurationMs float64   `json:"duration_ms"`
+	RequestID  string    `json:"request_id,omitempty"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+}
+
+// AccessLog writes access log lines; its settings can be changed while serving
+type AccessLog struct {
+	mu       sync.Mutex
+	out      io.Writer
+	format   string
+	excluded map[string]bool
+	prefixes []string
+}
+
+/**
+ * @description Creates an access log writing Common Log Format lines to stderr.
+ */
+func NewAccessLog() *AccessLog {
+	return &AccessLog{out: os.Stderr, format: AccessLogCommon, excluded: make(map[string]bool)}
+}
+
+/**
+ * @description Sends access log lines to out.
+ */
+func (a *AccessLog) SetOutput(out io.Writer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.out = out
+}
+
+/**
+ * @description Selects AccessLogJSON or AccessLogCommon.
+ */
+func (a *AccessLog) SetFormat(format string) error {
+	if format != AccessLogJSON && format != AccessLogCommon {
+		return fmt.Errorf("unknown access log format %q: must be %s or %s", format, AccessLogJSON, AccessLogCommon)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.format = format
+	return nil
+}
+
+/**
+ * @description Replaces the excluded paths. A path ending in "/" excludes everything under it;
+ * any other path is matched exactly.
+ */
+func (a *AccessLog) Exclude(paths ...string) {
+	excluded := make(map[string]bool, len(paths))
+	var prefixes []string
+	for _, path := range paths {
+		if strings.HasSuffix(path, "/") {
+			prefixes = append(prefixes, path)
+		} else {
+			excluded[path] = true
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.excluded = excluded
+	a.prefixes = prefixes
+}
+
+// skip reports whether requests to path are excluded
+func (a *AccessLog) skip(path string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.excluded[path] {
+		return true
+	}
+	for _, prefix := range a.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+/**
+ * @description Logs every request that is not excluded once its response is finished.
+ */
+func (a *AccessLog) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := httputil.NewResponseWriter(w)
+			if a.skip(r.URL.Path) {
+				next.ServeHTTP(rw, r)
+				return
+			}
+
+			start := time.Now()
+			next.ServeHTTP(rw, r)
+			if rw.Aborted() {
+				log.Printf("Response aborted for %s %s after %d bytes: %v", r.Method, r.URL.Path, rw.BytesWritten(), rw.Err())
+			}
+
+			a.write(AccessLogEntry{
+				Time:       start,
+				ClientIP:   httputil.ClientIP(r),
+				Method:     r.Method,
+				Path:       r.URL.Path,
+				Route:      r.Pattern,
+				Protocol:   r.Proto,
+				Status:     rw.Status(),
+				Bytes:      rw.BytesWritten(),
+				DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+				RequestID:  rw.Header().Get(RequestIDHeader),
+				UserAgent:  r.UserAgent(),
+			})
+		})
+	}
+}
+
+// write formats entry and writes it as a single line
+func (a *AccessLog) write(entry AccessLogEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var line []byte
+	if a.format == AccessLogJSON {
+		encoded, err := jsoncase.Marshal(entry)
+		if err != nil {
+			log.Printf("Failed to encode access log entry: %v", err)
+			return
+		}
+		line = append(encoded, '\n')
+	} else {
+		line = []byte(formatCommon(entry))
+	}
+	if _, err := a.out.Write(line); err != nil {
+		log.Printf("Failed to write access log: %v", err)
+	}
+}
+
+// formatCommon renders entry in Common Log Format followed by the user agent, request ID, and latency
+func formatCommon(entry AccessLogEntry) string {
+	bytes := "-"
+	if entry.Bytes > 0 {
+		bytes = fmt.Sprint(entry.Bytes)
+	}
+	return fmt.Sprintf("%s - - [%s] \"%s %s %s\" %d %s %q %s %.3fms\n",
+		entry.ClientIP, entry.Time.Format(commonLogTime), entry.Method, entry.Path, entry.Protocol,
+		entry.Status, bytes, entry.UserAgent, orDash(entry.RequestID), entry.DurationMs)
+}
+
+// orDash substitutes "-" for empty Common Log Format fields
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
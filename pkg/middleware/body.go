@@ -0,0 +1,106 @@
+/**
+ * @fileoverview Request body size limiting.
+ * BodyLimits applies a global limit with per-route overrides keyed by the matched route
+ * pattern, so it must run after routing. Requests whose declared Content-Length exceeds
+ * the limit get 413 immediately; bodies without one are capped as they are read, so
+ * handlers see *http.MaxBytesError instead of buffering unbounded input.
+ */
+
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+)
+
+// BodyLimits holds the default request body limit and per-route overrides
+type BodyLimits struct {
+	mu           sync.RWMutex
+	defaultLimit int64
+	routes       map[string]int64
+}
+
+/**
+ * @description Creates limits applying defaultLimit bytes to every route; zero or less means no limit.
+ */
+func NewBodyLimits(defaultLimit int64) *BodyLimits {
+	return &BodyLimits{defaultLimit: defaultLimit, routes: make(map[string]int64)}
+}
+
+/**
+ * @description Replaces the limit applied to routes without an override.
+ */
+func (b *BodyLimits) SetDefault(limit int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.defaultLimit = limit
+}
+
+/**
+ * @description Overrides the limit for the route registered as pattern; zero or less removes the limit.
+ */
+func (b *BodyLimits) Set(pattern string, limit int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.routes[pattern] = limit
+}
+
+/**
+ * @description Returns the limit for pattern, falling back to the default.
+ */
+func (b *BodyLimits) Limit(pattern string) int64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if limit, ok := b.routes[pattern]; ok {
+		return limit
+	}
+	return b.defaultLimit
+}
+
+/**
+ * @description Enforces the limit for the matched route, responding 413 when Content-Length exceeds it.
+ */
+func (b *BodyLimits) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			limit := b.Limit(r.Pattern)
+			if limit <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if r.ContentLength > limit {
+				WriteBodyTooLarge(w, r, limit)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+/**
+ * @description Limits every request body to limit bytes.
+ */
+func MaxBodyBytes(limit int64) Middleware {
+	return NewBodyLimits(limit).Middleware()
+}
+
+/**
+ * @description Reports whether err came from reading a body past its limit.
+ */
+func IsBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+/**
+ * @description Writes the 413 response used when a request body exceeds limit bytes.
+ */
+func WriteBodyTooLarge(w http.ResponseWriter, r *http.Request, limit int64) {
+	w.Header().Set("Connection", "close")
+	apierror.Write(w, r, http.StatusRequestEntityTooLarge, "request body exceeds "+strconv.FormatInt(limit, 10)+" bytes")
+}
@@ -0,0 +1,122 @@
+/**
+ * @fileoverview In-flight request tracking.
+ * Counts the requests currently being handled, exports the count as the
+ * http_requests_in_flight gauge, and lists each request with its age so operators
+ * can see what a draining server is still waiting for.
+ */
+
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+// inFlightGauge reports the requests being handled by every InFlight tracker
+var inFlightGauge = metrics.NewGauge("http_requests_in_flight", "Requests currently being handled.")
+
+// InFlightRequest describes one request being handled
+type InFlightRequest struct {
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	RequestID string    `json:"request_id,omitempty"`
+	Started   time.Time `json:"started"`
+	// DurationMS is how long the request has been running when listed
+	DurationMS int64 `json:"duration_ms"`
+}
+
+// InFlightResponse is the JSON body served by InFlight.Handler
+type InFlightResponse struct {
+	InFlight int               `json:"in_flight"`
+	Requests []InFlightRequest `json:"requests"`
+}
+
+// InFlight tracks the requests passing through its middleware
+type InFlight struct {
+	mu       sync.Mutex
+	next     uint64
+	requests map[uint64]InFlightRequest
+}
+
+/**
+ * @description Creates an empty tracker.
+ */
+func NewInFlight() *InFlight {
+	return &InFlight{requests: make(map[uint64]InFlightRequest)}
+}
+
+/**
+ * @description Tracks each request from when it enters until its handler returns. Place it
+ * outermost so queued and rejected requests are counted too.
+ */
+func (t *InFlight) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := t.add(InFlightRequest{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				RequestID: r.Header.Get(RequestIDHeader),
+				Started:   time.Now(),
+			})
+			defer t.remove(id)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+/**
+ * @description Returns the number of requests being handled.
+ */
+func (t *InFlight) Count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.requests)
+}
+
+/**
+ * @description Returns the requests being handled, oldest first.
+ */
+func (t *InFlight) Requests() []InFlightRequest {
+	now := time.Now()
+	t.mu.Lock()
+	requests := make([]InFlightRequest, 0, len(t.requests))
+	for _, request := range t.requests {
+		request.DurationMS = now.Sub(request.Started).Milliseconds()
+		requests = append(requests, request)
+	}
+	t.mu.Unlock()
+
+	sort.Slice(requests, func(i, j int) bool { return requests[i].Started.Before(requests[j].Started) })
+	return requests
+}
+
+/**
+ * @description Serves the in-flight count and requests as JSON.
+ */
+func (t *InFlight) Handler(w http.ResponseWriter, r *http.Request) {
+	requests := t.Requests()
+	jsoncase.Write(w, http.StatusOK, InFlightResponse{InFlight: len(requests), Requests: requests})
+}
+
+// add records a request and returns its tracking ID
+func (t *InFlight) add(request InFlightRequest) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.next++
+	t.requests[t.next] = request
+	inFlightGauge.Add(1)
+	return t.next
+}
+
+// remove forgets a finished request
+func (t *InFlight) remove(id uint64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.requests, id)
+	inFlightGauge.Add(-1)
+}
@@ -0,0 +1,68 @@
+/**
+ * @fileoverview Composable HTTP middleware.
+ * A Middleware wraps an http.Handler; Chain composes several into one and a Stack
+ * collects middleware with Use so it can be applied globally or extended per route
+ * with With. Middleware listed first runs outermost.
+ */
+
+package middleware
+
+import "net/http"
+
+// Middleware wraps a handler with additional behavior
+type Middleware func(http.Handler) http.Handler
+
+// Stack is an ordered list of middleware, outermost first
+type Stack struct {
+	middleware []Middleware
+}
+
+/**
+ * @description Composes middleware into one, with the first argument outermost.
+ */
+func Chain(middleware ...Middleware) Middleware {
+	return func(next http.Handler) http.Handler {
+		for i := len(middleware) - 1; i >= 0; i-- {
+			next = middleware[i](next)
+		}
+		return next
+	}
+}
+
+/**
+ * @description Creates a stack from middleware, outermost first.
+ */
+func New(middleware ...Middleware) *Stack {
+	return &Stack{middleware: append([]Middleware(nil), middleware...)}
+}
+
+/**
+ * @description Appends middleware inside everything already in the stack.
+ */
+func (s *Stack) Use(middleware ...Middleware) {
+	s.middleware = append(s.middleware, middleware...)
+}
+
+/**
+ * @description Returns a new stack with middleware appended, leaving s unchanged; used for
+ * per-route additions such as authentication on top of the global stack.
+ */
+func (s *Stack) With(middleware ...Middleware) *Stack {
+	combined := make([]Middleware, 0, len(s.middleware)+len(middleware))
+	combined = append(combined, s.middleware...)
+	return &Stack{middleware: append(combined, middleware...)}
+}
+
+/**
+ * @description Wraps handler with every middleware in the stack.
+ */
+func (s *Stack) Then(handler http.Handler) http.Handler {
+	return Chain(s.middleware...)(handler)
+}
+
+/**
+ * @description Wraps a handler function with every middleware in the stack.
+ */
+func (s *Stack) ThenFunc(handler func(http.ResponseWriter, *http.Request)) http.Handler {
+	return s.Then(http.HandlerFunc(handler))
+}
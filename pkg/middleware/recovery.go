@@ -0,0 +1,121 @@
+/**
+ * @fileoverview Panic recovery.
+ * Recovery turns handler panics into JSON 500 responses carrying the request ID, logs the
+ * panic with its stack, counts it, and hands a PanicReport to optional reporters such as
+ * an error tracker webhook.
+ */
+
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+// DefaultPanicReportTimeout bounds each delivery to an error tracker webhook
+const DefaultPanicReportTimeout = 5 * time.Second
+
+// panics counts recovered handler panics
+var panics = metrics.NewCounter("http_panics_total", "Handler panics recovered by the server.")
+
+// PanicReport describes a recovered handler panic
+type PanicReport struct {
+	Time      time.Time `json:"time"`
+	Method    string    `json:"method"`
+	Path      string    `json:"path"`
+	Route     string    `json:"route,omitempty"`
+	RequestID string    `json:"request_id,omitempty"`
+	Value     string    `json:"value"`
+	Stack     string    `json:"stack"`
+}
+
+// PanicReporter receives every recovered panic; it runs on the request goroutine and should not block
+type PanicReporter func(PanicReport)
+
+/**
+ * @description Recovers from handler panics, logging the stack and responding 500 with the
+ * request ID. Each reporter receives the panic. http.ErrAbortHandler is re-raised so the
+ * server still aborts the response.
+ */
+func Recovery(reporters ...PanicReporter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := httputil.NewResponseWriter(w)
+			defer func() {
+				value := recover()
+				if value == nil {
+					return
+				}
+				if value == http.ErrAbortHandler {
+					panic(value)
+				}
+
+				report := PanicReport{
+					Time:   time.Now().UTC(),
+					Method: r.Method,
+					Path:   r.URL.Path,
+					Route:  r.Pattern,
+					// Set by RequestID, which runs outside Recovery in the standard chain
+					RequestID: rw.Header().Get(RequestIDHeader),
+					Value:     fmt.Sprint(value),
+					Stack:     string(debug.Stack()),
+				}
+				panics.Add(1)
+				log.Printf("Panic in handler %s %s [%s]: %s\n%s", report.Method, report.Path, report.RequestID, report.Value, report.Stack)
+				for _, reporter := range reporters {
+					reporter(report)
+				}
+
+				// The status line is already sent when the handler panicked mid-response
+				if rw.WroteHeader() {
+					return
+				}
+				problem := apierror.New(http.StatusInternalServerError, "internal server error")
+				problem.RequestID = report.RequestID
+				problem.Write(rw, r)
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+/**
+ * @description Returns a reporter that POSTs each PanicReport as JSON to url in the background,
+ * logging failed deliveries.
+ */
+func WebhookPanicReporter(url string) PanicReporter {
+	client := &http.Client{Timeout: DefaultPanicReportTimeout}
+	return func(report PanicReport) {
+		go func() {
+			if err := postPanicReport(client, url, report); err != nil {
+				log.Printf("Failed to report panic to %s: %v", url, err)
+			}
+		}()
+	}
+}
+
+// postPanicReport delivers one report
+func postPanicReport(client *http.Client, url string, report PanicReport) error {
+	payload, err := jsoncase.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode panic report: %w", err)
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
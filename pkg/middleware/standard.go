@@ -0,0 +1,50 @@
+/**
+ * @fileoverview Standard middleware shared by every route.
+ * RequestID propagates or assigns X-Request-ID and Observe hands the finished response
+ * to a callback for metrics. Together with Recovery and AccessLog they share one
+ * httputil.ResponseWriter so the response outcome is visible to every layer.
+ */
+
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/id"
+)
+
+// RequestIDHeader carries the request identifier in both directions
+const RequestIDHeader = "X-Request-ID"
+
+/**
+ * @description Propagates the caller's request ID from header or assigns a new one,
+ * setting it on both the request and the response.
+ */
+func RequestID(header string) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(header)
+			if requestID == "" {
+				requestID = id.New()
+				r.Header.Set(header, requestID)
+			}
+			w.Header().Set(header, requestID)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+/**
+ * @description Calls observe with the finished response, including responses written by
+ * Recovery when it runs inside Observe.
+ */
+func Observe(observe func(*http.Request, *httputil.ResponseWriter)) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := httputil.NewResponseWriter(w)
+			defer observe(r, rw)
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
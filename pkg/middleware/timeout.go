@@ -0,0 +1,196 @@
+/**
+ * @fileoverview Per-route handler timeouts.
+ * Timeouts applies a default timeout with per-route overrides keyed by the matched route
+ * pattern, so it must run after routing. Like http.TimeoutHandler, the handler runs with
+ * the deadline on its context and a buffered response; when the deadline passes first the
+ * client gets 504 right away, even if the handler ignores its context, and whatever the
+ * handler writes afterwards is discarded. Buffering rules out flushing and hijacking, so
+ * streaming routes must disable the timeout.
+ */
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+)
+
+// Timeouts holds the default handler timeout and per-route overrides
+type Timeouts struct {
+	mu             sync.RWMutex
+	defaultTimeout time.Duration
+	routes         map[string]time.Duration
+}
+
+/**
+ * @description Creates timeouts applying defaultTimeout to every route; zero or less means no timeout.
+ */
+func NewTimeouts(defaultTimeout time.Duration) *Timeouts {
+	return &Timeouts{defaultTimeout: defaultTimeout, routes: make(map[string]time.Duration)}
+}
+
+/**
+ * @description Replaces the timeout applied to routes without an override.
+ */
+func (t *Timeouts) SetDefault(timeout time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.defaultTimeout = timeout
+}
+
+/**
+ * @description Overrides the timeout for the route registered as pattern; zero or less removes the timeout.
+ */
+func (t *Timeouts) Set(pattern string, timeout time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.routes[pattern] = timeout
+}
+
+/**
+ * @description Returns the timeout for pattern, falling back to the default.
+ */
+func (t *Timeouts) Timeout(pattern string) time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if timeout, ok := t.routes[pattern]; ok {
+		return timeout
+	}
+	return t.defaultTimeout
+}
+
+/**
+ * @description Bounds the matched route's handler by its timeout, responding 504 as soon as
+ * it expires unless the handler finished first. The handler's response is buffered and
+ * written once it returns in time; panics are re-raised for Recovery.
+ */
+func (t *Timeouts) Middleware() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			timeout := t.Timeout(r.Pattern)
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+
+			tw := &timeoutWriter{w: w, header: w.Header().Clone()}
+			done := make(chan struct{})
+			panicked := make(chan any, 1)
+			go func() {
+				defer func() {
+					if recovered := recover(); recovered != nil {
+						// Keep the handler's stack, which is lost once the panic is re-raised
+						if recovered != http.ErrAbortHandler {
+							recovered = fmt.Sprintf("%v\n\n%s", recovered, debug.Stack())
+						}
+						panicked <- recovered
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case recovered := <-panicked:
+				panic(recovered)
+			case <-done:
+				tw.flush()
+			case <-ctx.Done():
+				tw.mu.Lock()
+				defer tw.mu.Unlock()
+				tw.timedOut = true
+				// A cancelled parent context means the client is gone and nothing can be sent
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					WriteTimeout(w, r, timeout)
+				}
+			}
+		})
+	}
+}
+
+/**
+ * @description Bounds every request by timeout.
+ */
+func Timeout(timeout time.Duration) Middleware {
+	return NewTimeouts(timeout).Middleware()
+}
+
+/**
+ * @description Writes the 504 response used when a handler exceeds its timeout.
+ */
+func WriteTimeout(w http.ResponseWriter, r *http.Request, timeout time.Duration) {
+	apierror.Write(w, r, http.StatusGatewayTimeout, "request timed out after "+timeout.String())
+}
+
+// timeoutWriter buffers a handler's response until it returns in time; writes after the
+// timeout fail with http.ErrHandlerTimeout
+type timeoutWriter struct {
+	w      http.ResponseWriter
+	header http.Header
+
+	mu          sync.Mutex
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	tw.wroteHeader = true
+	tw.status = status
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.wroteHeader = true
+		tw.status = http.StatusOK
+	}
+	return tw.body.Write(b)
+}
+
+// ObserveSerialization passes encoding time through to the metrics writer outside
+func (tw *timeoutWriter) ObserveSerialization(d time.Duration) {
+	if observer, ok := tw.w.(interface{ ObserveSerialization(time.Duration) }); ok {
+		observer.ObserveSerialization(d)
+	}
+}
+
+// flush writes the buffered response once the handler has returned in time
+func (tw *timeoutWriter) flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	header := tw.w.Header()
+	clear(header)
+	maps.Copy(header, tw.header)
+	if !tw.wroteHeader {
+		tw.status = http.StatusOK
+	}
+	tw.w.WriteHeader(tw.status)
+	tw.w.Write(tw.body.Bytes())
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeoutsLookup(t *testing.T) {
+	timeouts := NewTimeouts(time.Second)
+	timeouts.Set("GET /slow", 5*time.Second)
+	timeouts.Set("GET /stream", 0)
+
+	tests := []struct {
+		pattern string
+		want    time.Duration
+	}{
+		{pattern: "GET /", want: time.Second},
+		{pattern: "", want: time.Second},
+		{pattern: "GET /slow", want: 5 * time.Second},
+		{pattern: "GET /stream", want: 0},
+	}
+	for _, tt := range tests {
+		if got := timeouts.Timeout(tt.pattern); got != tt.want {
+			t.Errorf("Timeout(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+
+	timeouts.SetDefault(2 * time.Second)
+	if got := timeouts.Timeout("GET /"); got != 2*time.Second {
+		t.Errorf("Timeout after SetDefault = %v, want 2s", got)
+	}
+	if got := timeouts.Timeout("GET /slow"); got != 5*time.Second {
+		t.Errorf("override after SetDefault = %v, want 5s", got)
+	}
+}
+
+func TestTimeoutsMiddleware(t *testing.T) {
+	// respond writes immediately; hang waits until the middleware has answered and reports the late write's error
+	respond := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Handler", "done")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("created"))
+	}
+
+	tests := []struct {
+		name       string
+		pattern    string
+		hang       bool
+		wantStatus int
+		wantBody   string
+		wantHeader string
+	}{
+		{name: "fast handler under default", pattern: "GET /", wantStatus: http.StatusCreated, wantBody: "created", wantHeader: "done"},
+		{name: "slow handler under default", pattern: "GET /", hang: true, wantStatus: http.StatusGatewayTimeout, wantBody: "request timed out after 20ms"},
+		{name: "slow handler under override", pattern: "GET /short", hang: true, wantStatus: http.StatusGatewayTimeout, wantBody: "request timed out after 10ms"},
+		{name: "fast handler with timeout disabled", pattern: "GET /stream", wantStatus: http.StatusCreated, wantBody: "created", wantHeader: "done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timeouts := NewTimeouts(20 * time.Millisecond)
+			timeouts.Set("GET /short", 10*time.Millisecond)
+			timeouts.Set("GET /stream", 0)
+
+			answered := make(chan struct{})
+			lateWrite := make(chan error, 1)
+			handler := timeouts.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !tt.hang {
+					respond(w, r)
+					return
+				}
+				<-answered
+				w.Header().Set("X-Handler", "late")
+				_, err := w.Write([]byte("late"))
+				lateWrite <- err
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Pattern = tt.pattern
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			close(answered)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if !strings.Contains(recorder.Body.String(), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", recorder.Body.String(), tt.wantBody)
+			}
+			if got := recorder.Header().Get("X-Handler"); got != tt.wantHeader {
+				t.Errorf("X-Handler = %q, want %q", got, tt.wantHeader)
+			}
+			if tt.hang {
+				if err := <-lateWrite; !errors.Is(err, http.ErrHandlerTimeout) {
+					t.Errorf("late write error = %v, want http.ErrHandlerTimeout", err)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeoutsMiddlewareReraisesPanics(t *testing.T) {
+	handler := Timeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	defer func() {
+		recovered := recover()
+		if recovered == nil || !strings.HasPrefix(recovered.(string), "boom") {
+			t.Errorf("recovered %v, want the handler's panic", recovered)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,152 @@
+/**
+ * @fileoverview OpenAPI 3 document generated from mounted routes.
+ * Each ServeMux pattern becomes a path item with its path parameters declared, so the
+ * published spec always matches what the router serves. Summaries and responses for
+ * known routes are layered on top from a map keyed by pattern.
+ */
+
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/routes"
+)
+
+// Version is the OpenAPI specification version documents are written against
+const Version = "3.1.0"
+
+// Document is the root of an OpenAPI document
+type Document struct {
+	OpenAPI string              `json:"openapi"`
+	Info    Info                `json:"info"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+// Info describes the API as a whole
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lowercase HTTP methods to the operations served at one path
+type PathItem map[string]*Operation
+
+// Operation describes a single method on a path
+type Operation struct {
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Tags        []string            `json:"tags,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter describes a path, query, or header parameter
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required"`
+	Schema      Schema `json:"schema"`
+}
+
+// Schema is the subset of JSON Schema used for parameters and bodies
+type Schema struct {
+	Type string `json:"type,omitempty"`
+}
+
+// Response describes one response status of an operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType describes a response body for one content type
+type MediaType struct {
+	Schema Schema `json:"schema"`
+}
+
+// wildcardPattern matches ServeMux wildcards such as {id} and {path...}
+var wildcardPattern = regexp.MustCompile(`\{([^{}.$]+)(\.\.\.)?\}`)
+
+/**
+ * @description Builds a document describing the mounted routes.
+ * Operations in described, keyed by the route's pattern ("GET /version" or "/health"),
+ * replace the generated defaults; their path parameters are still filled in from the pattern.
+ * Routes that match every method are documented as GET.
+ */
+func Generate(info Info, mounted []routes.RouteInfo, described map[string]Operation) *Document {
+	doc := &Document{OpenAPI: Version, Info: info, Paths: map[string]PathItem{}}
+	for _, route := range mounted {
+		path, params := convertPath(route.Path)
+		method := strings.ToLower(route.Method)
+		if method == "" {
+			method = "get"
+		}
+		// HEAD is implied by GET in ServeMux and is not documented separately
+		if method == "head" && doc.Paths[path]["get"] != nil {
+			continue
+		}
+
+		pattern := route.Path
+		if route.Method != "" {
+			pattern = route.Method + " " + route.Path
+		}
+		operation, ok := described[pattern]
+		if !ok {
+			operation = Operation{Responses: map[string]Response{"200": {Description: "Successful response"}}}
+		}
+		if len(operation.Tags) == 0 {
+			operation.Tags = []string{route.Source}
+		}
+		operation.Parameters = append(params, operation.Parameters...)
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][method] = &operation
+	}
+	return doc
+}
+
+/**
+ * @description Serves the document as JSON with an ETag, so clients that poll it get 304
+ * until the routes change.
+ * Encodes with encoding/json directly because OpenAPI field names are fixed by the
+ * specification and must not follow the configured response casing.
+ */
+func (d *Document) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		apierror.Write(w, r, http.StatusInternalServerError, "failed to encode OpenAPI document")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	httputil.WriteConditional(w, r, http.StatusOK, append(body, '\n'), time.Time{})
+}
+
+// convertPath turns a ServeMux path into an OpenAPI path template and its parameters,
+// dropping any host prefix and the {$} end-of-path anchor
+func convertPath(path string) (string, []Parameter) {
+	if i := strings.Index(path, "/"); i > 0 {
+		path = path[i:]
+	}
+	path = strings.ReplaceAll(path, "{$}", "")
+
+	var params []Parameter
+	for _, match := range wildcardPattern.FindAllStringSubmatch(path, -1) {
+		param := Parameter{Name: match[1], In: "path", Required: true, Schema: Schema{Type: "string"}}
+		if match[2] != "" {
+			param.Description = "Remaining path segments"
+		}
+		params = append(params, param)
+	}
+	return wildcardPattern.ReplaceAllString(path, "{$1}"), params
+}
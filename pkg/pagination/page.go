@@ -0,0 +1,109 @@
+/**
+ * @fileoverview Paged list responses.
+ * A Page carries the items with the total count when known and next/prev links that keep
+ * the request's other query parameters, so filters and sorting survive paging. The same
+ * links and count are sent as Link and X-Total-Count headers for clients that page
+ * without parsing bodies.
+ */
+
+package pagination
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// TotalCountHeader carries the total number of items across all pages
+const TotalCountHeader = "X-Total-Count"
+
+// Page is one page of a list response
+type Page[T any] struct {
+	Items []T `json:"items"`
+	// Total counts the items across all pages; omitted when unknown, e.g. with cursors
+	Total *int `json:"total,omitempty"`
+	Limit int  `json:"limit"`
+	// Next and Prev are relative URLs of the adjacent pages; empty at either end
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
+}
+
+/**
+ * @description Builds an offset page: items fetched at params.Offset, and total, the count
+ * across all pages. The next link is omitted on the last page and prev on the first.
+ */
+func OffsetPage[T any](r *http.Request, params Params, items []T, total int) Page[T] {
+	page := Page[T]{Items: nonNil(items), Total: &total, Limit: params.Limit}
+	if end := params.Offset + len(items); end < total {
+		page.Next = pageLink(r, params.Limit, OffsetParam, strconv.Itoa(end))
+	}
+	if params.Offset > 0 {
+		page.Prev = pageLink(r, params.Limit, OffsetParam, strconv.Itoa(max(0, params.Offset-params.Limit)))
+	}
+	return page
+}
+
+/**
+ * @description Builds a cursor page: next and prev are the cursors of the adjacent pages,
+ * or "" when there is none in that direction.
+ */
+func CursorPage[T any](r *http.Request, params Params, items []T, next, prev string) Page[T] {
+	page := Page[T]{Items: nonNil(items), Limit: params.Limit}
+	if next != "" {
+		page.Next = pageLink(r, params.Limit, CursorParam, next)
+	}
+	if prev != "" {
+		page.Prev = pageLink(r, params.Limit, CursorParam, prev)
+	}
+	return page
+}
+
+/**
+ * @description Returns the page of items selected by params' offset and limit from an
+ * in-memory list, with the list's length as the total.
+ */
+func Slice[T any](r *http.Request, params Params, items []T) Page[T] {
+	start := min(params.Offset, len(items))
+	end := min(start+params.Limit, len(items))
+	return OffsetPage(r, params, items[start:end], len(items))
+}
+
+/**
+ * @description Sets the Link header with the next and prev relations and, when the total
+ * is known, the X-Total-Count header.
+ */
+func (p Page[T]) SetHeaders(w http.ResponseWriter) {
+	var links []string
+	if p.Next != "" {
+		links = append(links, "<"+p.Next+`>; rel="next"`)
+	}
+	if p.Prev != "" {
+		links = append(links, "<"+p.Prev+`>; rel="prev"`)
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	if p.Total != nil {
+		w.Header().Set(TotalCountHeader, strconv.Itoa(*p.Total))
+	}
+}
+
+// pageLink returns the request's path and query with the limit and position replaced,
+// dropping the other position parameter
+func pageLink(r *http.Request, limit int, param, value string) string {
+	query := r.URL.Query()
+	query.Del(OffsetParam)
+	query.Del(CursorParam)
+	query.Set(LimitParam, strconv.Itoa(limit))
+	query.Set(param, value)
+	return (&url.URL{Path: r.URL.Path, RawQuery: query.Encode()}).String()
+}
+
+// nonNil returns items, or an empty slice so empty pages encode as []
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
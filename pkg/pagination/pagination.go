@@ -0,0 +1,180 @@
+/**
+ * @fileoverview Pagination, filtering, and sorting parameters for list endpoints.
+ * Parses limit with either offset or an opaque cursor, a sort list such as
+ * "sort=-created_at,name", and equality filters on declared fields, enforcing the
+ * endpoint's limits. Invalid parameters are reported as validate.Errors so handlers
+ * answer them with validate.WriteError like any other invalid request.
+ */
+
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/validate"
+)
+
+// Query parameter names
+const (
+	LimitParam  = "limit"
+	OffsetParam = "offset"
+	CursorParam = "cursor"
+	SortParam   = "sort"
+)
+
+const (
+	// DefaultLimit is the page size when Config and the request leave it unset
+	DefaultLimit = 20
+	// DefaultMaxLimit caps the page size when Config leaves MaxLimit zero
+	DefaultMaxLimit = 100
+)
+
+// Config declares what a list endpoint supports
+type Config struct {
+	// DefaultLimit is the page size when the request has no limit; zero uses DefaultLimit
+	DefaultLimit int
+	// MaxLimit caps the requested page size; zero uses DefaultMaxLimit
+	MaxLimit int
+	// SortFields lists the fields clients may sort by
+	SortFields []string
+	// DefaultSort applies when the request has no sort parameter, e.g. "-created_at"
+	DefaultSort string
+	// FilterFields lists the query parameters accepted as equality filters
+	FilterFields []string
+}
+
+// SortField is one sort key
+type SortField struct {
+	Field      string
+	Descending bool
+}
+
+// Params are the parsed pagination, sorting, and filtering parameters of a request
+type Params struct {
+	Limit  int
+	Offset int
+	// Cursor is the opaque position to continue from; decode it with DecodeCursor
+	Cursor  string
+	Sort    []SortField
+	Filters map[string]string
+}
+
+/**
+ * @description Parses the request's limit, offset, cursor, sort, and filter parameters.
+ * Limits above the maximum are clamped; offset and cursor are mutually exclusive.
+ * Returns validate.Errors naming each invalid parameter.
+ */
+func Parse(r *http.Request, config Config) (Params, error) {
+	if config.DefaultLimit <= 0 {
+		config.DefaultLimit = DefaultLimit
+	}
+	if config.MaxLimit <= 0 {
+		config.MaxLimit = DefaultMaxLimit
+	}
+
+	query := r.URL.Query()
+	params := Params{Limit: min(config.DefaultLimit, config.MaxLimit), Cursor: query.Get(CursorParam)}
+	var errs validate.Errors
+
+	if value := query.Get(LimitParam); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			errs = append(errs, validate.FieldError{Field: LimitParam, Rule: "min", Message: "must be a positive integer"})
+		} else {
+			params.Limit = min(limit, config.MaxLimit)
+		}
+	}
+	if value := query.Get(OffsetParam); value != "" {
+		offset, err := strconv.Atoi(value)
+		switch {
+		case err != nil || offset < 0:
+			errs = append(errs, validate.FieldError{Field: OffsetParam, Rule: "min", Message: "must be a non-negative integer"})
+		case params.Cursor != "":
+			errs = append(errs, validate.FieldError{Field: OffsetParam, Rule: "exclusive", Message: "cannot be combined with cursor"})
+		default:
+			params.Offset = offset
+		}
+	}
+
+	sortValue := query.Get(SortParam)
+	if sortValue == "" {
+		sortValue = config.DefaultSort
+	}
+	sortFields, err := ParseSort(sortValue, config.SortFields)
+	if err != nil {
+		errs = append(errs, validate.FieldError{Field: SortParam, Rule: "enum", Message: err.Error()})
+	}
+	params.Sort = sortFields
+
+	for _, name := range config.FilterFields {
+		if value := query.Get(name); value != "" {
+			if params.Filters == nil {
+				params.Filters = make(map[string]string)
+			}
+			params.Filters[name] = value
+		}
+	}
+
+	if len(errs) > 0 {
+		return Params{}, errs
+	}
+	return params, nil
+}
+
+/**
+ * @description Parses a comma-separated sort list, where a leading "-" sorts a field in
+ * descending order, accepting only the allowed fields.
+ */
+func ParseSort(value string, allowed []string) ([]SortField, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var fields []SortField
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		field := SortField{Field: strings.TrimPrefix(part, "-"), Descending: strings.HasPrefix(part, "-")}
+		if !slices.Contains(allowed, field.Field) {
+			if len(allowed) == 0 {
+				return nil, errors.New("sorting is not supported")
+			}
+			return nil, fmt.Errorf("cannot sort by %q; sortable fields are %s", field.Field, strings.Join(allowed, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+/**
+ * @description Encodes a position, such as the sort key of the last item on a page, as an
+ * opaque cursor.
+ */
+func EncodeCursor(position any) (string, error) {
+	data, err := json.Marshal(position)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+/**
+ * @description Decodes a cursor produced by EncodeCursor into position. Returns
+ * validate.Errors for cursors that were not produced by EncodeCursor.
+ */
+func DecodeCursor(cursor string, position any) error {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, position)
+	}
+	if err != nil {
+		return validate.Errors{{Field: CursorParam, Rule: "format", Message: "is not a valid cursor"}}
+	}
+	return nil
+}
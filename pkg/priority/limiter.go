@@ -0,0 +1,153 @@
+/**
+ * @fileoverview Priority-aware concurrency limiter.
+ * Bounds concurrent requests and, when saturated, queues waiters per priority class so
+ * freed slots go to the most important waiting request first. Critical traffic bypasses
+ * the limit so health probes keep working under load.
+ */
+
+package priority
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+)
+
+// ErrQueueFull is returned when no slot is free and the wait queue is full
+var ErrQueueFull = errors.New("priority: request queue is full")
+
+// Limiter admits at most Capacity non-critical requests at a time
+type Limiter struct {
+	capacity     int
+	maxQueue     int
+	queueTimeout time.Duration
+
+	mu       sync.Mutex
+	inFlight int
+	queued   int
+	waiters  [numClasses][]chan struct{}
+}
+
+/**
+ * @description Creates a limiter allowing capacity concurrent requests with at most
+ * maxQueue waiters, each waiting no longer than queueTimeout.
+ */
+func NewLimiter(capacity, maxQueue int, queueTimeout time.Duration) *Limiter {
+	return &Limiter{capacity: capacity, maxQueue: maxQueue, queueTimeout: queueTimeout}
+}
+
+/**
+ * @description Waits for a slot for a request of the given class, returning a release func.
+ * Fails with ErrQueueFull when the queue is full, or with the context error on timeout.
+ */
+func (l *Limiter) Acquire(ctx context.Context, class Class) (func(), error) {
+	if class == Critical {
+		return func() {}, nil
+	}
+
+	l.mu.Lock()
+	if l.inFlight < l.capacity && !l.hasWaitersAtOrAbove(class) {
+		l.inFlight++
+		l.mu.Unlock()
+		return l.release, nil
+	}
+	if l.queued >= l.maxQueue {
+		l.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+
+	ready := make(chan struct{})
+	l.waiters[class] = append(l.waiters[class], ready)
+	l.queued++
+	l.mu.Unlock()
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-ready:
+		return l.release, nil
+	case <-timer.C:
+		return l.abandon(class, ready, context.DeadlineExceeded)
+	case <-ctx.Done():
+		return l.abandon(class, ready, ctx.Err())
+	}
+}
+
+/**
+ * @description Returns the number of admitted non-critical requests and queued waiters.
+ */
+func (l *Limiter) Stats() (inFlight, queued int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inFlight, l.queued
+}
+
+/**
+ * @description Wraps next so every request acquires a slot according to its class.
+ * Rejected requests receive 503 with a Retry-After hint.
+ */
+func (l *Limiter) Middleware(classifier *Classifier, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		release, err := l.Acquire(r.Context(), classifier.Classify(r))
+		if err != nil {
+			w.Header().Set("Retry-After", strconv.Itoa(max(1, int(l.queueTimeout.Seconds()))))
+			apierror.Write(w, r, http.StatusServiceUnavailable, "server is at capacity, please retry")
+			return
+		}
+		defer release()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// release hands the slot to the highest-priority waiter or frees it
+func (l *Limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for class := range l.waiters {
+		if len(l.waiters[class]) == 0 {
+			continue
+		}
+		next := l.waiters[class][0]
+		l.waiters[class] = l.waiters[class][1:]
+		l.queued--
+		close(next)
+		return
+	}
+	l.inFlight--
+}
+
+// abandon removes a waiter that gave up; if a slot was granted concurrently it is passed on
+func (l *Limiter) abandon(class Class, ready chan struct{}, cause error) (func(), error) {
+	l.mu.Lock()
+	for i, waiter := range l.waiters[class] {
+		if waiter == ready {
+			l.waiters[class] = append(l.waiters[class][:i], l.waiters[class][i+1:]...)
+			l.queued--
+			l.mu.Unlock()
+			return nil, cause
+		}
+	}
+	l.mu.Unlock()
+
+	// The slot was granted between the timeout and acquiring the lock
+	l.release()
+	return nil, cause
+}
+
+// hasWaitersAtOrAbove reports whether requests of equal or higher priority are queued
+func (l *Limiter) hasWaitersAtOrAbove(class Class) bool {
+	for c := Critical; c <= class; c++ {
+		if len(l.waiters[c]) > 0 {
+			return true
+		}
+	}
+	return false
+}
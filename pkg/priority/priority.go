@@ -0,0 +1,135 @@
+/**
+ * @fileoverview Request priority classes and classification rules.
+ * Derives a priority from the route, an optional API-key tier lookup, and a request
+ * header, so operational and paid traffic can be admitted ahead of best-effort traffic.
+ */
+
+package priority
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Class is a request priority; lower values are admitted first
+type Class int
+
+// Priority classes from most to least important
+const (
+	// Critical traffic (health probes) bypasses the concurrency limit
+	Critical Class = iota
+	High
+	Normal
+	BestEffort
+	numClasses
+)
+
+// HeaderName lets clients lower the priority of their own requests
+const HeaderName = "X-Request-Priority"
+
+/**
+ * @description Returns the class name used in headers, logs, and configuration.
+ */
+func (c Class) String() string {
+	switch c {
+	case Critical:
+		return "critical"
+	case High:
+		return "high"
+	case Normal:
+		return "normal"
+	case BestEffort:
+		return "best-effort"
+	default:
+		return fmt.Sprintf("class(%d)", int(c))
+	}
+}
+
+/**
+ * @description Parses a class name as produced by String.
+ */
+func ParseClass(name string) (Class, error) {
+	for class := Critical; class < numClasses; class++ {
+		if strings.EqualFold(strings.TrimSpace(name), class.String()) {
+			return class, nil
+		}
+	}
+	return Normal, fmt.Errorf("unknown priority class %q", name)
+}
+
+// RouteRule assigns a class to requests for Path or, unless Exact, paths below it
+type RouteRule struct {
+	Path string
+	// Exact matches Path only; otherwise paths below it match at a segment boundary, so
+	// "/health" matches "/health/live" but not "/healthcare"
+	Exact bool
+	Class Class
+}
+
+// TierFunc maps a request to a class from its API key tier; ok is false when no key applies
+type TierFunc func(r *http.Request) (class Class, ok bool)
+
+// Classifier assigns a priority class to each request
+type Classifier struct {
+	// Routes are evaluated in order; the first matching rule wins
+	Routes []RouteRule
+	// Tier looks up the caller's API key tier when route rules do not match
+	Tier TierFunc
+	// Default is used when nothing else matches
+	Default Class
+}
+
+// DefaultRoutes lets the health probes bypass the limiter and queues operator traffic
+// ahead of normal requests. Admin endpoints are High rather than Critical, since an
+// unauthenticated request to any /admin/ path would otherwise skip the limiter.
+var DefaultRoutes = []RouteRule{
+	{Path: "/health", Exact: true, Class: Critical},
+	{Path: "/health/", Exact: true, Class: Critical},
+	{Path: "/ready", Exact: true, Class: Critical},
+	{Path: "/admin", Class: High},
+}
+
+/**
+ * @description Returns a classifier with DefaultRoutes and Normal as the default class.
+ */
+func NewClassifier() *Classifier {
+	return &Classifier{Routes: DefaultRoutes, Default: Normal}
+}
+
+/**
+ * @description Determines the request's class from route rules, then the API key tier,
+ * then the default. The priority header may only lower the result, never raise it.
+ */
+func (c *Classifier) Classify(r *http.Request) Class {
+	class, matched := c.Default, false
+
+	for _, rule := range c.Routes {
+		if rule.matches(r.URL.Path) {
+			class, matched = rule.Class, true
+			break
+		}
+	}
+
+	if !matched && c.Tier != nil {
+		if tierClass, ok := c.Tier(r); ok {
+			class = tierClass
+		}
+	}
+
+	if requested, err := ParseClass(r.Header.Get(HeaderName)); err == nil && requested > class {
+		class = requested
+	}
+	return class
+}
+
+// matches reports whether path is the rule's path or, unless Exact, lies below it
+func (rule RouteRule) matches(path string) bool {
+	if path == rule.Path {
+		return true
+	}
+	if rule.Exact {
+		return false
+	}
+	return strings.HasPrefix(path, strings.TrimSuffix(rule.Path, "/")+"/")
+}
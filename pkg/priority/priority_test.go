@@ -0,0 +1,189 @@
+package priority
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseClass(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Class
+		wantErr bool
+	}{
+		{name: "critical", want: Critical},
+		{name: "high", want: High},
+		{name: " Normal ", want: Normal},
+		{name: "BEST-EFFORT", want: BestEffort},
+		{name: "urgent", want: Normal, wantErr: true},
+		{name: "", want: Normal, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseClass(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseClass(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseClass(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	paid := func(r *http.Request) (Class, bool) {
+		if r.Header.Get("X-API-Key") == "paid" {
+			return High, true
+		}
+		return Normal, false
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		header map[string]string
+		tier   TierFunc
+		want   Class
+	}{
+		{name: "health probe", path: "/health", want: Critical},
+		{name: "readiness probe", path: "/ready", want: Critical},
+		{name: "exact rule does not match below", path: "/health/live", want: Normal},
+		{name: "admin subtree", path: "/admin/state", want: High},
+		{name: "admin at segment boundary only", path: "/administrator", want: Normal},
+		{name: "default", path: "/v1/completions", want: Normal},
+		{name: "tier applies without a route", path: "/v1/completions", header: map[string]string{"X-API-Key": "paid"}, tier: paid, want: High},
+		{name: "route wins over tier", path: "/health", header: map[string]string{"X-API-Key": "paid"}, tier: paid, want: Critical},
+		{name: "header lowers", path: "/v1/completions", header: map[string]string{HeaderName: "best-effort"}, want: BestEffort},
+		{name: "header cannot raise", path: "/v1/completions", header: map[string]string{HeaderName: "critical"}, want: Normal},
+		{name: "unknown header ignored", path: "/admin", header: map[string]string{HeaderName: "urgent"}, want: High},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classifier := NewClassifier()
+			classifier.Tier = tt.tier
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for name, value := range tt.header {
+				req.Header.Set(name, value)
+			}
+			if got := classifier.Classify(req); got != tt.want {
+				t.Errorf("Classify(%s) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimiterAcquire(t *testing.T) {
+	tests := []struct {
+		name     string
+		capacity int
+		maxQueue int
+		// held is how many normal requests hold slots before the call under test
+		held    int
+		class   Class
+		wantErr error
+	}{
+		{name: "free slot", capacity: 1, class: Normal},
+		{name: "critical bypasses a full limiter", capacity: 1, held: 1, class: Critical},
+		{name: "queue full", capacity: 1, held: 1, class: Normal, wantErr: ErrQueueFull},
+		{name: "queued until timeout", capacity: 1, maxQueue: 1, held: 1, class: High, wantErr: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewLimiter(tt.capacity, tt.maxQueue, 10*time.Millisecond)
+			for range tt.held {
+				if _, err := limiter.Acquire(context.Background(), Normal); err != nil {
+					t.Fatalf("holding a slot: %v", err)
+				}
+			}
+
+			release, err := limiter.Acquire(context.Background(), tt.class)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Acquire() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil {
+				release()
+			}
+			if inFlight, queued := limiter.Stats(); inFlight != tt.held || queued != 0 {
+				t.Errorf("Stats() = %d in flight, %d queued; want %d, 0", inFlight, queued, tt.held)
+			}
+		})
+	}
+}
+
+func TestLimiterReleasesToHighestPriority(t *testing.T) {
+	limiter := NewLimiter(1, 2, time.Second)
+	release, err := limiter.Acquire(context.Background(), Normal)
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	admitted := make(chan Class, 2)
+	wait := func(class Class) {
+		next, err := limiter.Acquire(context.Background(), class)
+		if err != nil {
+			t.Errorf("Acquire(%v) error = %v", class, err)
+			return
+		}
+		admitted <- class
+		next()
+	}
+	// Queue the best-effort waiter first so only priority can put the high one ahead
+	go wait(BestEffort)
+	waitForQueued(t, limiter, 1)
+	go wait(High)
+	waitForQueued(t, limiter, 2)
+
+	release()
+	for _, want := range []Class{High, BestEffort} {
+		if got := <-admitted; got != want {
+			t.Errorf("admitted %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLimiterMiddlewareRejectsAtCapacity(t *testing.T) {
+	limiter := NewLimiter(1, 0, 2*time.Second)
+	if _, err := limiter.Acquire(context.Background(), Normal); err != nil {
+		t.Fatalf("holding a slot: %v", err)
+	}
+	handler := limiter.Middleware(NewClassifier(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		path       string
+		wantStatus int
+	}{
+		{path: "/health", wantStatus: http.StatusNoContent},
+		{path: "/v1/completions", wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if recorder.Code != tt.wantStatus {
+			t.Errorf("%s status = %d, want %d", tt.path, recorder.Code, tt.wantStatus)
+		}
+		if tt.wantStatus == http.StatusServiceUnavailable && recorder.Header().Get("Retry-After") != "2" {
+			t.Errorf("%s Retry-After = %q, want %q", tt.path, recorder.Header().Get("Retry-After"), "2")
+		}
+	}
+}
+
+// waitForQueued blocks until the limiter has n queued waiters
+func waitForQueued(t *testing.T, limiter *Limiter, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, queued := limiter.Stats(); queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("limiter never had %d queued waiters", n)
+}
@@ -0,0 +1,206 @@
+/**
+ * @fileoverview Content-negotiated response rendering.
+ * Picks JSON, MessagePack, or XML from the request's Accept header and encodes the
+ * response in that format, with the same field names in every format: values are first
+ * encoded with the shared JSON casing, so a client switching formats sees the same
+ * document. Adding ?pretty=1 indents JSON and XML for reading in a terminal.
+ */
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+)
+
+// Supported media types
+const (
+	JSON    = "application/json"
+	MsgPack = "application/msgpack"
+	XML     = "application/xml"
+)
+
+// offered lists the media types that can be rendered, in order of preference; aliases are
+// answered with the type the client asked for
+var offered = []struct {
+	mediaType string
+	format    string
+}{
+	{JSON, JSON},
+	{MsgPack, MsgPack},
+	{"application/x-msgpack", MsgPack},
+	{"application/vnd.msgpack", MsgPack},
+	{XML, XML},
+	{"text/xml", XML},
+}
+
+// PrettyParam is the query parameter that requests indented output
+const PrettyParam = "pretty"
+
+// serializationObserver is implemented by response writers that record encoding time
+type serializationObserver interface {
+	ObserveSerialization(time.Duration)
+}
+
+// acceptRange is one media range from an Accept header
+type acceptRange struct {
+	mediaType string
+	q         float64
+}
+
+/**
+ * @description Returns the media type to answer r with: the acceptable type with the highest
+ * quality, preferring JSON on ties and when Accept is missing. Returns "" when the client
+ * accepts none of the supported types.
+ */
+func Negotiate(r *http.Request) string {
+	header := r.Header.Values("Accept")
+	if len(header) == 0 {
+		return JSON
+	}
+	ranges := parseAccept(strings.Join(header, ","))
+	if len(ranges) == 0 {
+		return JSON
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offered {
+		if q := quality(ranges, offer.mediaType); q > bestQ {
+			best, bestQ = offer.mediaType, q
+		}
+	}
+	return best
+}
+
+/**
+ * @description Writes v with status in the format negotiated from the Accept header,
+ * indented when the pretty query parameter is true. Successful responses carry an ETag and
+ * answer a matching If-None-Match with 304. Clients accepting no supported format
+ * get 406, and encoding failures a 500, both as problem details.
+ */
+func Write(w http.ResponseWriter, r *http.Request, status int, v any) error {
+	w.Header().Add("Vary", "Accept")
+	mediaType := Negotiate(r)
+	if mediaType == "" {
+		apierror.Write(w, r, http.StatusNotAcceptable, "supported response types are "+strings.Join([]string{JSON, MsgPack, XML}, ", "))
+		return nil
+	}
+	pretty, _ := strconv.ParseBool(r.URL.Query().Get(PrettyParam))
+
+	start := time.Now()
+	body, err := Marshal(mediaType, v, pretty)
+	if observer, ok := w.(serializationObserver); ok {
+		observer.ObserveSerialization(time.Since(start))
+	}
+	if err != nil {
+		apierror.Write(w, r, http.StatusInternalServerError, "failed to encode response")
+		return err
+	}
+
+	w.Header().Set("Content-Type", mediaType)
+	return httputil.WriteConditional(w, r, status, body, time.Time{})
+}
+
+/**
+ * @description Encodes v as mediaType, one of the supported types or their aliases.
+ */
+func Marshal(mediaType string, v any, pretty bool) ([]byte, error) {
+	format := formatOf(mediaType)
+	if format == "" {
+		return nil, fmt.Errorf("render: unsupported media type %q", mediaType)
+	}
+
+	data, err := jsoncase.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case JSON:
+		if !pretty {
+			return append(data, '\n'), nil
+		}
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, data, "", "  "); err != nil {
+			return nil, err
+		}
+		indented.WriteByte('\n')
+		return indented.Bytes(), nil
+	case MsgPack:
+		tree, err := decodeTree(data)
+		if err != nil {
+			return nil, err
+		}
+		return encodeMsgPack(tree)
+	default:
+		tree, err := decodeTree(data)
+		if err != nil {
+			return nil, err
+		}
+		return encodeXML(tree, pretty)
+	}
+}
+
+// parseAccept parses an Accept header, skipping malformed ranges
+func parseAccept(header string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+	return ranges
+}
+
+// quality returns the q-value the most specific matching range gives mediaType, or 0
+func quality(ranges []acceptRange, mediaType string) float64 {
+	typ, _, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, r := range ranges {
+		var s int
+		switch r.mediaType {
+		case mediaType:
+			s = 2
+		case typ + "/*":
+			s = 1
+		case "*/*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
+
+// formatOf returns the canonical format of a supported media type, or ""
+func formatOf(mediaType string) string {
+	for _, offer := range offered {
+		if offer.mediaType == mediaType {
+			return offer.format
+		}
+	}
+	return ""
+}
@@ -0,0 +1,185 @@
+/**
+ * @fileoverview Format-neutral document tree for the non-JSON encoders.
+ * The cased JSON encoding is decoded into objects that keep their key order, numbers
+ * that keep their exact text, and plain values, which the MessagePack and XML encoders
+ * then walk.
+ */
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// XMLRoot names the document element of XML responses
+const XMLRoot = "response"
+
+// xmlName matches keys usable as XML element names; other keys are written as
+// <item key="..."> elements
+var xmlName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9._-]*$`)
+
+// object is a JSON object with its keys in document order
+type object struct {
+	keys   []string
+	values []any
+}
+
+// decodeTree decodes JSON into objects, []any, json.Number, string, bool, and nil
+func decodeTree(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decodeValue(decoder)
+}
+
+// decodeValue decodes the next value from decoder
+func decodeValue(decoder *json.Decoder) (any, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case json.Delim('{'):
+		obj := &object{}
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			obj.keys = append(obj.keys, key.(string))
+			obj.values = append(obj.values, value)
+		}
+		_, err = decoder.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []any{}
+		for decoder.More() {
+			value, err := decodeValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err = decoder.Token()
+		return list, err
+	default:
+		return token, nil
+	}
+}
+
+// encodeMsgPack encodes a tree, writing integral numbers as integers
+func encodeMsgPack(tree any) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	if err := writeMsgPack(encoder, tree); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeMsgPack writes one tree value to encoder
+func writeMsgPack(encoder *msgpack.Encoder, value any) error {
+	switch v := value.(type) {
+	case *object:
+		if err := encoder.EncodeMapLen(len(v.keys)); err != nil {
+			return err
+		}
+		for i, key := range v.keys {
+			if err := encoder.EncodeString(key); err != nil {
+				return err
+			}
+			if err := writeMsgPack(encoder, v.values[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	case []any:
+		if err := encoder.EncodeArrayLen(len(v)); err != nil {
+			return err
+		}
+		for _, element := range v {
+			if err := writeMsgPack(encoder, element); err != nil {
+				return err
+			}
+		}
+		return nil
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return encoder.EncodeInt(n)
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return fmt.Errorf("render: invalid number %q: %w", v, err)
+		}
+		return encoder.EncodeFloat64(f)
+	default:
+		return encoder.Encode(v)
+	}
+}
+
+// encodeXML encodes a tree under an XMLRoot element; array elements become <item> elements
+func encodeXML(tree any, pretty bool) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	if pretty {
+		encoder.Indent("", "  ")
+	}
+	if err := writeXML(encoder, xml.StartElement{Name: xml.Name{Local: XMLRoot}}, tree); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// writeXML writes value as the element start
+func writeXML(encoder *xml.Encoder, start xml.StartElement, value any) error {
+	if err := encoder.EncodeToken(start); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case *object:
+		for i, key := range v.keys {
+			if err := writeXML(encoder, xmlElement(key), v.values[i]); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, element := range v {
+			if err := writeXML(encoder, xml.StartElement{Name: xml.Name{Local: "item"}}, element); err != nil {
+				return err
+			}
+		}
+	case nil:
+	default:
+		if err := encoder.EncodeToken(xml.CharData(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+	return encoder.EncodeToken(start.End())
+}
+
+// xmlElement returns the element for an object key, falling back to <item key="...">
+// for keys that are not valid element names
+func xmlElement(key string) xml.StartElement {
+	if xmlName.MatchString(key) && !strings.HasPrefix(strings.ToLower(key), "xml") {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: "item"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
@@ -0,0 +1,206 @@
+/**
+ * @fileoverview Data retention manager.
+ * Periodically purges expired records (sessions, idempotency keys, job records, usage data,
+ * conversation history) according to per-type maximum ages from configuration. Each data
+ * type registers a purge target; a dry-run mode reports what would be deleted without
+ * deleting it, and purge counts are exported as metrics.
+ */
+
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/store"
+)
+
+// Well-known data types with retention policies
+const (
+	TypeSessions        = "sessions"
+	TypeIdempotencyKeys = "idempotency_keys"
+	TypeJobs            = "jobs"
+	TypeUsage           = "usage"
+	TypeConversations   = "conversations"
+)
+
+// DefaultInterval is how often purges run
+const DefaultInterval = time.Hour
+
+var (
+	purgedRecords = metrics.NewCounterVec("retention_purged_records_total", "Records deleted by the retention manager.", "type")
+	purgeFailures = metrics.NewCounterVec("retention_purge_failures_total", "Failed retention purges.", "type")
+)
+
+// Target deletes records of one type older than cutoff, or only counts them when dryRun is set
+type Target func(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error)
+
+// Result is the outcome of purging one type
+type Result struct {
+	Type    string        `json:"type"`
+	Cutoff  time.Time     `json:"cutoff"`
+	Records int64         `json:"records"`
+	DryRun  bool          `json:"dry_run"`
+	Elapsed time.Duration `json:"elapsed"`
+	Err     error         `json:"-"`
+}
+
+// Config configures a Manager
+type Config struct {
+	// Policies maps a data type to its maximum age
+	Policies map[string]time.Duration
+	Interval time.Duration
+	DryRun   bool
+}
+
+// Manager runs retention purges
+type Manager struct {
+	config Config
+
+	mu      sync.Mutex
+	targets map[string]Target
+}
+
+/**
+ * @description Creates a manager; register targets before calling Run.
+ */
+func New(config Config) *Manager {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Manager{config: config, targets: make(map[string]Target)}
+}
+
+/**
+ * @description Registers the purge target for a data type.
+ */
+func (m *Manager) Register(dataType string, target Target) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.targets[dataType] = target
+}
+
+/**
+ * @description Registers a store backend's expired idempotency keys as TypeIdempotencyKeys.
+ * The cutoff is applied to expiry time, so keys are kept for their TTL plus the policy age.
+ */
+func (m *Manager) RegisterStore(purger store.Purger) {
+	m.Register(TypeIdempotencyKeys, purger.PurgeExpired)
+}
+
+/**
+ * @description Returns policy types that have no registered target and will never be purged.
+ */
+func (m *Manager) Unmanaged() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var missing []string
+	for dataType := range m.config.Policies {
+		if _, ok := m.targets[dataType]; !ok {
+			missing = append(missing, dataType)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+/**
+ * @description Purges every type that has both a policy and a target, in name order.
+ */
+func (m *Manager) RunOnce(ctx context.Context) []Result {
+	m.mu.Lock()
+	types := make([]string, 0, len(m.targets))
+	for dataType := range m.targets {
+		if _, ok := m.config.Policies[dataType]; ok {
+			types = append(types, dataType)
+		}
+	}
+	targets := make(map[string]Target, len(m.targets))
+	for dataType, target := range m.targets {
+		targets[dataType] = target
+	}
+	m.mu.Unlock()
+	sort.Strings(types)
+
+	results := make([]Result, 0, len(types))
+	for _, dataType := range types {
+		start := time.Now()
+		cutoff := start.Add(-m.config.Policies[dataType])
+		records, err := targets[dataType](ctx, cutoff, m.config.DryRun)
+		result := Result{Type: dataType, Cutoff: cutoff, Records: records, DryRun: m.config.DryRun, Elapsed: time.Since(start), Err: err}
+		results = append(results, result)
+
+		switch {
+		case err != nil:
+			purgeFailures.With(dataType).Add(1)
+			log.Printf("Retention purge of %s failed: %v", dataType, err)
+		case m.config.DryRun:
+			log.Printf("Retention dry run: would purge %d %s older than %s", records, dataType, cutoff.UTC().Format(time.RFC3339))
+		default:
+			purgedRecords.With(dataType).Add(records)
+			if records > 0 {
+				log.Printf("Retention purged %d %s older than %s", records, dataType, cutoff.UTC().Format(time.RFC3339))
+			}
+		}
+	}
+	return results
+}
+
+/**
+ * @description Purges immediately and then every Interval until ctx is cancelled.
+ */
+func (m *Manager) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+	for {
+		m.RunOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+/**
+ * @description Parses "type=age,..." policies, e.g. "sessions=30d,idempotency_keys=24h".
+ * Ages accept Go durations plus a "d" suffix for days.
+ */
+func ParsePolicies(spec string) (map[string]time.Duration, error) {
+	policies := make(map[string]time.Duration)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		dataType, age, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid retention policy %q: expected type=age", entry)
+		}
+		duration, err := parseAge(strings.TrimSpace(age))
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid retention age %q for %s", age, dataType)
+		}
+		policies[strings.TrimSpace(dataType)] = duration
+	}
+	return policies, nil
+}
+
+// parseAge parses a Go duration or a whole number of days such as "30d"
+func parseAge(age string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(age, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(age)
+}
@@ -0,0 +1,203 @@
+/**
+ * @fileoverview Weighted canary routing with a time-based ramp and automatic rollback.
+ * Splits traffic between a stable and a canary handler, raising the canary's share over
+ * time, and sends all traffic back to stable once the canary's error rate or latency
+ * exceeds its thresholds within an evaluation window.
+ */
+
+package routes
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+)
+
+// VariantHeader reports which variant served a response
+const VariantHeader = "X-Route-Variant"
+
+const (
+	// DefaultCanaryWindow is the evaluation window for canary error rate and latency
+	DefaultCanaryWindow = time.Minute
+	// DefaultCanaryMinRequests is the sample size required before thresholds are enforced
+	DefaultCanaryMinRequests = 20
+)
+
+// CanaryConfig controls traffic split and rollback thresholds
+type CanaryConfig struct {
+	// StartWeight and EndWeight are canary traffic percentages (0-100); EndWeight defaults to StartWeight
+	StartWeight float64
+	EndWeight   float64
+	// RampDuration moves the weight linearly from StartWeight to EndWeight; zero starts at EndWeight
+	RampDuration time.Duration
+	// MaxErrorRate is the highest tolerated fraction (0-1) of canary 5xx responses; zero disables it
+	MaxErrorRate float64
+	// MaxLatency is the highest tolerated mean canary latency; zero disables it
+	MaxLatency  time.Duration
+	MinRequests int
+	Window      time.Duration
+	// OnRollback is called once when the canary is rolled back
+	OnRollback func(reason string)
+}
+
+// CanaryState is a snapshot of a canary's progress
+type CanaryState struct {
+	Weight     float64 `json:"weight"`
+	RolledBack bool    `json:"rolled_back"`
+	Reason     string  `json:"reason,omitempty"`
+	Requests   int     `json:"window_requests"`
+	Errors     int     `json:"window_errors"`
+	MeanMs     int64   `json:"window_mean_latency_ms"`
+}
+
+// Canary routes a share of requests to a canary handler
+type Canary struct {
+	stable http.Handler
+	canary http.Handler
+	config CanaryConfig
+	start  time.Time
+
+	mu          sync.Mutex
+	rolledBack  bool
+	reason      string
+	windowStart time.Time
+	requests    int
+	errors      int
+	latency     time.Duration
+}
+
+/**
+ * @description Creates a canary splitter; the ramp starts immediately.
+ */
+func NewCanary(stable, canary http.Handler, config CanaryConfig) *Canary {
+	if config.EndWeight == 0 {
+		config.EndWeight = config.StartWeight
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultCanaryWindow
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = DefaultCanaryMinRequests
+	}
+	now := time.Now()
+	return &Canary{stable: stable, canary: canary, config: config, start: now, windowStart: now}
+}
+
+/**
+ * @description Serves the request from the canary with probability Weight()/100, otherwise from stable.
+ */
+func (c *Canary) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if rand.Float64()*100 >= c.Weight() {
+		w.Header().Set(VariantHeader, "stable")
+		c.stable.ServeHTTP(w, r)
+		return
+	}
+
+	w.Header().Set(VariantHeader, "canary")
+	rw := httputil.NewResponseWriter(w)
+	start := time.Now()
+	c.canary.ServeHTTP(rw, r)
+	c.observe(rw.Status() >= http.StatusInternalServerError, time.Since(start))
+}
+
+/**
+ * @description Returns the current canary traffic percentage, or 0 after a rollback.
+ */
+func (c *Canary) Weight() float64 {
+	c.mu.Lock()
+	rolledBack := c.rolledBack
+	c.mu.Unlock()
+	if rolledBack {
+		return 0
+	}
+
+	if c.config.RampDuration <= 0 {
+		return c.config.EndWeight
+	}
+	progress := min(float64(time.Since(c.start))/float64(c.config.RampDuration), 1)
+	return c.config.StartWeight + (c.config.EndWeight-c.config.StartWeight)*progress
+}
+
+/**
+ * @description Sends all traffic to stable until Reset is called.
+ */
+func (c *Canary) Rollback(reason string) {
+	c.mu.Lock()
+	if c.rolledBack {
+		c.mu.Unlock()
+		return
+	}
+	c.rolledBack = true
+	c.reason = reason
+	c.mu.Unlock()
+
+	if c.config.OnRollback != nil {
+		c.config.OnRollback(reason)
+	}
+}
+
+/**
+ * @description Clears a rollback and restarts the ramp and evaluation window.
+ */
+func (c *Canary) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rolledBack, c.reason = false, ""
+	c.start = time.Now()
+	c.resetWindow(c.start)
+}
+
+/**
+ * @description Returns the current weight, rollback state, and evaluation window counters.
+ */
+func (c *Canary) State() CanaryState {
+	weight := c.Weight()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	state := CanaryState{Weight: weight, RolledBack: c.rolledBack, Reason: c.reason, Requests: c.requests, Errors: c.errors}
+	if c.requests > 0 {
+		state.MeanMs = (c.latency / time.Duration(c.requests)).Milliseconds()
+	}
+	return state
+}
+
+// observe records a canary response and rolls back when a threshold is exceeded
+func (c *Canary) observe(failed bool, latency time.Duration) {
+	c.mu.Lock()
+	now := time.Now()
+	if now.Sub(c.windowStart) > c.config.Window {
+		c.resetWindow(now)
+	}
+	c.requests++
+	c.latency += latency
+	if failed {
+		c.errors++
+	}
+
+	var reason string
+	if c.requests >= c.config.MinRequests && !c.rolledBack {
+		errorRate := float64(c.errors) / float64(c.requests)
+		mean := c.latency / time.Duration(c.requests)
+		switch {
+		case c.config.MaxErrorRate > 0 && errorRate > c.config.MaxErrorRate:
+			reason = fmt.Sprintf("error rate %.1f%% exceeds %.1f%% over %d requests", errorRate*100, c.config.MaxErrorRate*100, c.requests)
+		case c.config.MaxLatency > 0 && mean > c.config.MaxLatency:
+			reason = fmt.Sprintf("mean latency %v exceeds %v over %d requests", mean, c.config.MaxLatency, c.requests)
+		}
+	}
+	c.mu.Unlock()
+
+	if reason != "" {
+		c.Rollback(reason)
+	}
+}
+
+// resetWindow starts a new evaluation window; callers hold c.mu
+func (c *Canary) resetWindow(now time.Time) {
+	c.windowStart = now
+	c.requests, c.errors, c.latency = 0, 0, 0
+}
@@ -0,0 +1,234 @@
+/**
+ * @fileoverview Route registry with startup-time validation.
+ * Collects every route registration with its source and middleware stack, then detects
+ * duplicate and conflicting patterns, shadowed routes, and misordered middleware before
+ * anything is mounted, so startup fails with a clear report instead of surprising behavior.
+ */
+
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultMiddlewareOrder is the required relative order of well-known middleware, outermost first
+var DefaultMiddlewareOrder = []string{"request-id", "logging", "recovery", "auth", "rate-limit"}
+
+// Registrar is implemented by *http.ServeMux, *Scope, and anything else that accepts routes
+type Registrar interface {
+	Handle(pattern string, handler http.Handler)
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// Registration is a single route with the metadata needed for validation
+type Registration struct {
+	Pattern string
+	Handler http.Handler
+	// Source identifies where the route came from, e.g. "builtin" or "routes file"
+	Source string
+	// Middleware names the middleware wrapping the handler, outermost first
+	Middleware []string
+}
+
+// Report lists validation problems; Errors fail startup, Warnings are informational
+type Report struct {
+	Errors   []string
+	Warnings []string
+}
+
+// Registry collects registrations in order for validation and mounting
+type Registry struct {
+	registrations   []Registration
+	middlewareOrder []string
+}
+
+// Scope registers routes into a Registry with a fixed source and middleware stack
+type Scope struct {
+	registry   *Registry
+	source     string
+	middleware []string
+	// prefix and wrap are set on scopes created by Version
+	prefix string
+	wrap   func(http.Handler) http.Handler
+}
+
+/**
+ * @description Creates an empty registry that enforces DefaultMiddlewareOrder.
+ */
+func NewRegistry() *Registry {
+	return &Registry{middlewareOrder: DefaultMiddlewareOrder}
+}
+
+/**
+ * @description Replaces the required middleware order, outermost first.
+ */
+func (reg *Registry) SetMiddlewareOrder(order []string) {
+	reg.middlewareOrder = order
+}
+
+/**
+ * @description Records a registration without mounting it.
+ */
+func (reg *Registry) Add(registration Registration) {
+	reg.registrations = append(reg.registrations, registration)
+}
+
+/**
+ * @description Returns a Registrar that tags routes with source and middleware names.
+ */
+func (reg *Registry) Scope(source string, middleware ...string) *Scope {
+	return &Scope{registry: reg, source: source, middleware: middleware}
+}
+
+/**
+ * @description Returns a copy of all registrations in registration order.
+ */
+func (reg *Registry) Registrations() []Registration {
+	return append([]Registration(nil), reg.registrations...)
+}
+
+/**
+ * @description Records a handler under the scope's source, middleware, and version prefix.
+ */
+func (s *Scope) Handle(pattern string, handler http.Handler) {
+	if s.wrap != nil {
+		handler = s.wrap(handler)
+	}
+	s.registry.Add(Registration{
+		Pattern:    s.Pattern(pattern),
+		Handler:    handler,
+		Source:     s.source,
+		Middleware: s.middleware,
+	})
+}
+
+/**
+ * @description Records a handler function under the scope's source and middleware.
+ */
+func (s *Scope) HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request)) {
+	s.Handle(pattern, http.HandlerFunc(handler))
+}
+
+/**
+ * @description Validates all registrations and returns a report of conflicts,
+ * shadowed routes, and middleware ordering violations.
+ */
+func (reg *Registry) Validate() Report {
+	var report Report
+	reg.checkConflicts(&report)
+	reg.checkShadowing(&report)
+	reg.checkMiddlewareOrder(&report)
+	return report
+}
+
+/**
+ * @description Validates the registry and mounts every route on mux.
+ * Returns an error containing the full report if validation found errors.
+ */
+func (reg *Registry) Mount(mux *http.ServeMux) (Report, error) {
+	report := reg.Validate()
+	if len(report.Errors) > 0 {
+		return report, fmt.Errorf("route validation failed:\n  - %s", strings.Join(report.Errors, "\n  - "))
+	}
+
+	for _, registration := range reg.registrations {
+		mux.Handle(registration.Pattern, registration.Handler)
+	}
+	return report, nil
+}
+
+// checkConflicts reports duplicates and patterns that ServeMux would reject
+func (reg *Registry) checkConflicts(report *Report) {
+	firstSource := make(map[string]string)
+	scratch := http.NewServeMux()
+
+	for _, registration := range reg.registrations {
+		pattern := normalizePattern(registration.Pattern)
+		if source, ok := firstSource[pattern]; ok {
+			report.Errors = append(report.Errors, fmt.Sprintf("duplicate route %q registered by %s and %s",
+				registration.Pattern, source, registration.Source))
+			continue
+		}
+		firstSource[pattern] = registration.Source
+
+		if err := tryRegister(scratch, registration.Pattern); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("route %q from %s: %v",
+				registration.Pattern, registration.Source, err))
+		}
+	}
+}
+
+// checkShadowing warns when a subtree route from one source captures routes from another
+func (reg *Registry) checkShadowing(report *Report) {
+	for _, outer := range reg.registrations {
+		outerMethod, outerPath := splitPattern(outer.Pattern)
+		if !strings.HasSuffix(outerPath, "/") || outerPath == "/" {
+			continue
+		}
+
+		for _, inner := range reg.registrations {
+			innerMethod, innerPath := splitPattern(inner.Pattern)
+			if inner.Source == outer.Source || innerPath == outerPath || !strings.HasPrefix(innerPath, outerPath) {
+				continue
+			}
+			if outerMethod != "" && innerMethod != "" && outerMethod != innerMethod {
+				continue
+			}
+			report.Warnings = append(report.Warnings, fmt.Sprintf("route %q from %s takes precedence over part of %q from %s",
+				inner.Pattern, inner.Source, outer.Pattern, outer.Source))
+		}
+	}
+}
+
+// checkMiddlewareOrder reports middleware that appears out of the required relative order
+func (reg *Registry) checkMiddlewareOrder(report *Report) {
+	rank := make(map[string]int, len(reg.middlewareOrder))
+	for i, name := range reg.middlewareOrder {
+		rank[name] = i
+	}
+
+	for _, registration := range reg.registrations {
+		previousName, previousRank := "", -1
+		for _, name := range registration.Middleware {
+			current, ok := rank[name]
+			if !ok {
+				continue
+			}
+			if current < previousRank {
+				report.Errors = append(report.Errors, fmt.Sprintf("route %q from %s: middleware %q must wrap %q, not run inside it",
+					registration.Pattern, registration.Source, name, previousName))
+			}
+			previousName, previousRank = name, current
+		}
+	}
+}
+
+// tryRegister registers pattern on a scratch mux, converting ServeMux panics into errors
+func tryRegister(mux *http.ServeMux, pattern string) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+	mux.Handle(pattern, http.NotFoundHandler())
+	return nil
+}
+
+// splitPattern separates an optional method from the path of a ServeMux pattern
+func splitPattern(pattern string) (method, path string) {
+	if method, path, found := strings.Cut(pattern, " "); found {
+		return method, strings.TrimSpace(path)
+	}
+	return "", pattern
+}
+
+// normalizePattern collapses whitespace between method and path for duplicate detection
+func normalizePattern(pattern string) string {
+	method, path := splitPattern(pattern)
+	if method == "" {
+		return path
+	}
+	return method + " " + path
+}
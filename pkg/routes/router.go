@@ -0,0 +1,122 @@
+/**
+ * @fileoverview Router built on ServeMux pattern matching.
+ * Mounts a validated Registry onto a ServeMux, so routes use Go's pattern syntax
+ * ("GET /v1/projects/{id}", "/static/", "/{$}"), and replaces ServeMux's plain-text
+ * 404 and 405 responses with problem details, keeping the Allow header on 405s. The mounted
+ * routes can be listed for discovery and debugging.
+ */
+
+package routes
+
+import (
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+)
+
+// RouteInfo describes one mounted route
+type RouteInfo struct {
+	// Method is empty when the route matches every method
+	Method string `json:"method,omitempty"`
+	Path   string `json:"path"`
+	Source string `json:"source"`
+}
+
+// RoutesResponse is the JSON body served by Router.ListHandler
+type RoutesResponse struct {
+	Routes []RouteInfo `json:"routes"`
+}
+
+// Router dispatches requests to mounted routes
+type Router struct {
+	mux    *http.ServeMux
+	routes []RouteInfo
+}
+
+/**
+ * @description Validates the registry and mounts every route on a new Router.
+ * Returns an error containing the full report if validation found errors.
+ */
+func (reg *Registry) Router() (*Router, Report, error) {
+	mux := http.NewServeMux()
+	report, err := reg.Mount(mux)
+	if err != nil {
+		return nil, report, err
+	}
+
+	router := &Router{mux: mux}
+	for _, registration := range reg.registrations {
+		method, path := splitPattern(registration.Pattern)
+		router.routes = append(router.routes, RouteInfo{Method: method, Path: path, Source: registration.Source})
+	}
+	sort.SliceStable(router.routes, func(i, j int) bool {
+		if router.routes[i].Path != router.routes[j].Path {
+			return router.routes[i].Path < router.routes[j].Path
+		}
+		return router.routes[i].Method < router.routes[j].Method
+	})
+	return router, report, nil
+}
+
+/**
+ * @description Serves the matching route, or a JSON 404 or 405 when no route matches.
+ */
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, pattern := rt.mux.Handler(r)
+	if pattern != "" {
+		rt.mux.ServeHTTP(w, r)
+		return
+	}
+
+	// Let ServeMux decide between 404, 405 (setting Allow), and redirects, then replace its body
+	capture := &statusCapture{header: w.Header()}
+	handler.ServeHTTP(capture, r)
+	switch capture.status {
+	case http.StatusNotFound:
+		apierror.Write(w, r, http.StatusNotFound, "no route for "+r.URL.Path)
+	case http.StatusMethodNotAllowed:
+		apierror.Write(w, r, http.StatusMethodNotAllowed,
+			"method "+r.Method+" not allowed for "+r.URL.Path+"; allowed: "+w.Header().Get("Allow"))
+	default:
+		w.WriteHeader(capture.status)
+	}
+}
+
+/**
+ * @description Returns the mounted routes sorted by path and method.
+ */
+func (rt *Router) Routes() []RouteInfo {
+	return append([]RouteInfo(nil), rt.routes...)
+}
+
+/**
+ * @description Serves the mounted routes as JSON.
+ */
+func (rt *Router) ListHandler(w http.ResponseWriter, r *http.Request) {
+	jsoncase.Write(w, http.StatusOK, RoutesResponse{Routes: rt.Routes()})
+}
+
+// statusCapture records the status ServeMux chose for an unmatched request, sharing the
+// real header map so Allow and Location survive, and discards the plain-text body
+type statusCapture struct {
+	header http.Header
+	status int
+}
+
+func (c *statusCapture) Header() http.Header {
+	return c.header
+}
+
+func (c *statusCapture) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+}
+
+func (c *statusCapture) Write(p []byte) (int, error) {
+	c.WriteHeader(http.StatusOK)
+	return io.Discard.Write(p)
+}
@@ -0,0 +1,316 @@
+/**
+ * @fileoverview Declarative route definitions loaded from a configuration file.
+ * Supports static responses, reverse proxies, and redirects so simple endpoints
+ * can be added to the server without writing Go handlers.
+ */
+
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/deadline"
+)
+
+// Route types supported in the routes file
+const (
+	TypeStatic   = "static"
+	TypeProxy    = "proxy"
+	TypeRedirect = "redirect"
+)
+
+// Route declares a single endpoint mounted at startup
+type Route struct {
+	// Path is the ServeMux pattern the route is mounted at
+	Path string `json:"path"`
+	// Version mounts the route under an API version prefix such as "v1"
+	Version string `json:"version,omitempty"`
+	// Type is one of "static", "proxy", or "redirect"
+	Type string `json:"type"`
+	// Status is the response status for static routes and redirects
+	Status int `json:"status,omitempty"`
+	// Body is the response body for static routes
+	Body string `json:"body,omitempty"`
+	// ContentType is the Content-Type for static routes; defaults to text/plain
+	ContentType string `json:"content_type,omitempty"`
+	// Headers are added to every response from the route
+	Headers map[string]string `json:"headers,omitempty"`
+	// Target is the upstream URL for proxies or the location for redirects
+	Target string `json:"target,omitempty"`
+	// StripPrefix removes the given prefix from the request path before proxying
+	StripPrefix string `json:"strip_prefix,omitempty"`
+	// Canary sends a share of proxy traffic to a second upstream version
+	Canary *CanaryRoute `json:"canary,omitempty"`
+	// MaxBodyBytes overrides the server's request body limit for this route; -1 removes the limit
+	MaxBodyBytes int64 `json:"max_body_bytes,omitempty"`
+	// TimeoutMs overrides the server's request timeout for this route; -1 removes the timeout
+	TimeoutMs int64 `json:"timeout_ms,omitempty"`
+}
+
+// CanaryRoute declares a canary upstream for a proxy route
+type CanaryRoute struct {
+	// Target is the canary upstream URL
+	Target string `json:"target"`
+	// Weight is the initial canary traffic percentage
+	Weight float64 `json:"weight"`
+	// EndWeight is the percentage reached after RampSeconds; defaults to Weight
+	EndWeight   float64 `json:"end_weight,omitempty"`
+	RampSeconds int     `json:"ramp_seconds,omitempty"`
+	// MaxErrorRate (0-1) and MaxLatencyMs trigger an automatic rollback when exceeded
+	MaxErrorRate float64 `json:"max_error_rate,omitempty"`
+	MaxLatencyMs int     `json:"max_latency_ms,omitempty"`
+	MinRequests  int     `json:"min_requests,omitempty"`
+}
+
+// File is the top-level structure of a routes file
+type File struct {
+	Routes []Route `json:"routes"`
+}
+
+/**
+ * @description Reads and validates route declarations from a JSON file.
+ * Returns an error describing the first invalid route, including its index and path.
+ */
+func LoadFile(path string) ([]Route, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routes file %s: %w", path, err)
+	}
+
+	var file File
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse routes file %s: %w", path, err)
+	}
+
+	if err := Validate(file.Routes); err != nil {
+		return nil, fmt.Errorf("invalid routes file %s: %w", path, err)
+	}
+
+	return file.Routes, nil
+}
+
+/**
+ * @description Validates route declarations and rejects duplicate paths within a version.
+ * Duplicate patterns would otherwise panic when registered on a ServeMux.
+ */
+func Validate(routes []Route) error {
+	seen := make(map[string]bool, len(routes))
+
+	for i, route := range routes {
+		if !strings.HasPrefix(patternPath(route.Path), "/") {
+			return fmt.Errorf("route %d: path %q must start with /", i, route.Path)
+		}
+		if route.Version != "" {
+			if err := ValidateVersionName(route.Version); err != nil {
+				return fmt.Errorf("route %d (%s): %w", i, route.Path, err)
+			}
+		}
+		key := route.Version + " " + route.Path
+		if seen[key] {
+			return fmt.Errorf("route %d: duplicate path %q", i, route.Path)
+		}
+		seen[key] = true
+		if route.MaxBodyBytes < -1 {
+			return fmt.Errorf("route %d (%s): max_body_bytes must be positive, or -1 for no limit", i, route.Path)
+		}
+		if route.TimeoutMs < -1 {
+			return fmt.Errorf("route %d (%s): timeout_ms must be positive, or -1 for no timeout", i, route.Path)
+		}
+
+		switch route.Type {
+		case TypeStatic:
+			if route.Canary != nil {
+				return fmt.Errorf("route %d (%s): canary is only supported on proxy routes", i, route.Path)
+			}
+			if route.Status != 0 && (route.Status < 100 || route.Status > 599) {
+				return fmt.Errorf("route %d (%s): invalid status %d", i, route.Path, route.Status)
+			}
+		case TypeProxy:
+			target, err := url.Parse(route.Target)
+			if err != nil || target.Scheme == "" || target.Host == "" {
+				return fmt.Errorf("route %d (%s): proxy target %q must be an absolute URL", i, route.Path, route.Target)
+			}
+			if err := validateCanary(route.Canary); err != nil {
+				return fmt.Errorf("route %d (%s): %w", i, route.Path, err)
+			}
+		case TypeRedirect:
+			if route.Canary != nil {
+				return fmt.Errorf("route %d (%s): canary is only supported on proxy routes", i, route.Path)
+			}
+			if route.Target == "" {
+				return fmt.Errorf("route %d (%s): redirect target is required", i, route.Path)
+			}
+			if route.Status != 0 && (route.Status < 300 || route.Status > 399) {
+				return fmt.Errorf("route %d (%s): redirect status %d is not a 3xx code", i, route.Path, route.Status)
+			}
+		default:
+			return fmt.Errorf("route %d (%s): unknown type %q", i, route.Path, route.Type)
+		}
+	}
+
+	return nil
+}
+
+/**
+ * @description Builds the HTTP handler for a validated route declaration.
+ * Response headers declared on the route are applied before the handler runs.
+ */
+func Handler(route Route) (http.Handler, error) {
+	var handler http.Handler
+
+	switch route.Type {
+	case TypeStatic:
+		handler = staticHandler(route)
+	case TypeProxy:
+		proxy, err := proxyHandler(route)
+		if err != nil {
+			return nil, err
+		}
+		handler = proxy
+	case TypeRedirect:
+		status := route.Status
+		if status == 0 {
+			status = http.StatusFound
+		}
+		handler = http.RedirectHandler(route.Target, status)
+	default:
+		return nil, fmt.Errorf("unknown route type %q", route.Type)
+	}
+
+	if len(route.Headers) == 0 {
+		return handler, nil
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for key, value := range route.Headers {
+			w.Header().Set(key, value)
+		}
+		handler.ServeHTTP(w, r)
+	}), nil
+}
+
+/**
+ * @description Registers every route on the registrar, wrapping each handler with the given wrapper.
+ * The wrapper lets callers apply the same middleware used for built-in endpoints.
+ */
+func Mount(mux Registrar, routes []Route, wrap func(http.Handler) http.Handler) error {
+	for _, route := range routes {
+		handler, err := Handler(route)
+		if err != nil {
+			return fmt.Errorf("route %s: %w", route.Path, err)
+		}
+		if wrap != nil {
+			handler = wrap(handler)
+		}
+		mux.Handle(route.Path, handler)
+	}
+	return nil
+}
+
+// staticHandler returns a fixed response body and status
+func staticHandler(route Route) http.Handler {
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	contentType := route.ContentType
+	if contentType == "" {
+		contentType = "text/plain; charset=utf-8"
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		w.WriteHeader(status)
+		w.Write([]byte(route.Body))
+	})
+}
+
+// proxyHandler forwards requests to the route's upstream target
+func proxyHandler(route Route) (http.Handler, error) {
+	target, err := url.Parse(route.Target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy target %q: %w", route.Target, err)
+	}
+
+	var handler http.Handler = newReverseProxy(target)
+	if route.Canary != nil {
+		canaryTarget, err := url.Parse(route.Canary.Target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid canary target %q: %w", route.Canary.Target, err)
+		}
+		handler = NewCanary(handler, newReverseProxy(canaryTarget), CanaryConfig{
+			StartWeight:  route.Canary.Weight,
+			EndWeight:    route.Canary.EndWeight,
+			RampDuration: time.Duration(route.Canary.RampSeconds) * time.Second,
+			MaxErrorRate: route.Canary.MaxErrorRate,
+			MaxLatency:   time.Duration(route.Canary.MaxLatencyMs) * time.Millisecond,
+			MinRequests:  route.Canary.MinRequests,
+			OnRollback: func(reason string) {
+				log.Printf("Canary %s for route %s rolled back: %s", route.Canary.Target, route.Path, reason)
+			},
+		})
+	}
+
+	// Forward the remaining deadline budget rather than the caller's original one
+	handler = deadline.Propagate(handler)
+
+	if route.StripPrefix == "" {
+		return handler, nil
+	}
+	return http.StripPrefix(route.StripPrefix, handler), nil
+}
+
+// newReverseProxy proxies to target, answering 504 instead of 502 when the request deadline expires
+func newReverseProxy(target *url.URL) *httputil.ReverseProxy {
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	proxy.ErrorHandler = proxyErrorHandler
+	return proxy
+}
+
+// proxyErrorHandler logs an upstream failure and answers 504 if the deadline expired or the
+// transport timed out waiting for the upstream, 502 otherwise
+func proxyErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("Proxy error for %s: %v", r.URL.Path, err)
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		apierror.Write(w, r, http.StatusGatewayTimeout, "upstream did not respond before the request deadline")
+		return
+	}
+	apierror.Write(w, r, http.StatusBadGateway, "upstream request failed")
+}
+
+// validateCanary checks an optional canary declaration
+func validateCanary(canary *CanaryRoute) error {
+	if canary == nil {
+		return nil
+	}
+	target, err := url.Parse(canary.Target)
+	if err != nil || target.Scheme == "" || target.Host == "" {
+		return fmt.Errorf("canary target %q must be an absolute URL", canary.Target)
+	}
+	if canary.Weight < 0 || canary.Weight > 100 || canary.EndWeight < 0 || canary.EndWeight > 100 {
+		return fmt.Errorf("canary weights must be between 0 and 100")
+	}
+	if canary.MaxErrorRate < 0 || canary.MaxErrorRate > 1 {
+		return fmt.Errorf("canary max_error_rate must be between 0 and 1")
+	}
+	return nil
+}
+
+// patternPath strips an optional "METHOD " prefix from a ServeMux pattern
+func patternPath(pattern string) string {
+	_, path := splitPattern(pattern)
+	return path
+}
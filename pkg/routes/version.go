@@ -0,0 +1,150 @@
+/**
+ * @fileoverview API version prefixes for route groups.
+ * A versioned scope mounts its routes under /v1, /v2, and so on, and tags every
+ * response from a deprecated version with Deprecation (RFC 9745), Sunset (RFC 8594),
+ * and Link headers, so clients learn about removals before they happen.
+ */
+
+package routes
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// VersionDateLayout is the date format accepted for deprecation and sunset dates
+const VersionDateLayout = "2006-01-02"
+
+// versionName matches API version names such as v1 and v2
+var versionName = regexp.MustCompile(`^v[1-9][0-9]*$`)
+
+// APIVersion describes an API version and its deprecation schedule
+type APIVersion struct {
+	// Name is the path prefix segment, e.g. "v1"
+	Name string
+	// Deprecated is when the version was deprecated; zero while it is current
+	Deprecated time.Time
+	// Sunset is when the version will be removed; zero when no removal is scheduled
+	Sunset time.Time
+	// Link points clients at migration documentation for a deprecated version
+	Link string
+}
+
+/**
+ * @description Validates an API version name of the form v1, v2, and so on.
+ */
+func ValidateVersionName(name string) error {
+	if !versionName.MatchString(name) {
+		return fmt.Errorf("API version %q must look like v1", name)
+	}
+	return nil
+}
+
+/**
+ * @description Builds the version policies from "name=YYYY-MM-DD" entries giving when each
+ * version was deprecated and when it is sunset; link is the migration guide for all of them.
+ */
+func ParseVersions(deprecated, sunset []string, link string) (map[string]APIVersion, error) {
+	versions := make(map[string]APIVersion)
+	parse := func(key string, entries []string, set func(*APIVersion, time.Time)) error {
+		for _, entry := range entries {
+			name, date, found := strings.Cut(entry, "=")
+			name = strings.TrimSpace(name)
+			if err := ValidateVersionName(name); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			if !found {
+				return fmt.Errorf("%s: entry %q must be name=%s", key, entry, VersionDateLayout)
+			}
+			when, err := time.Parse(VersionDateLayout, strings.TrimSpace(date))
+			if err != nil {
+				return fmt.Errorf("%s: entry %q has an invalid date: %w", key, entry, err)
+			}
+			version := versions[name]
+			version.Name = name
+			version.Link = link
+			set(&version, when)
+			versions[name] = version
+		}
+		return nil
+	}
+
+	if err := parse("deprecated_versions", deprecated, func(v *APIVersion, when time.Time) { v.Deprecated = when }); err != nil {
+		return nil, err
+	}
+	if err := parse("sunset_versions", sunset, func(v *APIVersion, when time.Time) { v.Sunset = when }); err != nil {
+		return nil, err
+	}
+	for name, version := range versions {
+		if !version.Deprecated.IsZero() && !version.Sunset.IsZero() && version.Sunset.Before(version.Deprecated) {
+			return nil, fmt.Errorf("sunset_versions: %s is sunset before it is deprecated", name)
+		}
+	}
+	return versions, nil
+}
+
+/**
+ * @description Returns the path prefix routes in the version are mounted under.
+ */
+func (v APIVersion) Prefix() string {
+	return "/" + v.Name
+}
+
+/**
+ * @description Wraps next so every response carries the version's deprecation headers.
+ * Returns next unchanged for a version with neither a deprecation nor a sunset date.
+ */
+func (v APIVersion) Middleware(next http.Handler) http.Handler {
+	if v.Deprecated.IsZero() && v.Sunset.IsZero() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		if !v.Deprecated.IsZero() {
+			header.Set("Deprecation", fmt.Sprintf("@%d", v.Deprecated.Unix()))
+		}
+		if !v.Sunset.IsZero() {
+			header.Set("Sunset", v.Sunset.UTC().Format(http.TimeFormat))
+		}
+		if v.Link != "" {
+			header.Add("Link", fmt.Sprintf("<%s>; rel=\"deprecation\"", v.Link))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+/**
+ * @description Returns a scope registering routes under the version's prefix, with the
+ * same source and middleware, and deprecation headers added when the version is deprecated.
+ */
+func (s *Scope) Version(version APIVersion) *Scope {
+	return &Scope{
+		registry:   s.registry,
+		source:     s.source,
+		middleware: s.middleware,
+		prefix:     version.Prefix(),
+		wrap:       version.Middleware,
+	}
+}
+
+/**
+ * @description Returns the pattern a route registered on the scope is mounted at,
+ * inserting the scope's version prefix after any method and host.
+ */
+func (s *Scope) Pattern(pattern string) string {
+	if s.prefix == "" {
+		return pattern
+	}
+	method, path := splitPattern(pattern)
+	if i := strings.Index(path, "/"); i >= 0 {
+		path = path[:i] + s.prefix + path[i:]
+	}
+	if method == "" {
+		return path
+	}
+	return method + " " + path
+}
@@ -0,0 +1,225 @@
+/**
+ * @fileoverview Portable export and import of server state for environment promotion.
+ * Subsystems that own persistent state (configuration, the upstream trust store, stored
+ * idempotency keys, and later prompt templates, model registry entries, feature flags, and
+ * API keys) register a named section; an export collects every section into one
+ * versioned JSON bundle that another instance can validate and import.
+ */
+
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+)
+
+// BundleVersion is the bundle format version written by Export and accepted by Import
+const BundleVersion = 1
+
+// Well-known section names
+const (
+	SectionPromptTemplates = "prompt_templates"
+	SectionModels          = "models"
+	SectionFeatureFlags    = "feature_flags"
+	SectionAPIKeys         = "api_keys"
+	SectionConfig          = "config"
+	SectionTrustStore      = "trust_store"
+	SectionStore           = "store"
+)
+
+// MaxBundleBytes limits the size of an uploaded bundle
+const MaxBundleBytes = 32 << 20
+
+// Bundle is the portable JSON document produced by Export
+type Bundle struct {
+	Version    int                        `json:"version"`
+	ExportedAt string                     `json:"exported_at"`
+	Source     string                     `json:"source,omitempty"`
+	Sections   map[string]json.RawMessage `json:"sections"`
+}
+
+// Section exports and imports one subsystem's state
+type Section struct {
+	// Export returns the section's state as a JSON-encodable value
+	Export func(ctx context.Context) (any, error)
+	// Import validates data and, unless dryRun is set, applies it; it returns the number of items
+	Import func(ctx context.Context, data json.RawMessage, dryRun bool) (int, error)
+}
+
+// ImportResult reports how many items each section imported
+type ImportResult struct {
+	DryRun   bool           `json:"dry_run"`
+	Sections map[string]int `json:"sections"`
+	Skipped  []string       `json:"skipped,omitempty"`
+}
+
+// Registry holds the sections included in bundles
+type Registry struct {
+	mu       sync.RWMutex
+	source   string
+	sections map[string]Section
+}
+
+/**
+ * @description Creates an empty registry; source identifies this instance in exported bundles.
+ */
+func NewRegistry(source string) *Registry {
+	return &Registry{source: source, sections: make(map[string]Section)}
+}
+
+/**
+ * @description Registers a section under name, replacing any previous registration.
+ */
+func (r *Registry) Register(name string, section Section) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sections[name] = section
+}
+
+/**
+ * @description Returns registered section names in sorted order.
+ */
+func (r *Registry) Sections() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.sections))
+	for name := range r.sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+/**
+ * @description Exports the named sections, or every section when names is empty.
+ */
+func (r *Registry) Export(ctx context.Context, names ...string) (Bundle, error) {
+	if len(names) == 0 {
+		names = r.Sections()
+	}
+	bundle := Bundle{
+		Version:    BundleVersion,
+		ExportedAt: time.Now().UTC().Format(time.RFC3339),
+		Source:     r.source,
+		Sections:   make(map[string]json.RawMessage, len(names)),
+	}
+	for _, name := range names {
+		section, ok := r.section(name)
+		if !ok {
+			return Bundle{}, fmt.Errorf("unknown state section %q", name)
+		}
+		value, err := section.Export(ctx)
+		if err != nil {
+			return Bundle{}, fmt.Errorf("failed to export %s: %w", name, err)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return Bundle{}, fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		bundle.Sections[name] = data
+	}
+	return bundle, nil
+}
+
+/**
+ * @description Imports a bundle. Every section is validated with a dry run before any is
+ * applied, so a bad section leaves this instance unchanged. Sections this instance does not
+ * register are skipped and reported.
+ */
+func (r *Registry) Import(ctx context.Context, bundle Bundle, dryRun bool) (ImportResult, error) {
+	if bundle.Version != BundleVersion {
+		return ImportResult{}, fmt.Errorf("unsupported bundle version %d (expected %d)", bundle.Version, BundleVersion)
+	}
+
+	result := ImportResult{DryRun: dryRun, Sections: make(map[string]int)}
+	names := make([]string, 0, len(bundle.Sections))
+	for name := range bundle.Sections {
+		if _, ok := r.section(name); ok {
+			names = append(names, name)
+		} else {
+			result.Skipped = append(result.Skipped, name)
+		}
+	}
+	sort.Strings(names)
+	sort.Strings(result.Skipped)
+
+	for _, name := range names {
+		section, _ := r.section(name)
+		count, err := section.Import(ctx, bundle.Sections[name], true)
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		result.Sections[name] = count
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	for _, name := range names {
+		section, _ := r.section(name)
+		count, err := section.Import(ctx, bundle.Sections[name], false)
+		if err != nil {
+			return ImportResult{}, fmt.Errorf("failed to import %s: %w", name, err)
+		}
+		result.Sections[name] = count
+	}
+	return result, nil
+}
+
+/**
+ * @description Serves GET exports; "?section=a&section=b" limits the bundle to those sections.
+ * Bundles may contain secrets such as API keys, so mount behind admin authentication.
+ */
+func (r *Registry) ExportHandler(w http.ResponseWriter, req *http.Request) {
+	bundle, err := r.Export(req.Context(), req.URL.Query()["section"]...)
+	if err != nil {
+		apierror.Write(w, req, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Disposition", `attachment; filename="state-bundle.json"`)
+	jsoncase.Write(w, http.StatusOK, bundle)
+}
+
+/**
+ * @description Serves POST imports of a bundle body, decoded with the casing ExportHandler
+ * writes; "?dry_run=true" validates without applying.
+ */
+func (r *Registry) ImportHandler(w http.ResponseWriter, req *http.Request) {
+	var bundle Bundle
+	if err := jsoncase.Decode(http.MaxBytesReader(w, req.Body, MaxBundleBytes), &bundle); err != nil {
+		var tooLarge *http.MaxBytesError
+		status := http.StatusBadRequest
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		} else if errors.Is(err, io.EOF) {
+			err = errors.New("empty bundle")
+		}
+		apierror.Write(w, req, status, fmt.Sprintf("invalid bundle: %v", err))
+		return
+	}
+
+	result, err := r.Import(req.Context(), bundle, req.URL.Query().Get("dry_run") == "true")
+	if err != nil {
+		apierror.Write(w, req, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	jsoncase.Write(w, http.StatusOK, result)
+}
+
+// section looks up a registered section
+func (r *Registry) section(name string) (Section, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	section, ok := r.sections[name]
+	return section, ok
+}
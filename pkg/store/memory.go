@@ -0,0 +1,186 @@
+/**
+ * @fileoverview In-memory implementations of the idempotency and limiter stores.
+ * Suitable for single-instance deployments and development; state is lost on restart.
+ */
+
+package store
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryEntry is a stored idempotency value or reservation
+type memoryEntry struct {
+	value     []byte
+	reserved  bool
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore keeps idempotency entries in a process-local map. Expired entries
+// are ignored on access and removed by PurgeExpired, which the retention manager runs
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+/**
+ * @description Creates an empty in-memory idempotency store.
+ */
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{
+		entries: make(map[string]memoryEntry),
+		now:     time.Now,
+	}
+}
+
+/**
+ * @description Claims key for ttl unless a live entry already exists.
+ */
+func (s *MemoryIdempotencyStore) Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if entry, ok := s.entries[key]; ok && now.Before(entry.expiresAt) {
+		return false, nil
+	}
+
+	s.entries[key] = memoryEntry{reserved: true, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+/**
+ * @description Returns the stored value for key or ErrNotFound.
+ */
+func (s *MemoryIdempotencyStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || entry.reserved || !s.now().Before(entry.expiresAt) {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), entry.value...), nil
+}
+
+/**
+ * @description Stores value for key until ttl elapses.
+ */
+func (s *MemoryIdempotencyStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{value: append([]byte(nil), value...), expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+/**
+ * @description Removes key from the store.
+ */
+func (s *MemoryIdempotencyStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+/**
+ * @description Returns every unexpired stored value, sorted by key.
+ */
+func (s *MemoryIdempotencyStore) Entries(ctx context.Context) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	entries := make([]Entry, 0, len(s.entries))
+	for key, entry := range s.entries {
+		if !entry.reserved && now.Before(entry.expiresAt) {
+			entries = append(entries, Entry{Key: key, Value: append([]byte(nil), entry.value...), ExpiresAt: entry.expiresAt})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+/**
+ * @description Deletes, or with dryRun counts, entries that expired at or before cutoff.
+ */
+func (s *MemoryIdempotencyStore) PurgeExpired(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var purged int64
+	for key, entry := range s.entries {
+		if !cutoff.Before(entry.expiresAt) {
+			purged++
+			if !dryRun {
+				delete(s.entries, key)
+			}
+		}
+	}
+	return purged, nil
+}
+
+// memoryLimiterPurgeSize is the bucket count above which idle buckets are purged
+const memoryLimiterPurgeSize = 10000
+
+// memoryBucket is the persisted state of a token bucket
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// MemoryLimiterStore keeps token buckets in a process-local map
+type MemoryLimiterStore struct {
+	mu      sync.Mutex
+	buckets map[string]memoryBucket
+	now     func() time.Time
+}
+
+/**
+ * @description Creates an empty in-memory limiter store.
+ */
+func NewMemoryLimiterStore() *MemoryLimiterStore {
+	return &MemoryLimiterStore{
+		buckets: make(map[string]memoryBucket),
+		now:     time.Now,
+	}
+}
+
+/**
+ * @description Consumes one token from the bucket for key.
+ * New buckets start full so the first burst of requests is admitted.
+ */
+func (s *MemoryLimiterStore) Take(ctx context.Context, key string, rate float64, burst int) (LimitDecision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = memoryBucket{tokens: float64(burst), updated: now}
+	}
+
+	tokens, decision := takeToken(bucket.tokens, now.Sub(bucket.updated), rate, burst)
+	s.buckets[key] = memoryBucket{tokens: tokens, updated: now}
+
+	if len(s.buckets) > memoryLimiterPurgeSize && rate > 0 {
+		s.purgeFullLocked(now, rate, burst)
+	}
+	return decision, nil
+}
+
+// purgeFullLocked drops buckets idle long enough to have refilled; callers must hold s.mu
+func (s *MemoryLimiterStore) purgeFullLocked(now time.Time, rate float64, burst int) {
+	refill := time.Duration(float64(burst) / rate * float64(time.Second))
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.updated) >= refill {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+/**
+ * @fileoverview Redis implementation of the idempotency and limiter stores.
+ * Shares state across replicas; token buckets are updated atomically with a Lua script.
+ */
+
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisReservedMarker is stored for reserved keys that have no value yet
+const redisReservedMarker = "\x00reserved"
+
+// tokenBucketScript refills and consumes a token bucket stored as a hash {tokens, updated_ms}
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "tokens", "updated_ms")
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) / 1000 * rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "updated_ms", now)
+if rate > 0 then
+  redis.call("PEXPIRE", KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+end
+return {allowed, tostring(tokens)}
+`)
+
+// RedisBackend implements IdempotencyStore and LimiterStore on a Redis client
+type RedisBackend struct {
+	client *redis.Client
+	prefix string
+}
+
+/**
+ * @description Creates a Redis backend connected to addr with the given key prefix.
+ */
+func NewRedisBackend(addr, password string, db int, prefix string) *RedisBackend {
+	return &RedisBackend{
+		client: redis.NewClient(&redis.Options{
+			Addr:     addr,
+			Password: password,
+			DB:       db,
+		}),
+		prefix: prefix,
+	}
+}
+
+/**
+ * @description Verifies the Redis server is reachable.
+ */
+func (b *RedisBackend) Ping(ctx context.Context) error {
+	return b.client.Ping(ctx).Err()
+}
+
+/**
+ * @description Closes the underlying Redis client.
+ */
+func (b *RedisBackend) Close() error {
+	return b.client.Close()
+}
+
+/**
+ * @description Claims key for ttl using SET NX.
+ */
+func (b *RedisBackend) Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return b.client.SetNX(ctx, b.idempotencyKey(key), redisReservedMarker, ttl).Result()
+}
+
+/**
+ * @description Returns the stored value for key or ErrNotFound.
+ */
+func (b *RedisBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := b.client.Get(ctx, b.idempotencyKey(key)).Bytes()
+	if errors.Is(err, redis.Nil) || string(value) == redisReservedMarker {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+/**
+ * @description Stores value for key until ttl elapses.
+ */
+func (b *RedisBackend) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return b.client.Set(ctx, b.idempotencyKey(key), value, ttl).Err()
+}
+
+/**
+ * @description Removes key from Redis.
+ */
+func (b *RedisBackend) Delete(ctx context.Context, key string) error {
+	return b.client.Del(ctx, b.idempotencyKey(key)).Err()
+}
+
+/**
+ * @description Consumes one token from the bucket for key atomically on the server.
+ */
+func (b *RedisBackend) Take(ctx context.Context, key string, rate float64, burst int) (LimitDecision, error) {
+	now := time.Now().UnixMilli()
+	result, err := tokenBucketScript.Run(ctx, b.client, []string{b.prefix + "ratelimit:" + key}, rate, burst, now).Slice()
+	if err != nil {
+		return LimitDecision{}, err
+	}
+	if len(result) != 2 {
+		return LimitDecision{}, errors.New("store: unexpected token bucket script result")
+	}
+
+	allowed, _ := result[0].(int64)
+	tokensText, _ := result[1].(string)
+
+	var tokens float64
+	if _, err := fmt.Sscan(tokensText, &tokens); err != nil {
+		return LimitDecision{}, err
+	}
+
+	if allowed == 1 {
+		return LimitDecision{Allowed: true, Remaining: int(tokens)}, nil
+	}
+	_, decision := takeToken(tokens, 0, rate, burst)
+	return decision, nil
+}
+
+// idempotencyKey namespaces an idempotency key
+func (b *RedisBackend) idempotencyKey(key string) string {
+	return b.prefix + "idempotency:" + key
+}
@@ -0,0 +1,276 @@
+/**
+ * @fileoverview database/sql implementation of the idempotency and limiter stores.
+ * Works with PostgreSQL, MySQL, and SQLite; the caller opens the *sql.DB and imports the driver.
+ */
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQL dialects supported by the SQL backend
+const (
+	DialectPostgres = "postgres"
+	DialectMySQL    = "mysql"
+	DialectSQLite   = "sqlite"
+)
+
+// SQLBackend implements IdempotencyStore and LimiterStore on a relational database
+type SQLBackend struct {
+	db                 *sql.DB
+	dialect            string
+	idempotencyTable   string
+	limiterBucketTable string
+}
+
+/**
+ * @description Creates a SQL backend for the given dialect.
+ * The prefix is prepended to table names so several services can share a database.
+ */
+func NewSQLBackend(db *sql.DB, dialect, prefix string) (*SQLBackend, error) {
+	switch dialect {
+	case DialectPostgres, DialectMySQL, DialectSQLite:
+	default:
+		return nil, fmt.Errorf("store: unsupported sql dialect %q", dialect)
+	}
+
+	return &SQLBackend{
+		db:                 db,
+		dialect:            dialect,
+		idempotencyTable:   prefix + "idempotency_keys",
+		limiterBucketTable: prefix + "ratelimit_buckets",
+	}, nil
+}
+
+/**
+ * @description Creates the backend's tables if they do not exist.
+ */
+func (b *SQLBackend) EnsureSchema(ctx context.Context) error {
+	keyType, blobType, floatType := "TEXT", "BLOB", "REAL"
+	switch b.dialect {
+	case DialectPostgres:
+		blobType, floatType = "BYTEA", "DOUBLE PRECISION"
+	case DialectMySQL:
+		keyType, blobType, floatType = "VARCHAR(255)", "LONGBLOB", "DOUBLE"
+	}
+
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			idem_key %s PRIMARY KEY,
+			value %s,
+			reserved INTEGER NOT NULL,
+			expires_at BIGINT NOT NULL
+		)`, b.idempotencyTable, keyType, blobType),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			bucket_key %s PRIMARY KEY,
+			tokens %s NOT NULL,
+			updated_at BIGINT NOT NULL
+		)`, b.limiterBucketTable, keyType, floatType),
+	}
+
+	for _, statement := range statements {
+		if _, err := b.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("store: failed to create schema: %w", err)
+		}
+	}
+	return nil
+}
+
+/**
+ * @description Deletes, or with dryRun counts, idempotency rows that expired at or before cutoff.
+ * Expired rows are otherwise only removed when their key is reused.
+ */
+func (b *SQLBackend) PurgeExpired(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error) {
+	if dryRun {
+		var count int64
+		err := b.queryRow(ctx, "SELECT COUNT(*) FROM "+b.idempotencyTable+" WHERE expires_at <= ?", cutoff.UnixMilli()).Scan(&count)
+		if err != nil {
+			return 0, fmt.Errorf("store: failed to count expired idempotency keys: %w", err)
+		}
+		return count, nil
+	}
+
+	result, err := b.exec(ctx, "DELETE FROM "+b.idempotencyTable+" WHERE expires_at <= ?", cutoff.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("store: failed to purge expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+/**
+ * @description Claims key for ttl by inserting a reservation row.
+ * Expired rows are removed first; a failed insert for a live key reports false.
+ */
+func (b *SQLBackend) Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	if _, err := b.exec(ctx, "DELETE FROM "+b.idempotencyTable+" WHERE idem_key = ? AND expires_at <= ?",
+		key, now.UnixMilli()); err != nil {
+		return false, err
+	}
+
+	_, insertErr := b.exec(ctx, "INSERT INTO "+b.idempotencyTable+" (idem_key, value, reserved, expires_at) VALUES (?, NULL, 1, ?)",
+		key, now.Add(ttl).UnixMilli())
+	if insertErr == nil {
+		return true, nil
+	}
+
+	// Distinguish a conflicting live key from other failures without relying on driver error codes
+	var exists int
+	err := b.queryRow(ctx, "SELECT 1 FROM "+b.idempotencyTable+" WHERE idem_key = ?", key).Scan(&exists)
+	if err == nil {
+		return false, nil
+	}
+	return false, insertErr
+}
+
+/**
+ * @description Returns the stored value for key or ErrNotFound.
+ */
+func (b *SQLBackend) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := b.queryRow(ctx, "SELECT value FROM "+b.idempotencyTable+" WHERE idem_key = ? AND reserved = 0 AND expires_at > ?",
+		key, time.Now().UnixMilli()).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+/**
+ * @description Returns every unexpired stored value, sorted by key.
+ */
+func (b *SQLBackend) Entries(ctx context.Context) ([]Entry, error) {
+	rows, err := b.db.QueryContext(ctx, b.rebind("SELECT idem_key, value, expires_at FROM "+b.idempotencyTable+
+		" WHERE reserved = 0 AND expires_at > ? ORDER BY idem_key"), time.Now().UnixMilli())
+	if err != nil {
+		return nil, fmt.Errorf("store: failed to list idempotency keys: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []Entry
+	for rows.Next() {
+		var entry Entry
+		var expiresAt int64
+		if err := rows.Scan(&entry.Key, &entry.Value, &expiresAt); err != nil {
+			return nil, fmt.Errorf("store: failed to list idempotency keys: %w", err)
+		}
+		entry.ExpiresAt = time.UnixMilli(expiresAt)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+/**
+ * @description Stores value for key until ttl elapses, replacing any reservation.
+ */
+func (b *SQLBackend) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return b.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := b.txExec(ctx, tx, "DELETE FROM "+b.idempotencyTable+" WHERE idem_key = ?", key); err != nil {
+			return err
+		}
+		_, err := b.txExec(ctx, tx, "INSERT INTO "+b.idempotencyTable+" (idem_key, value, reserved, expires_at) VALUES (?, ?, 0, ?)",
+			key, value, time.Now().Add(ttl).UnixMilli())
+		return err
+	})
+}
+
+/**
+ * @description Removes key from the database.
+ */
+func (b *SQLBackend) Delete(ctx context.Context, key string) error {
+	_, err := b.exec(ctx, "DELETE FROM "+b.idempotencyTable+" WHERE idem_key = ?", key)
+	return err
+}
+
+/**
+ * @description Consumes one token from the bucket for key inside a transaction.
+ */
+func (b *SQLBackend) Take(ctx context.Context, key string, rate float64, burst int) (LimitDecision, error) {
+	var decision LimitDecision
+
+	err := b.withTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now()
+		selectQuery := "SELECT tokens, updated_at FROM " + b.limiterBucketTable + " WHERE bucket_key = ?"
+		if b.dialect != DialectSQLite {
+			selectQuery += " FOR UPDATE"
+		}
+
+		var tokens float64
+		var updatedAt int64
+		err := tx.QueryRowContext(ctx, b.rebind(selectQuery), key).Scan(&tokens, &updatedAt)
+		exists := err == nil
+		if errors.Is(err, sql.ErrNoRows) {
+			tokens, updatedAt = float64(burst), now.UnixMilli()
+		} else if err != nil {
+			return err
+		}
+
+		elapsed := time.Duration(now.UnixMilli()-updatedAt) * time.Millisecond
+		tokens, decision = takeToken(tokens, max(elapsed, 0), rate, burst)
+
+		if exists {
+			_, err = b.txExec(ctx, tx, "UPDATE "+b.limiterBucketTable+" SET tokens = ?, updated_at = ? WHERE bucket_key = ?",
+				tokens, now.UnixMilli(), key)
+		} else {
+			_, err = b.txExec(ctx, tx, "INSERT INTO "+b.limiterBucketTable+" (bucket_key, tokens, updated_at) VALUES (?, ?, ?)",
+				key, tokens, now.UnixMilli())
+		}
+		return err
+	})
+
+	return decision, err
+}
+
+// withTx runs fn in a transaction, committing on success and rolling back on error
+func (b *SQLBackend) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// exec runs a statement written with ? placeholders
+func (b *SQLBackend) exec(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return b.db.ExecContext(ctx, b.rebind(query), args...)
+}
+
+// txExec runs a statement written with ? placeholders inside tx
+func (b *SQLBackend) txExec(ctx context.Context, tx *sql.Tx, query string, args ...any) (sql.Result, error) {
+	return tx.ExecContext(ctx, b.rebind(query), args...)
+}
+
+// queryRow runs a single-row query written with ? placeholders
+func (b *SQLBackend) queryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	return b.db.QueryRowContext(ctx, b.rebind(query), args...)
+}
+
+// rebind converts ? placeholders to $n for PostgreSQL
+func (b *SQLBackend) rebind(query string) string {
+	if b.dialect != DialectPostgres {
+		return query
+	}
+
+	var builder strings.Builder
+	position := 0
+	for _, char := range query {
+		if char == '?' {
+			position++
+			builder.WriteString("$" + strconv.Itoa(position))
+			continue
+		}
+		builder.WriteRune(char)
+	}
+	return builder.String()
+}
@@ -0,0 +1,184 @@
+/**
+ * @fileoverview Pluggable persistence for idempotency keys and rate-limiter state.
+ * Defines the storage interfaces used by middleware plus a factory that selects the
+ * in-memory, Redis, or SQL backend from configuration, so single-binary development
+ * and multi-replica production share the same middleware code.
+ */
+
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Supported backend names
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+	BackendSQL    = "sql"
+)
+
+// ErrNotFound is returned when a key does not exist or has expired
+var ErrNotFound = errors.New("store: key not found")
+
+// IdempotencyStore persists values keyed by caller-provided idempotency keys
+type IdempotencyStore interface {
+	// Reserve atomically claims key for ttl, returning false if it is already claimed or stored
+	Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Get returns the stored value for key, or ErrNotFound; a reserved key without a value is ErrNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores value for key, replacing any reservation, and expires it after ttl
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key and any reservation
+	Delete(ctx context.Context, key string) error
+}
+
+// Purger is implemented by backends whose expired entries are not removed automatically
+type Purger interface {
+	// PurgeExpired deletes entries that expired at or before cutoff and returns how many;
+	// with dryRun set it only counts them
+	PurgeExpired(ctx context.Context, cutoff time.Time, dryRun bool) (int64, error)
+}
+
+// Entry is a stored idempotency value and its expiry
+type Entry struct {
+	Key       string    `json:"key"`
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Lister is implemented by backends that can enumerate their idempotency values
+type Lister interface {
+	// Entries returns every unexpired stored value; reservations without a value are skipped
+	Entries(ctx context.Context) ([]Entry, error)
+}
+
+// LimitDecision is the outcome of a token bucket request
+type LimitDecision struct {
+	Allowed    bool
+	Remaining  int
+	RetryAfter time.Duration
+}
+
+// LimiterStore persists token buckets for rate limiting
+type LimiterStore interface {
+	// Take consumes one token from the bucket for key, refilling at rate tokens per second up to burst
+	Take(ctx context.Context, key string, rate float64, burst int) (LimitDecision, error)
+}
+
+// Config selects and configures a storage backend
+type Config struct {
+	// Backend is "memory" (default), "redis", or "sql"
+	Backend string
+	// KeyPrefix namespaces keys in shared backends
+	KeyPrefix string
+
+	// RedisAddr, RedisPassword, and RedisDB configure the Redis backend
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+
+	// SQLDB is an open database handle for the SQL backend; the caller owns the driver import
+	SQLDB *sql.DB
+	// SQLDialect is "postgres", "mysql", or "sqlite" and selects placeholders and DDL
+	SQLDialect string
+}
+
+// Stores bundles the stores produced by a single backend
+type Stores struct {
+	Idempotency IdempotencyStore
+	Limiter     LimiterStore
+	closer      func() error
+}
+
+/**
+ * @description Creates the idempotency and limiter stores for the configured backend.
+ * The SQL backend creates its tables if they do not already exist.
+ */
+func New(ctx context.Context, config Config) (*Stores, error) {
+	switch config.Backend {
+	case "", BackendMemory:
+		return &Stores{
+			Idempotency: NewMemoryIdempotencyStore(),
+			Limiter:     NewMemoryLimiterStore(),
+			closer:      func() error { return nil },
+		}, nil
+
+	case BackendRedis:
+		if config.RedisAddr == "" {
+			return nil, errors.New("store: redis backend requires RedisAddr")
+		}
+		backend := NewRedisBackend(config.RedisAddr, config.RedisPassword, config.RedisDB, config.KeyPrefix)
+		if err := backend.Ping(ctx); err != nil {
+			backend.Close()
+			return nil, fmt.Errorf("store: failed to connect to redis at %s: %w", config.RedisAddr, err)
+		}
+		return &Stores{Idempotency: backend, Limiter: backend, closer: backend.Close}, nil
+
+	case BackendSQL:
+		if config.SQLDB == nil {
+			return nil, errors.New("store: sql backend requires SQLDB")
+		}
+		backend, err := NewSQLBackend(config.SQLDB, config.SQLDialect, config.KeyPrefix)
+		if err != nil {
+			return nil, err
+		}
+		if err := backend.EnsureSchema(ctx); err != nil {
+			return nil, err
+		}
+		return &Stores{Idempotency: backend, Limiter: backend, closer: func() error { return nil }}, nil
+
+	default:
+		return nil, fmt.Errorf("store: unknown backend %q", config.Backend)
+	}
+}
+
+/**
+ * @description Returns the idempotency store's Purger, or false for backends such as Redis
+ * that expire entries themselves.
+ */
+func (s *Stores) Purger() (Purger, bool) {
+	purger, ok := s.Idempotency.(Purger)
+	return purger, ok
+}
+
+/**
+ * @description Returns the idempotency store's Lister, or false for backends such as Redis
+ * that cannot enumerate their keys.
+ */
+func (s *Stores) Lister() (Lister, bool) {
+	lister, ok := s.Idempotency.(Lister)
+	return lister, ok
+}
+
+/**
+ * @description Releases connections owned by the backend.
+ * SQL handles are owned by the caller and are not closed.
+ */
+func (s *Stores) Close() error {
+	return s.closer()
+}
+
+/**
+ * @description Computes a token bucket refill and consumption shared by all backends.
+ * Returns the new token count and the decision for a single-token request.
+ */
+func takeToken(tokens float64, elapsed time.Duration, rate float64, burst int) (float64, LimitDecision) {
+	capacity := float64(burst)
+	tokens = min(capacity, tokens+elapsed.Seconds()*rate)
+
+	if tokens >= 1 {
+		tokens--
+		return tokens, LimitDecision{Allowed: true, Remaining: int(tokens)}
+	}
+
+	retryAfter := time.Duration(0)
+	if rate > 0 {
+		retryAfter = time.Duration((1 - tokens) / rate * float64(time.Second))
+	}
+	return tokens, LimitDecision{Allowed: false, Remaining: 0, RetryAfter: retryAfter}
+}
@@ -0,0 +1,76 @@
+/**
+ * @fileoverview HTTP integration for the stream tracker.
+ */
+
+package streams
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+)
+
+// streamContextKey stores the current *Stream in the request context
+type streamContextKey struct{}
+
+/**
+ * @description Wraps a streaming handler so it is counted under route, limited per key, and
+ * reaped when idle. Every write counts as activity. Over-limit requests receive 429.
+ */
+func (t *Tracker) Middleware(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stream, ctx, err := t.Open(r, route)
+		if errors.Is(err, ErrTooManyStreams) {
+			w.Header().Set("Retry-After", "5")
+			apierror.Write(w, r, http.StatusTooManyRequests, "too many open streams")
+			return
+		}
+		defer t.Release(stream)
+
+		ctx = context.WithValue(ctx, streamContextKey{}, stream)
+		next.ServeHTTP(&activityWriter{ResponseWriter: w, stream: stream}, r.WithContext(ctx))
+	})
+}
+
+/**
+ * @description Returns the tracked stream for a request context, or nil outside Middleware.
+ */
+func FromContext(ctx context.Context) *Stream {
+	stream, _ := ctx.Value(streamContextKey{}).(*Stream)
+	return stream
+}
+
+// activityWriter touches the stream on every write
+type activityWriter struct {
+	http.ResponseWriter
+	stream *Stream
+}
+
+func (w *activityWriter) Write(b []byte) (int, error) {
+	w.stream.Touch()
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *activityWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+/**
+ * @description Writes keepalive bytes (such as SSE heartbeat comments) without counting them as
+ * activity, so streams that only carry keepalives are still reaped when idle.
+ */
+func WriteKeepalive(w http.ResponseWriter, b []byte) (int, error) {
+	for inner := w; inner != nil; {
+		if tracked, ok := inner.(*activityWriter); ok {
+			return tracked.ResponseWriter.Write(b)
+		}
+		unwrapper, ok := inner.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		inner = unwrapper.Unwrap()
+	}
+	return w.Write(b)
+}
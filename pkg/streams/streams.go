@@ -0,0 +1,224 @@
+/**
+ * @fileoverview Accounting for long-running SSE, WebSocket, and streaming connections.
+ * Tracks open streams per key and route, enforces a per-key maximum, and reaps streams
+ * that have been idle too long, recording a structured close reason the handler can
+ * report to the client before it returns.
+ */
+
+package streams
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/httputil"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/id"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+)
+
+// Close reasons recorded when the tracker ends a stream
+const (
+	ReasonIdleTimeout = "idle_timeout"
+	ReasonShutdown    = "server_shutdown"
+)
+
+// ErrTooManyStreams is returned when a key already has the maximum number of open streams
+var ErrTooManyStreams = errors.New("streams: too many open streams for key")
+
+// reapedStreams counts streams closed by the idle reaper
+var reapedStreams = metrics.NewCounter("http_streams_reaped_total", "Streaming connections closed after exceeding the idle timeout.")
+
+// Config configures a Tracker
+type Config struct {
+	// MaxPerKey limits concurrent streams per key; zero means unlimited
+	MaxPerKey int
+	// IdleTimeout closes streams with no writes for this long; zero disables reaping
+	IdleTimeout time.Duration
+	// Key identifies the caller; defaults to the client IP
+	Key func(*http.Request) string
+}
+
+// Stream is one open long-running connection
+type Stream struct {
+	ID     string
+	Key    string
+	Route  string
+	Opened time.Time
+
+	lastActivity atomic.Int64
+	reason       atomic.Value
+	cancel       context.CancelFunc
+}
+
+// StreamInfo describes an open stream for the admin view
+type StreamInfo struct {
+	ID         string `json:"id"`
+	Key        string `json:"key"`
+	Route      string `json:"route"`
+	Opened     string `json:"opened"`
+	IdleMillis int64  `json:"idle_ms"`
+}
+
+// Tracker accounts for open streams
+type Tracker struct {
+	config Config
+
+	mu      sync.Mutex
+	streams map[string]*Stream
+	perKey  map[string]int
+}
+
+/**
+ * @description Creates a tracker; call Run to start idle reaping.
+ */
+func NewTracker(config Config) *Tracker {
+	if config.Key == nil {
+		config.Key = ClientIP
+	}
+	return &Tracker{config: config, streams: make(map[string]*Stream), perKey: make(map[string]int)}
+}
+
+/**
+ * @description Returns the host part of the request's remote address.
+ */
+func ClientIP(r *http.Request) string {
+	return httputil.ClientIP(r)
+}
+
+/**
+ * @description Registers a stream for r on route, returning a context that is cancelled when
+ * the stream is reaped or closed. Callers must call Release when the stream ends.
+ */
+func (t *Tracker) Open(r *http.Request, route string) (*Stream, context.Context, error) {
+	key := t.config.Key(r)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.config.MaxPerKey > 0 && t.perKey[key] >= t.config.MaxPerKey {
+		return nil, nil, ErrTooManyStreams
+	}
+
+	ctx, cancel := context.WithCancel(r.Context())
+	stream := &Stream{ID: id.New(), Key: key, Route: route, Opened: time.Now(), cancel: cancel}
+	stream.Touch()
+	t.streams[stream.ID] = stream
+	t.perKey[key]++
+	return stream, ctx, nil
+}
+
+/**
+ * @description Removes a stream from accounting and cancels its context.
+ */
+func (t *Tracker) Release(stream *Stream) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.streams[stream.ID]; !ok {
+		return
+	}
+	delete(t.streams, stream.ID)
+	if t.perKey[stream.Key]--; t.perKey[stream.Key] <= 0 {
+		delete(t.perKey, stream.Key)
+	}
+	stream.cancel()
+}
+
+/**
+ * @description Reaps idle streams until ctx is cancelled; does nothing when IdleTimeout is zero.
+ */
+func (t *Tracker) Run(ctx context.Context) {
+	if t.config.IdleTimeout <= 0 {
+		return
+	}
+	ticker := time.NewTicker(max(t.config.IdleTimeout/4, time.Second))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, stream := range t.snapshot() {
+				if now.Sub(stream.LastActivity()) > t.config.IdleTimeout {
+					stream.Close(ReasonIdleTimeout)
+					reapedStreams.Add(1)
+				}
+			}
+		}
+	}
+}
+
+/**
+ * @description Closes every open stream with reason, e.g. ReasonShutdown so streams do not delay draining.
+ */
+func (t *Tracker) CloseAll(reason string) {
+	for _, stream := range t.snapshot() {
+		stream.Close(reason)
+	}
+}
+
+/**
+ * @description Returns the open streams, oldest first.
+ */
+func (t *Tracker) Streams() []StreamInfo {
+	now := time.Now()
+	open := t.snapshot()
+	sort.Slice(open, func(i, j int) bool { return open[i].Opened.Before(open[j].Opened) })
+
+	infos := make([]StreamInfo, len(open))
+	for i, stream := range open {
+		infos[i] = StreamInfo{
+			ID:         stream.ID,
+			Key:        stream.Key,
+			Route:      stream.Route,
+			Opened:     stream.Opened.UTC().Format(time.RFC3339),
+			IdleMillis: now.Sub(stream.LastActivity()).Milliseconds(),
+		}
+	}
+	return infos
+}
+
+// snapshot copies the open streams so callers can act without holding the lock
+func (t *Tracker) snapshot() []*Stream {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	open := make([]*Stream, 0, len(t.streams))
+	for _, stream := range t.streams {
+		open = append(open, stream)
+	}
+	return open
+}
+
+/**
+ * @description Records activity on the stream, postponing idle reaping.
+ */
+func (s *Stream) Touch() {
+	s.lastActivity.Store(time.Now().UnixNano())
+}
+
+/**
+ * @description Returns when the stream last had activity.
+ */
+func (s *Stream) LastActivity() time.Time {
+	return time.Unix(0, s.lastActivity.Load())
+}
+
+/**
+ * @description Cancels the stream's context, recording reason if it is the first close.
+ */
+func (s *Stream) Close(reason string) {
+	s.reason.CompareAndSwap(nil, reason)
+	s.cancel()
+}
+
+/**
+ * @description Returns why the tracker closed the stream, or "" if it did not.
+ */
+func (s *Stream) CloseReason() string {
+	reason, _ := s.reason.Load().(string)
+	return reason
+}
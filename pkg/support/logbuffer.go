@@ -0,0 +1,82 @@
+/**
+ * @fileoverview Bounded in-memory buffer of recent log lines for support bundles.
+ */
+
+package support
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// DefaultLogLines is the number of recent log lines kept
+const DefaultLogLines = 1000
+
+// LogBuffer is an io.Writer that retains the most recent complete log lines
+type LogBuffer struct {
+	mu      sync.Mutex
+	size    int
+	lines   []string
+	partial []byte
+}
+
+/**
+ * @description Creates a buffer retaining size lines; non-positive uses DefaultLogLines.
+ * Install with log.SetOutput(io.MultiWriter(os.Stderr, buffer)).
+ */
+func NewLogBuffer(size int) *LogBuffer {
+	if size <= 0 {
+		size = DefaultLogLines
+	}
+	return &LogBuffer{size: size}
+}
+
+/**
+ * @description Appends p, splitting it into lines; a trailing incomplete line waits for the next write.
+ */
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	data := append(b.partial, p...)
+	for {
+		newline := bytes.IndexByte(data, '\n')
+		if newline < 0 {
+			break
+		}
+		b.lines = append(b.lines, string(data[:newline]))
+		data = data[newline+1:]
+	}
+	b.partial = append([]byte(nil), data...)
+
+	// Compact only once the buffer doubles so trimming stays amortized
+	if len(b.lines) > 2*b.size {
+		b.lines = append([]string(nil), b.lines[len(b.lines)-b.size:]...)
+	}
+	return len(p), nil
+}
+
+/**
+ * @description Returns the retained lines, oldest first.
+ */
+func (b *LogBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.lines[max(len(b.lines)-b.size, 0):]...)
+}
+
+/**
+ * @description Creates a bundle file with the retained lines.
+ */
+func (b *LogBuffer) File(name string) File {
+	return File{Name: name, Collect: func(w io.Writer) error {
+		lines := b.Lines()
+		if len(lines) == 0 {
+			return nil
+		}
+		_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+		return err
+	}}
+}
@@ -0,0 +1,148 @@
+/**
+ * @fileoverview Support bundle generation for bug reports.
+ * Collects diagnostic files (redacted configuration, version info, health history,
+ * goroutine dump, metrics, recent logs) into a single .tar.gz archive. A file that fails
+ * to collect is replaced by a ".error" entry so one broken source never loses the rest.
+ */
+
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/config"
+)
+
+// File is one entry in a support bundle
+type File struct {
+	Name    string
+	Collect func(w io.Writer) error
+}
+
+// urlCredentials matches the userinfo portion of URLs embedded in configuration values
+var urlCredentials = regexp.MustCompile(`://[^/@\s]+@`)
+
+/**
+ * @description Writes files as a gzip-compressed tar archive under a timestamped directory.
+ */
+func WriteArchive(w io.Writer, files []File) error {
+	gz := gzip.NewWriter(w)
+	archive := tar.NewWriter(gz)
+	now := time.Now().UTC()
+	dir := "support-bundle-" + now.Format("20060102T150405Z") + "/"
+
+	for _, file := range files {
+		var content bytes.Buffer
+		name := file.Name
+		if err := file.Collect(&content); err != nil {
+			content.Reset()
+			fmt.Fprintf(&content, "failed to collect %s: %v\n", file.Name, err)
+			name += ".error"
+		}
+
+		header := &tar.Header{Name: dir + name, Mode: 0o644, Size: int64(content.Len()), ModTime: now}
+		if err := archive.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+		}
+		if _, err := archive.Write(content.Bytes()); err != nil {
+			return fmt.Errorf("failed to write %s to bundle: %w", name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("failed to finish bundle: %w", err)
+	}
+	return gz.Close()
+}
+
+/**
+ * @description Serves a freshly collected bundle as a download.
+ */
+func Handler(files func() []File) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/gzip")
+		w.Header().Set("Content-Disposition", `attachment; filename="support-bundle.tar.gz"`)
+		if err := WriteArchive(w, files()); err != nil {
+			log.Printf("Support bundle download failed: %v", err)
+		}
+	}
+}
+
+/**
+ * @description Creates a file holding value encoded as indented JSON.
+ */
+func JSONFile(name string, value func() (any, error)) File {
+	return File{Name: name, Collect: func(w io.Writer) error {
+		v, err := value()
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}}
+}
+
+/**
+ * @description Creates a file with full stack traces of every goroutine.
+ */
+func GoroutineDump() File {
+	return File{Name: "goroutines.txt", Collect: func(w io.Writer) error {
+		return pprof.Lookup("goroutine").WriteTo(w, 2)
+	}}
+}
+
+/**
+ * @description Creates a file with the process environment, redacting sensitive variables
+ * and credentials embedded in URLs.
+ */
+func RedactedEnvironment() File {
+	return File{Name: "environment.txt", Collect: func(w io.Writer) error {
+		environment := os.Environ()
+		sort.Strings(environment)
+		for _, entry := range environment {
+			key, value, _ := strings.Cut(entry, "=")
+			fmt.Fprintf(w, "%s=%s\n", key, RedactValue(key, value))
+		}
+		return nil
+	}}
+}
+
+/**
+ * @description Creates a file with the output of an in-process handler such as the metrics endpoint.
+ */
+func HandlerSnapshot(name string, handler http.Handler) File {
+	return File{Name: name, Collect: func(w io.Writer) error {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		if recorder.Code != http.StatusOK {
+			return fmt.Errorf("handler returned status %d", recorder.Code)
+		}
+		_, err := recorder.Body.WriteTo(w)
+		return err
+	}}
+}
+
+/**
+ * @description Redacts a configuration value whose key names a secret, and URL credentials otherwise.
+ */
+func RedactValue(key, value string) string {
+	if config.IsSensitive(key) && value != "" {
+		return config.Redacted
+	}
+	return urlCredentials.ReplaceAllString(value, "://"+config.Redacted+"@")
+}
@@ -0,0 +1,90 @@
+/**
+ * @fileoverview Fake gRPC health service for exercising gRPC dependency checks.
+ * Implements grpc.health.v1.Health with per-service statuses and an optional
+ * forced error code, switchable at runtime to simulate failing upstreams.
+ */
+
+package testsupport
+
+import (
+	"context"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// FakeGRPCHealthServer serves the standard gRPC health protocol with controllable answers
+type FakeGRPCHealthServer struct {
+	server   *grpc.Server
+	listener net.Listener
+	health   *health.Server
+
+	mu        sync.RWMutex
+	errorCode codes.Code
+}
+
+/**
+ * @description Starts a gRPC server on addr (use "127.0.0.1:0" for a random port)
+ * whose overall ("") service reports SERVING.
+ */
+func NewFakeGRPCHealthServer(addr string) (*FakeGRPCHealthServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	fake := &FakeGRPCHealthServer{listener: listener, health: health.NewServer(), errorCode: codes.OK}
+	fake.server = grpc.NewServer(grpc.UnaryInterceptor(fake.failingInterceptor))
+	healthpb.RegisterHealthServer(fake.server, fake.health)
+	fake.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	go fake.server.Serve(listener)
+	return fake, nil
+}
+
+/**
+ * @description Returns the server's address in host:port form.
+ */
+func (f *FakeGRPCHealthServer) Addr() string {
+	return f.listener.Addr().String()
+}
+
+/**
+ * @description Sets the status reported for service ("" is the overall server status).
+ */
+func (f *FakeGRPCHealthServer) SetStatus(service string, serving healthpb.HealthCheckResponse_ServingStatus) {
+	f.health.SetServingStatus(service, serving)
+}
+
+/**
+ * @description Makes every unary call fail with code; codes.OK restores normal behavior.
+ */
+func (f *FakeGRPCHealthServer) SetError(code codes.Code) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errorCode = code
+}
+
+/**
+ * @description Stops the server immediately, closing open connections.
+ */
+func (f *FakeGRPCHealthServer) Close() {
+	f.server.Stop()
+}
+
+// failingInterceptor returns the configured error instead of calling the handler
+func (f *FakeGRPCHealthServer) failingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	f.mu.RLock()
+	code := f.errorCode
+	f.mu.RUnlock()
+
+	if code != codes.OK {
+		return nil, status.Errorf(code, "fake failure for %s", info.FullMethod)
+	}
+	return handler(ctx, req)
+}
@@ -0,0 +1,102 @@
+/**
+ * @fileoverview Slow HTTP endpoint fake for exercising HTTP checks and resilience middleware.
+ * Serves a configurable status and body after a configurable delay, adjustable at runtime.
+ */
+
+package testsupport
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// SlowHTTPServer serves every request with the configured delay, status, and body
+type SlowHTTPServer struct {
+	server   *http.Server
+	listener net.Listener
+
+	mu     sync.RWMutex
+	delay  time.Duration
+	status int
+	body   string
+}
+
+/**
+ * @description Starts an HTTP server on addr (use "127.0.0.1:0" for a random port).
+ */
+func NewSlowHTTPServer(addr string, delay time.Duration, status int, body string) (*SlowHTTPServer, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	slow := &SlowHTTPServer{listener: listener, delay: delay, status: status, body: body}
+	slow.server = &http.Server{Handler: http.HandlerFunc(slow.handle)}
+
+	go func() {
+		if err := slow.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			listener.Close()
+		}
+	}()
+	return slow, nil
+}
+
+/**
+ * @description Returns the base URL of the server.
+ */
+func (s *SlowHTTPServer) URL() string {
+	return "http://" + s.listener.Addr().String()
+}
+
+/**
+ * @description Changes the delay applied to subsequent requests.
+ */
+func (s *SlowHTTPServer) SetDelay(delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delay = delay
+}
+
+/**
+ * @description Changes the status and body returned by subsequent requests.
+ */
+func (s *SlowHTTPServer) SetResponse(status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.status, s.body = status, body
+}
+
+/**
+ * @description Shuts the server down, abandoning in-flight delayed requests.
+ */
+func (s *SlowHTTPServer) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.server.Shutdown(ctx); err != nil {
+		return s.server.Close()
+	}
+	return nil
+}
+
+// handle waits for the configured delay, then writes the configured response
+func (s *SlowHTTPServer) handle(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	delay, status, body := s.delay, s.status, s.body
+	s.mu.RUnlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-r.Context().Done():
+		return
+	}
+
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
@@ -0,0 +1,121 @@
+/**
+ * @fileoverview Flaky TCP listener fake for exercising connection-level health checks.
+ * Accepts connections but can be switched down or configured to drop a fraction
+ * of connections immediately, simulating an unreliable network dependency.
+ */
+
+package testsupport
+
+import (
+	"math/rand"
+	"net"
+	"sync"
+	"sync/atomic"
+)
+
+// FlakyTCPListener accepts TCP connections with configurable failure behavior
+type FlakyTCPListener struct {
+	listener net.Listener
+
+	mu          sync.Mutex
+	failureRate float64
+	down        atomic.Bool
+	wg          sync.WaitGroup
+	accepted    atomic.Int64
+	dropped     atomic.Int64
+}
+
+/**
+ * @description Starts a listener on addr (use "127.0.0.1:0" for a random port).
+ * failureRate is the probability in [0,1] that an accepted connection is closed immediately.
+ */
+func NewFlakyTCPListener(addr string, failureRate float64) (*FlakyTCPListener, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	flaky := &FlakyTCPListener{listener: listener, failureRate: failureRate}
+	flaky.wg.Add(1)
+	go flaky.serve()
+	return flaky, nil
+}
+
+/**
+ * @description Returns the listener's address in host:port form.
+ */
+func (f *FlakyTCPListener) Addr() string {
+	return f.listener.Addr().String()
+}
+
+/**
+ * @description Changes the probability that a new connection is dropped.
+ */
+func (f *FlakyTCPListener) SetFailureRate(rate float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failureRate = rate
+}
+
+/**
+ * @description Drops every new connection while down is true.
+ */
+func (f *FlakyTCPListener) SetDown(down bool) {
+	f.down.Store(down)
+}
+
+/**
+ * @description Returns the number of connections held open and dropped so far.
+ */
+func (f *FlakyTCPListener) Stats() (accepted, dropped int64) {
+	return f.accepted.Load(), f.dropped.Load()
+}
+
+/**
+ * @description Stops accepting connections and waits for the accept loop to exit.
+ */
+func (f *FlakyTCPListener) Close() error {
+	err := f.listener.Close()
+	f.wg.Wait()
+	return err
+}
+
+// serve accepts connections until the listener is closed
+func (f *FlakyTCPListener) serve() {
+	defer f.wg.Done()
+
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		f.mu.Lock()
+		drop := f.down.Load() || rand.Float64() < f.failureRate
+		f.mu.Unlock()
+
+		if drop {
+			f.dropped.Add(1)
+			if tcp, ok := conn.(*net.TCPConn); ok {
+				// Reset instead of a graceful close so clients observe a hard failure
+				tcp.SetLinger(0)
+			}
+			conn.Close()
+			continue
+		}
+
+		f.accepted.Add(1)
+		go drain(conn)
+	}
+}
+
+// drain discards data until the peer closes the connection
+func drain(conn net.Conn) {
+	defer conn.Close()
+	buf := make([]byte, 4096)
+	for {
+		if _, err := conn.Read(buf); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,204 @@
+/**
+ * @fileoverview Server-side TLS for HTTPS listeners.
+ * KeyPair serves a certificate and key that can be rotated on disk without a restart,
+ * and ServerTLSConfig applies the baseline every listener uses: TLS 1.2 or newer with
+ * forward-secret AEAD cipher suites only.
+ */
+
+package tlsutil
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// modernCipherSuites are the TLS 1.2 suites offered; TLS 1.3 suites are not configurable
+var modernCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+/**
+ * @description Parses a minimum TLS version name ("1.2" or "1.3"); empty means 1.2.
+ */
+func ParseMinVersion(name string) (uint16, error) {
+	switch name {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported minimum TLS version %q (expected 1.2 or 1.3)", name)
+	}
+}
+
+/**
+ * @description Returns a server TLS config requiring at least minVersion, offering only modern
+ * cipher suites, and presenting the certificate returned by getCertificate on every handshake.
+ */
+func ServerTLSConfig(minVersion uint16, getCertificate func(*tls.ClientHelloInfo) (*tls.Certificate, error)) *tls.Config {
+	return &tls.Config{
+		MinVersion:       minVersion,
+		CipherSuites:     modernCipherSuites,
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		GetCertificate:   getCertificate,
+	}
+}
+
+// KeyPair holds a server certificate and key that can be reloaded at runtime
+type KeyPair struct {
+	certPath string
+	keyPath  string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	chain    []*x509.Certificate
+	modTimes map[string]time.Time
+	onReload func(error)
+}
+
+/**
+ * @description Loads the certificate chain at certPath and its key at keyPath.
+ * Returns an error if the initial load fails.
+ */
+func NewKeyPair(certPath, keyPath string) (*KeyPair, error) {
+	pair := &KeyPair{certPath: certPath, keyPath: keyPath}
+	if err := pair.Reload(); err != nil {
+		return nil, err
+	}
+	return pair, nil
+}
+
+/**
+ * @description Registers a callback invoked after every reload attempt with its result.
+ */
+func (p *KeyPair) OnReload(callback func(error)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onReload = callback
+}
+
+/**
+ * @description Re-reads the certificate and key, keeping the previous pair if the new files are invalid.
+ */
+func (p *KeyPair) Reload() error {
+	err := p.reload()
+
+	p.mu.RLock()
+	callback := p.onReload
+	p.mu.RUnlock()
+	if callback != nil {
+		callback(err)
+	}
+	return err
+}
+
+// reload performs the load and swaps in the new pair on success
+func (p *KeyPair) reload() error {
+	cert, err := tls.LoadX509KeyPair(p.certPath, p.keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load server certificate %s: %w", p.certPath, err)
+	}
+	chain := make([]*x509.Certificate, 0, len(cert.Certificate))
+	for _, der := range cert.Certificate {
+		parsed, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("failed to parse server certificate %s: %w", p.certPath, err)
+		}
+		chain = append(chain, parsed)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cert = &cert
+	p.chain = chain
+	p.modTimes = modTimes(p.certPath, p.keyPath)
+	return nil
+}
+
+/**
+ * @description Returns the current certificate; suitable for tls.Config.GetCertificate.
+ */
+func (p *KeyPair) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.cert, nil
+}
+
+/**
+ * @description Returns the parsed certificate chain, leaf first.
+ */
+func (p *KeyPair) Certificates() []*x509.Certificate {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return append([]*x509.Certificate(nil), p.chain...)
+}
+
+/**
+ * @description Reloads on SIGHUP and whenever the certificate or key file changes, until ctx
+ * is cancelled. Reload errors keep the previous pair and are reported via OnReload.
+ */
+func (p *KeyPair) Watch(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			p.Reload()
+		case <-ticker.C:
+			if p.filesChanged() {
+				p.Reload()
+			}
+		}
+	}
+}
+
+// filesChanged reports whether either file's modification time differs from the last load
+func (p *KeyPair) filesChanged() bool {
+	current := modTimes(p.certPath, p.keyPath)
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for path, modTime := range current {
+		if !modTime.Equal(p.modTimes[path]) {
+			return true
+		}
+	}
+	return false
+}
+
+// modTimes stats each non-empty path
+func modTimes(paths ...string) map[string]time.Time {
+	times := make(map[string]time.Time, len(paths))
+	for _, path := range paths {
+		if path == "" {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil {
+			times[path] = info.ModTime()
+		}
+	}
+	return times
+}
@@ -0,0 +1,256 @@
+/**
+ * @fileoverview Struct-tag constraints for request validation.
+ * Fields declare rules in a `validate` tag, e.g. `validate:"required,min=1,max=64"`:
+ *   - required: the value must not be the zero value (use a pointer to accept an explicit zero)
+ *   - min=N, max=N: bounds numbers by value and strings, slices, and maps by length
+ *   - enum=a|b|c: the value must be one of the listed options
+ *   - format=NAME: strings must be an email, url, uuid, date, datetime, or duration
+ * Nested structs, pointers, and slices of structs are validated recursively.
+ */
+
+package validate
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// uuidPattern matches canonical UUIDs of any version
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// formats checks string values for each supported format name
+var formats = map[string]func(string) bool{
+	"email": func(s string) bool {
+		address, err := mail.ParseAddress(s)
+		return err == nil && address.Address == s
+	},
+	"url": func(s string) bool {
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != "" && u.Host != ""
+	},
+	"uuid": uuidPattern.MatchString,
+	"date": func(s string) bool {
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	},
+	"datetime": func(s string) bool {
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	},
+	"duration": func(s string) bool {
+		_, err := time.ParseDuration(s)
+		return err == nil
+	},
+}
+
+// rule is one parsed constraint from a validate tag
+type rule struct {
+	name  string
+	arg   string
+	bound float64
+	enum  []string
+}
+
+// field is a struct field with its JSON name and rules
+type field struct {
+	index int
+	name  string
+	rules []rule
+}
+
+// fieldCache holds the parsed fields for each struct type
+var fieldCache sync.Map
+
+/**
+ * @description Validates v, a struct or pointer to one, against its validate tags.
+ * Returns Errors listing every violation, or nil. Panics on malformed tags, which are
+ * programming errors.
+ */
+func Struct(v any) error {
+	var errs Errors
+	validateValue(reflect.ValueOf(v), "", nil, &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateValue applies rules to value and recurses into nested structs and slices
+func validateValue(value reflect.Value, path string, rules []rule, errs *Errors) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			if hasRule(rules, "required") {
+				*errs = append(*errs, FieldError{Field: path, Rule: "required", Message: "is required"})
+			}
+			return
+		}
+		value = value.Elem()
+	}
+
+	for _, r := range rules {
+		if message := check(r, value); message != "" {
+			*errs = append(*errs, FieldError{Field: path, Rule: r.name, Message: message})
+			if r.name == "required" {
+				return
+			}
+		}
+	}
+
+	switch value.Kind() {
+	case reflect.Struct:
+		for _, f := range structFields(value.Type()) {
+			validateValue(value.Field(f.index), joinPath(path, f.name), f.rules, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if element := value.Index(i); isStructLike(element.Type()) {
+				validateValue(element, fmt.Sprintf("%s[%d]", path, i), nil, errs)
+			}
+		}
+	}
+}
+
+// check returns a message describing how value violates r, or "" if it satisfies it
+func check(r rule, value reflect.Value) string {
+	switch r.name {
+	case "required":
+		if value.IsZero() {
+			return "is required"
+		}
+	case "min", "max":
+		size, unit, ok := measure(value)
+		if !ok {
+			return ""
+		}
+		if r.name == "min" && size < r.bound {
+			return "must be at least " + r.arg + unit
+		}
+		if r.name == "max" && size > r.bound {
+			return "must be at most " + r.arg + unit
+		}
+	case "enum":
+		if value.IsZero() {
+			return ""
+		}
+		if !slices.Contains(r.enum, fmt.Sprint(value.Interface())) {
+			return "must be one of " + strings.Join(r.enum, ", ")
+		}
+	case "format":
+		if value.Kind() == reflect.String && value.Len() > 0 && !formats[r.arg](value.String()) {
+			return "must be a valid " + r.arg
+		}
+	}
+	return ""
+}
+
+// measure returns the number compared by min and max: the value of numbers and the
+// length of strings, slices, and maps, with the unit used in messages
+func measure(value reflect.Value) (float64, string, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), "", true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), " characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), " items", true
+	default:
+		return 0, "", false
+	}
+}
+
+// structFields parses and caches the validated fields of a struct type
+func structFields(t reflect.Type) []field {
+	if cached, ok := fieldCache.Load(t); ok {
+		return cached.([]field)
+	}
+
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		structField := t.Field(i)
+		if !structField.IsExported() {
+			continue
+		}
+		name := structField.Name
+		if tag, _, _ := strings.Cut(structField.Tag.Get("json"), ","); tag == "-" {
+			continue
+		} else if tag != "" {
+			name = tag
+		}
+
+		rules := parseRules(t, structField)
+		if len(rules) == 0 && !isStructLike(structField.Type) {
+			continue
+		}
+		fields = append(fields, field{index: i, name: name, rules: rules})
+	}
+
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+// parseRules parses a field's validate tag, panicking on unknown rules or bad arguments
+func parseRules(t reflect.Type, structField reflect.StructField) []rule {
+	tag := structField.Tag.Get("validate")
+	if tag == "" {
+		return nil
+	}
+
+	var rules []rule
+	for _, part := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+		r := rule{name: name, arg: arg}
+		switch name {
+		case "required":
+		case "min", "max":
+			bound, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				panic(fmt.Sprintf("validate: %s.%s: %s needs a numeric argument, got %q", t.Name(), structField.Name, name, arg))
+			}
+			r.bound = bound
+		case "enum":
+			r.enum = strings.Split(arg, "|")
+		case "format":
+			if formats[arg] == nil {
+				panic(fmt.Sprintf("validate: %s.%s: unknown format %q", t.Name(), structField.Name, arg))
+			}
+		default:
+			panic(fmt.Sprintf("validate: %s.%s: unknown rule %q", t.Name(), structField.Name, name))
+		}
+		rules = append(rules, r)
+	}
+	return rules
+}
+
+// isStructLike reports whether values of t can contain nested validated fields
+func isStructLike(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{})
+}
+
+// hasRule reports whether rules contains the named rule
+func hasRule(rules []rule, name string) bool {
+	return slices.ContainsFunc(rules, func(r rule) bool { return r.name == name })
+}
+
+// joinPath appends a field name to a JSON path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
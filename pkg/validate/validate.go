@@ -0,0 +1,131 @@
+/**
+ * @fileoverview Request decoding and validation for JSON endpoints.
+ * Decodes a request body into a typed struct, rejecting unknown fields and trailing data,
+ * then enforces the constraints declared in `validate` struct tags. Every problem is
+ * reported per field, named as the client sent it, in a 400 problem details response.
+ */
+
+package validate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+)
+
+// FieldError describes one invalid field
+type FieldError struct {
+	// Field is the JSON path of the field, e.g. "items[0].name"; empty for the whole body
+	Field string `json:"field,omitempty"`
+	// Rule is the failed constraint, e.g. "required" or "max"
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// Errors lists every invalid field in a request
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		if fieldErr.Field == "" {
+			messages[i] = fieldErr.Message
+		} else {
+			messages[i] = fieldErr.Field + ": " + fieldErr.Message
+		}
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+/**
+ * @description Decodes the JSON request body into dst, a pointer to a struct, and validates it.
+ * Returns Errors for malformed bodies and constraint violations, or the body read error,
+ * such as *http.MaxBytesError when the body limit is exceeded.
+ */
+func Decode(r *http.Request, dst any) error {
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json")) {
+			return Errors{{Rule: "content_type", Message: fmt.Sprintf("Content-Type %q is not JSON", contentType)}}
+		}
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		return Errors{{Rule: "json", Message: "body must contain a single JSON value"}}
+	}
+	return Struct(dst)
+}
+
+/**
+ * @description Writes the response for an error returned by Decode or Struct: 413 when the
+ * body was too large, 400 with the field errors in an "errors" member for validation errors,
+ * and 400 otherwise.
+ */
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		apierror.Write(w, r, http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit))
+		return
+	}
+
+	var fieldErrs Errors
+	if errors.As(err, &fieldErrs) {
+		problem := apierror.New(http.StatusBadRequest, "invalid request")
+		problem.Errors = fieldErrs
+		problem.Write(w, r)
+		return
+	}
+	apierror.Write(w, r, http.StatusBadRequest, err.Error())
+}
+
+// decodeError converts a JSON decoding failure into field errors where possible
+func decodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var maxBytesErr *http.MaxBytesError
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return err
+	case errors.Is(err, io.EOF):
+		return Errors{{Rule: "json", Message: "body must not be empty"}}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return Errors{{Rule: "json", Message: "body contains truncated JSON"}}
+	case errors.As(err, &syntaxErr):
+		return Errors{{Rule: "json", Message: fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset)}}
+	case errors.As(err, &typeErr):
+		return Errors{{Field: typeErr.Field, Rule: "type", Message: "must be " + jsonType(typeErr.Type.Kind().String())}}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return Errors{{Field: field, Rule: "unknown", Message: "is not a known field"}}
+	default:
+		return Errors{{Rule: "json", Message: err.Error()}}
+	}
+}
+
+// jsonType names a Go kind as the JSON type a client should send
+func jsonType(kind string) string {
+	switch {
+	case strings.HasPrefix(kind, "int"), strings.HasPrefix(kind, "uint"), strings.HasPrefix(kind, "float"):
+		return "a number"
+	case kind == "string":
+		return "a string"
+	case kind == "bool":
+		return "a boolean"
+	case kind == "slice", kind == "array":
+		return "an array"
+	default:
+		return "an object"
+	}
+}
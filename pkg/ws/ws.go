@@ -0,0 +1,323 @@
+/**
+ * @fileoverview WebSocket upgrade helper and connection manager.
+ * Upgrades requests after checking their Origin, pings open connections so dead peers
+ * are detected, and closes every connection with a going-away status on shutdown.
+ * Connections opened under streams.Tracker middleware count reads and writes as
+ * activity and report the tracker's close reason to the client.
+ */
+
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coder/websocket"
+
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/apierror"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/id"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/jsoncase"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/metrics"
+	"github.com/ashleywang1/new-ai-project-tutorial/pkg/streams"
+)
+
+const (
+	// DefaultPingInterval is how often connections are pinged when Config leaves it zero
+	DefaultPingInterval = 30 * time.Second
+	// DefaultPongTimeout is how long a ping waits for its pong when Config leaves it zero
+	DefaultPongTimeout = 10 * time.Second
+	// DefaultMaxMessageBytes limits incoming messages when Config leaves it zero
+	DefaultMaxMessageBytes = 1 << 20
+)
+
+// ReasonShutdown is the close reason sent to clients during shutdown, matching the stream tracker's
+const ReasonShutdown = streams.ReasonShutdown
+
+// ErrClosed is returned by Accept once CloseAll has been called
+var ErrClosed = errors.New("ws: manager closed")
+
+// keepaliveFailures counts connections closed because a ping went unanswered
+var keepaliveFailures = metrics.NewCounter("websocket_keepalive_failures_total", "WebSocket connections closed after a ping went unanswered.")
+
+// Config configures a Manager
+type Config struct {
+	// AllowedOrigins lists origin host patterns (path.Match syntax) allowed besides the request's own host
+	AllowedOrigins []string
+	// PingInterval is how often connections are pinged; zero uses DefaultPingInterval
+	PingInterval time.Duration
+	// PongTimeout bounds the wait for each pong; zero uses DefaultPongTimeout
+	PongTimeout time.Duration
+	// MaxMessageBytes limits incoming messages; zero uses DefaultMaxMessageBytes
+	MaxMessageBytes int64
+}
+
+// Manager upgrades requests and tracks the open connections
+type Manager struct {
+	config Config
+
+	mu     sync.Mutex
+	conns  map[*Conn]struct{}
+	closed bool
+}
+
+// Conn is an accepted WebSocket connection
+type Conn struct {
+	ID string
+
+	conn    *websocket.Conn
+	manager *Manager
+	stream  *streams.Stream
+	cancel  context.CancelFunc
+	// done is closed once the connection is released
+	done     chan struct{}
+	released sync.Once
+}
+
+/**
+ * @description Creates a manager, filling zero settings with the package defaults.
+ */
+func NewManager(config Config) *Manager {
+	if config.PingInterval <= 0 {
+		config.PingInterval = DefaultPingInterval
+	}
+	if config.PongTimeout <= 0 {
+		config.PongTimeout = DefaultPongTimeout
+	}
+	if config.MaxMessageBytes <= 0 {
+		config.MaxMessageBytes = DefaultMaxMessageBytes
+	}
+	return &Manager{config: config, conns: make(map[*Conn]struct{})}
+}
+
+/**
+ * @description Returns a handler that upgrades each request and runs handle on the connection.
+ * The connection is closed when handle returns: normally on nil, with an internal error
+ * status otherwise. Handlers that
+ * never read must call CloseRead so pongs and close frames are still processed.
+ */
+func (m *Manager) Handler(handle func(ctx context.Context, conn *Conn) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, ctx, err := m.Accept(w, r)
+		if err != nil {
+			log.Printf("WebSocket upgrade failed for %s: %v", r.URL.Path, err)
+			return
+		}
+		defer m.release(conn)
+
+		err = handle(ctx, conn)
+		switch {
+		case websocket.CloseStatus(err) != -1 || ctx.Err() != nil:
+			// The client closed the connection, or it was already closed on our side
+		case err != nil:
+			conn.conn.Close(websocket.StatusInternalError, "internal error")
+		default:
+			conn.conn.Close(websocket.StatusNormalClosure, "")
+		}
+	})
+}
+
+/**
+ * @description Checks the origin and upgrades the request, returning the connection and a
+ * context cancelled when the connection fails its keepalive or is closed. When the request
+ * context ends, for example because the stream tracker reaped the connection, the client
+ * receives a going-away close with the tracker's reason first. On error the
+ * response has already been written. Callers must Close the connection when done.
+ */
+func (m *Manager) Accept(w http.ResponseWriter, r *http.Request) (*Conn, context.Context, error) {
+	m.mu.Lock()
+	closed := m.closed
+	m.mu.Unlock()
+	if closed {
+		apierror.Write(w, r, http.StatusServiceUnavailable, "server is shutting down")
+		return nil, nil, ErrClosed
+	}
+
+	// Hijacked connections keep the server's read and write deadlines; clear them
+	controller := http.NewResponseController(w)
+	if err := controller.SetReadDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return nil, nil, fmt.Errorf("failed to clear read deadline: %w", err)
+	}
+	if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return nil, nil, fmt.Errorf("failed to clear write deadline: %w", err)
+	}
+
+	c, err := websocket.Accept(w, r, &websocket.AcceptOptions{OriginPatterns: m.config.AllowedOrigins})
+	if err != nil {
+		return nil, nil, err
+	}
+	c.SetReadLimit(m.config.MaxMessageBytes)
+
+	// Cancelling a read's context drops the connection without a close frame, so the
+	// connection's context is detached and keepalive closes it gracefully instead
+	ctx, cancel := context.WithCancel(context.WithoutCancel(r.Context()))
+	conn := &Conn{ID: id.New(), conn: c, manager: m, stream: streams.FromContext(r.Context()), cancel: cancel, done: make(chan struct{})}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		cancel()
+		c.Close(websocket.StatusGoingAway, ReasonShutdown)
+		return nil, nil, ErrClosed
+	}
+	m.conns[conn] = struct{}{}
+	m.mu.Unlock()
+
+	go m.keepalive(ctx, r.Context(), conn)
+	return conn, ctx, nil
+}
+
+/**
+ * @description Closes every open connection with a going-away status and reason, rejects
+ * new upgrades, and waits until their handlers have returned or ctx expires.
+ */
+func (m *Manager) CloseAll(ctx context.Context, reason string) error {
+	m.mu.Lock()
+	m.closed = true
+	open := make([]*Conn, 0, len(m.conns))
+	for conn := range m.conns {
+		open = append(open, conn)
+	}
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, conn := range open {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			conn.conn.Close(websocket.StatusGoingAway, reason)
+			conn.cancel()
+			select {
+			case <-conn.done:
+			case <-ctx.Done():
+			}
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+/**
+ * @description Returns the number of open connections.
+ */
+func (m *Manager) Count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.conns)
+}
+
+// release forgets a connection and cancels its context
+func (m *Manager) release(conn *Conn) {
+	conn.released.Do(func() {
+		m.mu.Lock()
+		delete(m.conns, conn)
+		m.mu.Unlock()
+		conn.cancel()
+		conn.conn.CloseNow()
+		close(conn.done)
+	})
+}
+
+// keepalive pings the connection until ctx ends, closing it when a pong does not arrive in
+// time or when the request context ends
+func (m *Manager) keepalive(ctx, requestCtx context.Context, conn *Conn) {
+	ticker := time.NewTicker(m.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-requestCtx.Done():
+			reason := ReasonShutdown
+			if conn.stream != nil && conn.stream.CloseReason() != "" {
+				reason = conn.stream.CloseReason()
+			}
+			conn.conn.Close(websocket.StatusGoingAway, reason)
+			conn.cancel()
+			return
+		case <-ticker.C:
+			pingCtx, cancel := context.WithTimeout(ctx, m.config.PongTimeout)
+			err := conn.conn.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				if ctx.Err() == nil {
+					keepaliveFailures.Add(1)
+					conn.conn.Close(websocket.StatusPolicyViolation, "keepalive timeout")
+				}
+				conn.cancel()
+				return
+			}
+		}
+	}
+}
+
+/**
+ * @description Reads the next message, text or binary.
+ */
+func (c *Conn) Read(ctx context.Context) ([]byte, error) {
+	_, data, err := c.conn.Read(ctx)
+	if err == nil {
+		c.touch()
+	}
+	return data, err
+}
+
+/**
+ * @description Reads the next message and decodes it as JSON into v.
+ */
+func (c *Conn) ReadJSON(ctx context.Context, v any) error {
+	data, err := c.Read(ctx)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+/**
+ * @description Writes data as a text message.
+ */
+func (c *Conn) Write(ctx context.Context, data []byte) error {
+	c.touch()
+	return c.conn.Write(ctx, websocket.MessageText, data)
+}
+
+/**
+ * @description Writes v as a JSON text message using the configured field casing.
+ */
+func (c *Conn) WriteJSON(ctx context.Context, v any) error {
+	data, err := jsoncase.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	return c.Write(ctx, data)
+}
+
+/**
+ * @description Discards incoming messages in the background for write-only handlers,
+ * returning a context cancelled when the client closes the connection.
+ */
+func (c *Conn) CloseRead(ctx context.Context) context.Context {
+	return c.conn.CloseRead(ctx)
+}
+
+/**
+ * @description Closes the connection normally with reason and stops tracking it.
+ */
+func (c *Conn) Close(reason string) error {
+	err := c.conn.Close(websocket.StatusNormalClosure, reason)
+	c.manager.release(c)
+	return err
+}
+
+// touch records activity on the tracked stream, if any
+func (c *Conn) touch() {
+	if c.stream != nil {
+		c.stream.Touch()
+	}
+}